@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/caproven/termdict/dictionary"
 	"github.com/spf13/cobra"
 )
 
 type addOptions struct {
 	words   []string
+	lang    string
 	noCheck bool
 }
 
@@ -20,35 +22,48 @@ func NewAddCommand(cfg *Config) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "add word ...",
 		Short: "Add words to your vocab list",
-		Long: `Add words to your personal vocab list.
+		Long: `Add words to your personal vocab list. Words are checked concurrently to make sure they can be defined, and
+none are added if any can't be.
 
 Sample usage:
   termdict list add comeuppance
   termdict list add ameliorate entropy
-  termdict list add omg --no-check`,
+  termdict list add omg --no-check
+  termdict list add --lang de schnee`,
 		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			o.words = args
 
-			return o.run(cmd.Context(), cfg.Out, cfg.Vocab, cfg.Dict)
+			return o.run(cmd.Context(), cfg.Out, cmd.ErrOrStderr(), cfg.Vocab, cfg.Dict)
 		},
 	}
 
+	cmd.Flags().StringVar(&o.lang, "lang", cfg.language(), "language the words are in, such as en or de")
 	cmd.Flags().BoolVarP(&o.noCheck, "no-check", "n", false, "don't check that words can be defined before adding")
 
 	return cmd
 }
 
-func (o *addOptions) run(ctx context.Context, out io.Writer, v VocabRepo, d Definer) error {
+func (o *addOptions) run(ctx context.Context, out, errOut io.Writer, v VocabRepo, d Definer) error {
 	if !o.noCheck {
-		for _, word := range o.words {
-			if _, err := d.Define(ctx, word); err != nil {
-				return fmt.Errorf("define word %q to be added to list: %w", word, err)
+		var failed []dictionary.Result
+		for _, r := range dictionary.NewBatch(d, 0).DefineMany(ctx, o.words, o.lang) {
+			if r.Err != nil {
+				failed = append(failed, r)
 			}
 		}
+		if len(failed) == 1 {
+			return fmt.Errorf("define word %q to be added to list: %w", failed[0].Word, failed[0].Err)
+		}
+		if len(failed) > 1 {
+			for _, r := range failed {
+				_, _ = fmt.Fprintf(errOut, "Can't define %s: %s\n", r.Word, ErrorMessage(r.Err))
+			}
+			return fmt.Errorf("%d of %d words can't be defined; no words were added", len(failed), len(o.words))
+		}
 	}
 
-	added, err := v.AddWordsToList(ctx, o.words)
+	added, err := v.AddWordsToList(ctx, o.words, o.lang)
 	if err != nil {
 		return fmt.Errorf("add words to list: %w", err)
 	}
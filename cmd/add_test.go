@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"bytes"
 	"errors"
 	"os"
 	"testing"
@@ -11,10 +12,12 @@ import (
 )
 
 func TestAddCmd(t *testing.T) {
-	sampleDefs := []dictionary.Definition{
-		{PartOfSpeech: "verb", Meaning: "to foo"},
-		{PartOfSpeech: "verb", Meaning: "to bar"},
-	}
+	sampleEntries := []dictionary.Entry{{
+		Definitions: []dictionary.Definition{
+			{PartOfSpeech: "verb", Meaning: "to foo"},
+			{PartOfSpeech: "verb", Meaning: "to bar"},
+		},
+	}}
 	sampleErr := errors.New("failure")
 
 	t.Run("no words specified", func(t *testing.T) {
@@ -35,11 +38,11 @@ func TestAddCmd(t *testing.T) {
 	t.Run("failure adding words", func(t *testing.T) {
 		vocabRepo := &mockVocabRepo{}
 		defer vocabRepo.AssertExpectations(t)
-		vocabRepo.On("AddWordsToList", mock.Anything, mock.Anything).Return(nil, sampleErr).Once()
+		vocabRepo.On("AddWordsToList", mock.Anything, mock.Anything, "en").Return(nil, sampleErr).Once()
 
 		definer := &mockDefiner{}
 		defer definer.AssertExpectations(t)
-		definer.On("Define", mock.Anything, "foo").Return(sampleDefs, nil).Once()
+		definer.On("Define", mock.Anything, "foo", "en").Return(sampleEntries, nil).Once()
 
 		cmd := NewRootCmd(&Config{
 			Out:   os.Stdout,
@@ -58,7 +61,7 @@ func TestAddCmd(t *testing.T) {
 
 		definer := &mockDefiner{}
 		defer definer.AssertExpectations(t)
-		definer.On("Define", mock.Anything, "foo").Return(nil, sampleErr).Once()
+		definer.On("Define", mock.Anything, "foo", "en").Return(nil, sampleErr).Once()
 
 		cmd := NewRootCmd(&Config{
 			Out:   os.Stdout,
@@ -74,7 +77,7 @@ func TestAddCmd(t *testing.T) {
 	t.Run("add word that cannot be defined with no check", func(t *testing.T) {
 		vocabRepo := &mockVocabRepo{}
 		defer vocabRepo.AssertExpectations(t)
-		vocabRepo.On("AddWordsToList", mock.Anything, []string{"foo"}).Return(nil, nil).Once()
+		vocabRepo.On("AddWordsToList", mock.Anything, []string{"foo"}, "en").Return(nil, nil).Once()
 
 		// Shouldn't be called
 		definer := &mockDefiner{}
@@ -94,11 +97,11 @@ func TestAddCmd(t *testing.T) {
 	t.Run("add word that can be defined", func(t *testing.T) {
 		vocabRepo := &mockVocabRepo{}
 		defer vocabRepo.AssertExpectations(t)
-		vocabRepo.On("AddWordsToList", mock.Anything, []string{"fortitude"}).Return(nil, nil).Once()
+		vocabRepo.On("AddWordsToList", mock.Anything, []string{"fortitude"}, "en").Return(nil, nil).Once()
 
 		definer := &mockDefiner{}
 		defer definer.AssertExpectations(t)
-		definer.On("Define", mock.Anything, "fortitude").Return(sampleDefs, nil).Once()
+		definer.On("Define", mock.Anything, "fortitude", "en").Return(sampleEntries, nil).Once()
 
 		cmd := NewRootCmd(&Config{
 			Out:   os.Stdout,
@@ -114,12 +117,12 @@ func TestAddCmd(t *testing.T) {
 	t.Run("add multiple words", func(t *testing.T) {
 		vocabRepo := &mockVocabRepo{}
 		defer vocabRepo.AssertExpectations(t)
-		vocabRepo.On("AddWordsToList", mock.Anything, []string{"porter", "placate"}).Return(nil, nil).Once()
+		vocabRepo.On("AddWordsToList", mock.Anything, []string{"porter", "placate"}, "en").Return(nil, nil).Once()
 
 		definer := &mockDefiner{}
 		defer definer.AssertExpectations(t)
-		definer.On("Define", mock.Anything, "porter").Return(sampleDefs, nil).Once()
-		definer.On("Define", mock.Anything, "placate").Return(sampleDefs, nil).Once()
+		definer.On("Define", mock.Anything, "porter", "en").Return(sampleEntries, nil).Once()
+		definer.On("Define", mock.Anything, "placate", "en").Return(sampleEntries, nil).Once()
 
 		cmd := NewRootCmd(&Config{
 			Out:   os.Stdout,
@@ -138,8 +141,8 @@ func TestAddCmd(t *testing.T) {
 
 		definer := &mockDefiner{}
 		defer definer.AssertExpectations(t)
-		definer.On("Define", mock.Anything, "erudite").Return(sampleDefs, nil).Once()
-		definer.On("Define", mock.Anything, "sanguine").Return(nil, sampleErr).Once()
+		definer.On("Define", mock.Anything, "erudite", "en").Return(sampleEntries, nil).Once()
+		definer.On("Define", mock.Anything, "sanguine", "en").Return(nil, sampleErr).Once()
 
 		cmd := NewRootCmd(&Config{
 			Out:   os.Stdout,
@@ -151,4 +154,48 @@ func TestAddCmd(t *testing.T) {
 		err := cmd.Execute()
 		require.Error(t, err)
 	})
+
+	t.Run("add words in another language", func(t *testing.T) {
+		vocabRepo := &mockVocabRepo{}
+		defer vocabRepo.AssertExpectations(t)
+		vocabRepo.On("AddWordsToList", mock.Anything, []string{"schnee"}, "de").Return([]string{"schnee"}, nil).Once()
+
+		definer := &mockDefiner{}
+		defer definer.AssertExpectations(t)
+		definer.On("Define", mock.Anything, "schnee", "de").Return(sampleEntries, nil).Once()
+
+		cmd := NewRootCmd(&Config{
+			Out:   os.Stdout,
+			Vocab: vocabRepo,
+			Dict:  definer,
+		})
+		cmd.SetArgs([]string{"list", "add", "--lang", "de", "schnee"})
+
+		err := cmd.Execute()
+		require.NoError(t, err)
+	})
+
+	t.Run("words that cannot be defined are all reported", func(t *testing.T) {
+		vocabRepo := &mockVocabRepo{}
+		defer vocabRepo.AssertExpectations(t)
+
+		definer := &mockDefiner{}
+		defer definer.AssertExpectations(t)
+		definer.On("Define", mock.Anything, "foo", "en").Return(sampleEntries, nil).Once()
+		definer.On("Define", mock.Anything, "fooo", "en").Return(nil, &dictionary.LookupError{Word: "fooo", Err: dictionary.ErrNotFound}).Once()
+		definer.On("Define", mock.Anything, "barr", "en").Return(nil, &dictionary.LookupError{Word: "barr", Err: dictionary.ErrNotFound}).Once()
+
+		var errOut bytes.Buffer
+		cmd := NewRootCmd(&Config{
+			Out:   os.Stdout,
+			Vocab: vocabRepo,
+			Dict:  definer,
+		})
+		cmd.SetErr(&errOut)
+		cmd.SetArgs([]string{"list", "add", "foo", "fooo", "barr"})
+
+		require.EqualError(t, cmd.Execute(), "2 of 3 words can't be defined; no words were added")
+		require.Contains(t, errOut.String(), `Can't define fooo: no definitions found for "fooo"`)
+		require.Contains(t, errOut.String(), `Can't define barr: no definitions found for "barr"`)
+	})
 }
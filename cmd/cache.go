@@ -0,0 +1,510 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/caproven/termdict/dictionary"
+	"github.com/caproven/termdict/vocab"
+	"github.com/spf13/cobra"
+)
+
+type cacheRefreshOptions struct {
+	words []string
+	lang  string
+	all   bool
+	stale bool
+}
+
+// refreshTarget is a word to define again with the given providers
+type refreshTarget struct {
+	word      string
+	lang      string
+	providers []string
+	merge     bool
+}
+
+// NewCacheCommand constructs the cache command
+func NewCacheCommand(cfg *Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the cache of defined words",
+		Long: `Manage the cache of words defined by dictionary providers.
+
+Cached words are defined again once they're older than the cache TTL, which defaults to 30 days and can be set with
+the cache_ttl setting, e.g. "cache_ttl": "90d". Words of the offline dictionary aren't part of the cache.`,
+	}
+
+	cmd.AddCommand(NewCacheStatsCommand(cfg))
+	cmd.AddCommand(NewCacheListCommand(cfg))
+	cmd.AddCommand(NewCacheShowCommand(cfg))
+	cmd.AddCommand(NewCacheRemoveCommand(cfg))
+	cmd.AddCommand(NewCachePurgeCommand(cfg))
+	cmd.AddCommand(NewCacheVacuumCommand(cfg))
+	cmd.AddCommand(NewCacheRefreshCommand(cfg))
+	cmd.AddCommand(NewCacheWarmCommand(cfg))
+	cmd.AddCommand(NewCacheExportCommand(cfg))
+	cmd.AddCommand(NewCacheImportCommand(cfg))
+
+	return cmd
+}
+
+// NewCacheStatsCommand constructs the cache stats command
+func NewCacheStatsCommand(cfg *Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "stats",
+		Short: "Show statistics about the cache",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			c, err := wordCache(cfg)
+			if err != nil {
+				return err
+			}
+			stats, err := c.CacheStats(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("get cache stats: %w", err)
+			}
+			printCacheStats(cfg.Out, stats)
+			return nil
+		},
+	}
+}
+
+func printCacheStats(out io.Writer, stats dictionary.CacheStats) {
+	w := tabwriter.NewWriter(out, 0, 0, 1, ' ', 0)
+	_, _ = fmt.Fprintf(w, "Words:\t%d\n", stats.Words)
+	_, _ = fmt.Fprintf(w, "Definitions:\t%d\n", stats.Definitions)
+	_, _ = fmt.Fprintf(w, "Not found:\t%d\n", stats.NotFound)
+	_, _ = fmt.Fprintf(w, "Database size:\t%s\n", formatBytes(stats.SizeBytes))
+	if !stats.Oldest.FetchedAt.IsZero() {
+		_, _ = fmt.Fprintf(w, "Oldest:\t%s\n", describeCachedWord(stats.Oldest))
+		_, _ = fmt.Fprintf(w, "Newest:\t%s\n", describeCachedWord(stats.Newest))
+	}
+	_ = w.Flush()
+}
+
+// describeCachedWord summarizes a cached word on a single line
+func describeCachedWord(w dictionary.CachedWord) string {
+	return fmt.Sprintf("%s [%s] from %s, fetched %s", w.Word, w.Language, w.Provider, formatFetchedAt(w.FetchedAt))
+}
+
+func formatFetchedAt(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return t.Format(time.DateOnly)
+}
+
+// formatBytes formats a size in bytes with a binary unit, such as 1.5 MiB
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// NewCacheListCommand constructs the cache ls command
+func NewCacheListCommand(cfg *Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "ls",
+		Short: "List cached words",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			c, err := wordCache(cfg)
+			if err != nil {
+				return err
+			}
+			words, err := c.CachedWords(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("list cached words: %w", err)
+			}
+			if len(words) == 0 {
+				_, _ = fmt.Fprintln(cfg.Out, "no cached words")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(cfg.Out, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "WORD\tLANGUAGE\tPROVIDER\tFETCHED")
+			for _, word := range words {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", word.Word, word.Language, word.Provider, formatFetchedAt(word.FetchedAt))
+			}
+			return w.Flush()
+		},
+	}
+}
+
+type cacheWordsOptions struct {
+	lang string
+}
+
+// NewCacheShowCommand constructs the cache show command
+func NewCacheShowCommand(cfg *Config) *cobra.Command {
+	o := &cacheWordsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "show word",
+		Short: "Show the cached entries of a word",
+		Long: `Show the entries cached for a word by every provider, as stored.
+
+Sample usage:
+  termdict cache show serendipity
+  termdict cache show --lang de Gift`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := wordCache(cfg)
+			if err != nil {
+				return err
+			}
+			entries, err := c.CachedEntries(cmd.Context(), args[0], o.lang)
+			if err != nil {
+				return fmt.Errorf("get cached entries: %w", err)
+			}
+			if len(entries) == 0 {
+				return fmt.Errorf("%q isn't cached in language %q", args[0], o.lang)
+			}
+			return writeJSON(cfg.Out, entries)
+		},
+	}
+
+	cmd.Flags().StringVar(&o.lang, "lang", cfg.language(), "language of the word")
+
+	return cmd
+}
+
+// NewCacheRemoveCommand constructs the cache rm command
+func NewCacheRemoveCommand(cfg *Config) *cobra.Command {
+	o := &cacheWordsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "rm word ...",
+		Short: "Remove words from the cache",
+		Long: `Remove words from the cache of every provider, so they're defined again when next looked up.
+
+Sample usage:
+  termdict cache rm serendipity
+  termdict cache rm --lang de Gift Schnee`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := wordCache(cfg)
+			if err != nil {
+				return err
+			}
+			removed, err := c.RemoveCachedWords(cmd.Context(), args, o.lang)
+			if err != nil {
+				return fmt.Errorf("remove cached words: %w", err)
+			}
+			_, _ = fmt.Fprintf(cfg.Out, "Removed %d cached words\n", removed)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&o.lang, "lang", cfg.language(), "language of the words")
+
+	return cmd
+}
+
+// NewCachePurgeCommand constructs the cache purge command
+func NewCachePurgeCommand(cfg *Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "purge",
+		Short: "Remove every word from the cache",
+		Long:  `Remove every word from the cache of every provider. The offline dictionary and your vocab list are kept.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			c, err := wordCache(cfg)
+			if err != nil {
+				return err
+			}
+			if err := c.PurgeCache(cmd.Context()); err != nil {
+				return fmt.Errorf("purge cache: %w", err)
+			}
+			_, _ = fmt.Fprintln(cfg.Out, "Purged the cache")
+			return nil
+		},
+	}
+}
+
+// NewCacheVacuumCommand constructs the cache vacuum command
+func NewCacheVacuumCommand(cfg *Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "vacuum",
+		Short: "Compact the database",
+		Long:  `Compact the database, returning the space left by removed words to the filesystem.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			c, err := wordCache(cfg)
+			if err != nil {
+				return err
+			}
+			before, err := c.CacheStats(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("get cache stats: %w", err)
+			}
+			if err := c.Vacuum(cmd.Context()); err != nil {
+				return fmt.Errorf("compact database: %w", err)
+			}
+			after, err := c.CacheStats(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("get cache stats: %w", err)
+			}
+			_, _ = fmt.Fprintf(cfg.Out, "Compacted the database from %s to %s\n", formatBytes(before.SizeBytes), formatBytes(after.SizeBytes))
+			return nil
+		},
+	}
+}
+
+// wordCache returns the cache, or an error if it isn't available
+func wordCache(cfg *Config) (WordCache, error) {
+	if cfg.Cache == nil {
+		return nil, errors.New("cache not available")
+	}
+	return cfg.Cache, nil
+}
+
+// NewCacheRefreshCommand constructs the cache refresh command
+func NewCacheRefreshCommand(cfg *Config) *cobra.Command {
+	o := &cacheRefreshOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "refresh [word...]",
+		Short: "Define cached words again",
+		Long: `Define cached words again, replacing their cached definitions with the latest from their providers.
+
+Words given as arguments are defined with the chosen providers. With --all, every cached word is defined again by
+the provider which cached it; with --stale, only those older than the cache TTL are. Words of the offline dictionary
+aren't part of the cache, so are never refreshed.
+
+Sample usage:
+  termdict cache refresh serendipity
+  termdict cache refresh --lang de Gift
+  termdict cache refresh --stale`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.words = args
+			if err := o.validate(); err != nil {
+				return err
+			}
+			if cfg.NewDefiner == nil {
+				return errors.New("refreshing the cache isn't available")
+			}
+			if cfg.Offline {
+				return errors.New("can't refresh the cache with --offline")
+			}
+			return o.run(cmd.Context(), cfg, cmd.ErrOrStderr())
+		},
+	}
+
+	cmd.Flags().StringVar(&o.lang, "lang", cfg.language(), "language of the words to refresh")
+	cmd.Flags().BoolVar(&o.all, "all", false, "refresh every cached word")
+	cmd.Flags().BoolVar(&o.stale, "stale", false, "refresh cached words older than the cache TTL")
+	cmd.MarkFlagsMutuallyExclusive("all", "stale")
+
+	return cmd
+}
+
+func (o *cacheRefreshOptions) validate() error {
+	selectors := 0
+	for _, set := range []bool{len(o.words) > 0, o.all, o.stale} {
+		if set {
+			selectors++
+		}
+	}
+	if selectors != 1 {
+		return errors.New("give words to refresh, or one of --all or --stale")
+	}
+	return nil
+}
+
+func (o *cacheRefreshOptions) run(ctx context.Context, cfg *Config, errOut io.Writer) error {
+	targets, err := o.targets(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	// Definers are shared by targets with the same providers
+	definers := make(map[string]Definer)
+	definerErrs := make(map[string]error)
+	failed := 0
+	for _, t := range targets {
+		key := strings.Join(t.providers, ",")
+		d, ok := definers[key]
+		err := definerErrs[key]
+		if !ok && err == nil {
+			d, err = cfg.NewDefiner(DefinerOptions{Providers: t.providers, Merge: t.merge, Refresh: true, HTTP: cfg.HTTP})
+			definers[key], definerErrs[key] = d, err
+		}
+		if err == nil {
+			_, err = d.Define(ctx, t.word, t.lang)
+		}
+		if err != nil {
+			failed++
+			_, _ = fmt.Fprintf(errOut, "Failed to refresh %s: %s\n", t.word, ErrorMessage(err))
+		}
+	}
+
+	_, _ = fmt.Fprintf(cfg.Out, "Refreshed %d words\n", len(targets)-failed)
+	if failed > 0 {
+		return fmt.Errorf("failed to refresh %d of %d words", failed, len(targets))
+	}
+	return nil
+}
+
+// targets returns the words to refresh along with the providers to define them with
+func (o *cacheRefreshOptions) targets(ctx context.Context, cfg *Config) ([]refreshTarget, error) {
+	if len(o.words) > 0 {
+		targets := make([]refreshTarget, 0, len(o.words))
+		for _, word := range o.words {
+			targets = append(targets, refreshTarget{word: word, lang: o.lang, providers: cfg.Providers, merge: cfg.Merge})
+		}
+		return targets, nil
+	}
+
+	c, err := wordCache(cfg)
+	if err != nil {
+		return nil, err
+	}
+	cached, err := c.CachedWords(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list cached words: %w", err)
+	}
+	now := time.Now()
+	var targets []refreshTarget
+	for _, w := range cached {
+		if o.stale && !dictionary.Stale(w.FetchedAt, cfg.CacheTTL, now) {
+			continue
+		}
+		targets = append(targets, refreshTarget{word: w.Word, lang: w.Language, providers: []string{w.Provider}})
+	}
+	return targets, nil
+}
+
+type cacheWarmOptions struct {
+	rate        float64
+	concurrency int
+}
+
+// NewCacheWarmCommand constructs the cache warm command
+func NewCacheWarmCommand(cfg *Config) *cobra.Command {
+	o := &cacheWarmOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "warm",
+		Short: "Cache every word in your vocab list",
+		Long: `Define every word in your vocab list which isn't cached yet, or whose cached definitions have expired, so
+they can be looked up later without network access.
+
+Words are defined concurrently with the chosen providers, at most --rate words a second. Words which can't be
+defined are reported at the end.
+
+Sample usage:
+  termdict cache warm
+  termdict cache warm --rate 2
+  termdict --provider wiktionary cache warm`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if cfg.Offline {
+				return errors.New("can't warm the cache with --offline")
+			}
+			return o.run(cmd.Context(), cfg, cmd.ErrOrStderr())
+		},
+	}
+
+	cmd.Flags().Float64Var(&o.rate, "rate", 5, "most words to define a second; 0 for no limit")
+	cmd.Flags().IntVar(&o.concurrency, "concurrency", dictionary.DefaultConcurrency, "most words to define at once")
+
+	return cmd
+}
+
+func (o *cacheWarmOptions) run(ctx context.Context, cfg *Config, errOut io.Writer) error {
+	pending, listed, err := uncachedWords(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		_, _ = fmt.Fprintf(cfg.Out, "All %d words in your vocab list are already cached\n", listed)
+		return nil
+	}
+
+	// Words are batched by language, keeping the order of the list
+	var langs []string
+	byLang := make(map[string][]string)
+	for _, entry := range pending {
+		if _, ok := byLang[entry.Language]; !ok {
+			langs = append(langs, entry.Language)
+		}
+		byLang[entry.Language] = append(byLang[entry.Language], entry.Word)
+	}
+
+	batch := dictionary.NewBatch(dictionary.NewRateLimitedDefiner(cfg.Dict, o.rate), o.concurrency)
+	p := &progress{w: errOut, total: int64(len(pending)), action: "Warming", unit: "words"}
+	p.update(0, 0)
+	done := 0
+	var failed []dictionary.Result
+	for _, lang := range langs {
+		batch.DefineEach(ctx, byLang[lang], lang, func(_ int, r dictionary.Result) {
+			done++
+			if r.Err != nil {
+				failed = append(failed, r)
+			}
+			p.update(int64(done), done)
+		})
+	}
+	// Finish the progress line
+	_, _ = fmt.Fprintln(errOut)
+
+	_, _ = fmt.Fprintf(cfg.Out, "Cached %d of %d words\n", len(pending)-len(failed), len(pending))
+	if len(failed) == 0 {
+		return nil
+	}
+	for _, r := range failed {
+		_, _ = fmt.Fprintf(errOut, "Failed to define %s: %s\n", r.Word, ErrorMessage(r.Err))
+	}
+	return fmt.Errorf("failed to cache %d of %d words", len(failed), len(pending))
+}
+
+// uncachedWords returns the words of the vocab list without fresh cached definitions from any of the chosen providers,
+// along with the number of words in the list.
+func uncachedWords(ctx context.Context, cfg *Config) ([]vocab.Entry, int, error) {
+	list, err := cfg.Vocab.GetWordsInList(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list words: %w", err)
+	}
+	c, err := wordCache(cfg)
+	if err != nil {
+		return nil, 0, err
+	}
+	cached, err := c.CachedWords(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list cached words: %w", err)
+	}
+
+	type key struct {
+		word, lang string
+	}
+	fresh := make(map[key]bool)
+	now := time.Now()
+	for _, w := range cached {
+		if slices.Contains(cfg.Providers, w.Provider) && !dictionary.Stale(w.FetchedAt, cfg.CacheTTL, now) {
+			fresh[key{word: w.Word, lang: w.Language}] = true
+		}
+	}
+
+	var pending []vocab.Entry
+	for _, entry := range list {
+		if !fresh[key{word: strings.ToLower(entry.Word), lang: entry.Language}] {
+			pending = append(pending, entry)
+		}
+	}
+	return pending, len(list), nil
+}
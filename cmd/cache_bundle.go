@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/caproven/termdict/dictionary/bundle"
+	"github.com/spf13/cobra"
+)
+
+// NewCacheExportCommand constructs the cache export command
+func NewCacheExportCommand(cfg *Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "export [file]",
+		Short: "Export the cache as a bundle",
+		Long: `Export the words cached by every provider as a bundle, which can be imported into the cache on another machine.
+The bundle is written to the given file, or to standard output if there's none. Words of the offline dictionary and
+your own definitions aren't part of the cache, so aren't exported.
+
+Sample usage:
+  termdict cache export team-cache.jsonl
+  termdict cache export | gzip > team-cache.jsonl.gz`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			c, err := wordCache(cfg)
+			if err != nil {
+				return err
+			}
+
+			out := cfg.Out
+			if len(args) == 1 {
+				f, err := os.Create(args[0])
+				if err != nil {
+					return fmt.Errorf("create bundle: %w", err)
+				}
+				defer func() {
+					err = errors.Join(err, f.Close())
+				}()
+				out = f
+			}
+
+			n, err := exportCache(cmd.Context(), out, c)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d words\n", n)
+			return nil
+		},
+	}
+}
+
+// exportCache writes the cache to w as a bundle, returning the number of words written
+func exportCache(ctx context.Context, w io.Writer, c WordCache) (int, error) {
+	bw, err := bundle.NewWriter(w)
+	if err != nil {
+		return 0, fmt.Errorf("write bundle: %w", err)
+	}
+	n := 0
+	err = c.ExportCache(ctx, func(word bundle.Word) error {
+		n++
+		return bw.Write(word)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("export cache: %w", err)
+	}
+	return n, nil
+}
+
+type cacheImportOptions struct {
+	onConflict string
+}
+
+// NewCacheImportCommand constructs the cache import command
+func NewCacheImportCommand(cfg *Config) *cobra.Command {
+	o := &cacheImportOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "import [file]",
+		Short: "Import a cache bundle",
+		Long: `Import a bundle exported with "termdict cache export" into the cache. The bundle is read from the given file,
+or from standard input if there's none. Imported words keep the time they were fetched, so expire as they would have
+on the machine they were exported from.
+
+Words already cached by the same provider are handled by --on-conflict:
+  keep-newer  keep whichever was fetched most recently
+  overwrite   replace cached words with those of the bundle
+  skip        keep cached words
+
+Words are imported in batches of 1000, each in a transaction of its own. If the bundle turns out to be malformed part
+way through, the words of the batches before are kept.
+
+Sample usage:
+  termdict cache import team-cache.jsonl
+  gunzip -c team-cache.jsonl.gz | termdict cache import --on-conflict overwrite`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			policy, err := bundle.ParseConflictPolicy(o.onConflict)
+			if err != nil {
+				return err
+			}
+			c, err := wordCache(cfg)
+			if err != nil {
+				return err
+			}
+
+			in := cmd.InOrStdin()
+			if len(args) == 1 {
+				f, err := os.Open(args[0])
+				if err != nil {
+					return fmt.Errorf("open bundle: %w", err)
+				}
+				defer func() {
+					err = errors.Join(err, f.Close())
+				}()
+				in = f
+			}
+
+			return o.run(cmd.Context(), cfg.Out, in, c, policy)
+		},
+	}
+
+	cmd.Flags().StringVar(&o.onConflict, "on-conflict", string(bundle.KeepNewer), "what to do with words already cached; one of keep-newer, overwrite, skip")
+
+	return cmd
+}
+
+func (o *cacheImportOptions) run(ctx context.Context, out io.Writer, in io.Reader, c WordCache, policy bundle.ConflictPolicy) error {
+	// Words are imported in batches, so bundles needn't fit in memory and each transaction stays small
+	var batch []bundle.Word
+	var read, imported int
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		n, err := c.ImportCache(ctx, batch, policy)
+		if err != nil {
+			return err
+		}
+		imported += n
+		batch = batch[:0]
+		return nil
+	}
+
+	var importErr error
+	readErr := bundle.Read(in, func(w bundle.Word) error {
+		read++
+		batch = append(batch, w)
+		if len(batch) < importBatchSize {
+			return nil
+		}
+		importErr = flush()
+		return importErr
+	})
+	if readErr == nil {
+		importErr = flush()
+	}
+	if importErr != nil {
+		return fmt.Errorf("import cache: %w", importErr)
+	}
+	if readErr != nil {
+		return fmt.Errorf("read bundle: %w", readErr)
+	}
+	_, _ = fmt.Fprintf(out, "Imported %d of %d words\n", imported, read)
+	return nil
+}
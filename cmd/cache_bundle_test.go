@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/caproven/termdict/dictionary"
+	"github.com/caproven/termdict/dictionary/bundle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheExportImportCmd(t *testing.T) {
+	words := []bundle.Word{
+		{
+			Word:      "snow",
+			Language:  "en",
+			Provider:  "freedictionary",
+			FetchedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			Entries:   []dictionary.Entry{{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "Frozen rain."}}}},
+		},
+		{
+			Word:     "gift",
+			Language: "de",
+			Provider: "wiktionary",
+			Entries:  []dictionary.Entry{{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "poison"}}}},
+		},
+	}
+
+	t.Run("round trip through standard streams", func(t *testing.T) {
+		source := &mockWordCache{}
+		defer source.AssertExpectations(t)
+		source.On("ExportCache", mock.Anything, mock.Anything).Return(words, nil).Once()
+
+		var exported, errOut bytes.Buffer
+		cmd := NewRootCmd(&Config{Out: &exported, Cache: source})
+		cmd.SetErr(&errOut)
+		cmd.SetArgs([]string{"cache", "export"})
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "Exported 2 words\n", errOut.String())
+
+		target := &mockWordCache{}
+		defer target.AssertExpectations(t)
+		target.On("ImportCache", mock.Anything, words, bundle.KeepNewer).Return(1, nil).Once()
+
+		var out bytes.Buffer
+		cmd = NewRootCmd(&Config{Out: &out, Cache: target})
+		cmd.SetIn(&exported)
+		cmd.SetArgs([]string{"cache", "import"})
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "Imported 1 of 2 words\n", out.String())
+	})
+
+	t.Run("round trip through a file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "bundle.jsonl")
+		source := &mockWordCache{}
+		defer source.AssertExpectations(t)
+		source.On("ExportCache", mock.Anything, mock.Anything).Return(words, nil).Once()
+
+		var out bytes.Buffer
+		cmd := NewRootCmd(&Config{Out: &out, Cache: source})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"cache", "export", path})
+		require.NoError(t, cmd.Execute())
+		assert.Empty(t, out.String())
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, 3, strings.Count(string(data), "\n"), "header and a line per word")
+
+		target := &mockWordCache{}
+		defer target.AssertExpectations(t)
+		target.On("ImportCache", mock.Anything, words, bundle.Skip).Return(2, nil).Once()
+
+		cmd = NewRootCmd(&Config{Out: &out, Cache: target})
+		cmd.SetArgs([]string{"cache", "import", "--on-conflict", "skip", path})
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "Imported 2 of 2 words\n", out.String())
+	})
+
+	t.Run("imported in batches", func(t *testing.T) {
+		var b bytes.Buffer
+		w, err := bundle.NewWriter(&b)
+		require.NoError(t, err)
+		for i := range importBatchSize + 1 {
+			word := words[0]
+			word.Word = fmt.Sprintf("snow%d", i)
+			require.NoError(t, w.Write(word))
+		}
+
+		target := &mockWordCache{}
+		defer target.AssertExpectations(t)
+		target.On("ImportCache", mock.Anything, mock.MatchedBy(func(ws []bundle.Word) bool {
+			return len(ws) == importBatchSize
+		}), bundle.KeepNewer).Return(importBatchSize-1, nil).Once()
+		target.On("ImportCache", mock.Anything, mock.MatchedBy(func(ws []bundle.Word) bool {
+			return len(ws) == 1 && ws[0].Word == fmt.Sprintf("snow%d", importBatchSize)
+		}), bundle.KeepNewer).Return(1, nil).Once()
+
+		var out bytes.Buffer
+		cmd := NewRootCmd(&Config{Out: &out, Cache: target})
+		cmd.SetIn(&b)
+		cmd.SetArgs([]string{"cache", "import"})
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, fmt.Sprintf("Imported %d of %d words\n", importBatchSize, importBatchSize+1), out.String())
+	})
+
+	t.Run("malformed bundle keeps the batches before", func(t *testing.T) {
+		var b bytes.Buffer
+		w, err := bundle.NewWriter(&b)
+		require.NoError(t, err)
+		for i := range importBatchSize {
+			word := words[0]
+			word.Word = fmt.Sprintf("snow%d", i)
+			require.NoError(t, w.Write(word))
+		}
+		b.WriteString("{\"word\":\n")
+
+		target := &mockWordCache{}
+		defer target.AssertExpectations(t)
+		target.On("ImportCache", mock.Anything, mock.Anything, bundle.KeepNewer).Return(importBatchSize, nil).Once()
+
+		cmd := NewRootCmd(&Config{Out: &bytes.Buffer{}, Cache: target})
+		cmd.SetIn(&b)
+		cmd.SetArgs([]string{"cache", "import"})
+		require.ErrorContains(t, cmd.Execute(), "read bundle")
+	})
+
+	t.Run("unknown conflict policy", func(t *testing.T) {
+		cmd := NewRootCmd(&Config{Out: &bytes.Buffer{}, Cache: &mockWordCache{}})
+		cmd.SetIn(strings.NewReader(""))
+		cmd.SetArgs([]string{"cache", "import", "--on-conflict", "newest"})
+
+		require.Error(t, cmd.Execute())
+	})
+
+	t.Run("not a bundle", func(t *testing.T) {
+		cmd := NewRootCmd(&Config{Out: &bytes.Buffer{}, Cache: &mockWordCache{}})
+		cmd.SetIn(strings.NewReader(`{"id":"01J3XYZ1","type":"add","word":"snow","timestamp":100}` + "\n"))
+		cmd.SetArgs([]string{"cache", "import"})
+
+		require.ErrorContains(t, cmd.Execute(), "not a cache bundle")
+	})
+}
@@ -0,0 +1,395 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/caproven/termdict/dictionary"
+	"github.com/caproven/termdict/vocab"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheRefreshCmd(t *testing.T) {
+	entries := []dictionary.Entry{{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "def 1"}}}}
+	cached := []dictionary.CachedWord{
+		{Word: "gift", Language: "de", Provider: "freedictionary", FetchedAt: time.Now().Add(-48 * time.Hour)},
+		{Word: "snow", Language: "en", Provider: "freedictionary", FetchedAt: time.Now()},
+		{Word: "rain", Language: "en", Provider: "wiktionary"},
+	}
+
+	// newDefiners records the options each Definer was created with
+	newDefiners := func(d Definer, got *[]DefinerOptions) func(DefinerOptions) (Definer, error) {
+		return func(opts DefinerOptions) (Definer, error) {
+			*got = append(*got, opts)
+			return d, nil
+		}
+	}
+
+	t.Run("words", func(t *testing.T) {
+		definer := &mockDefiner{}
+		defer definer.AssertExpectations(t)
+		definer.On("Define", mock.Anything, "snow", "en").Return(entries, nil).Once()
+		definer.On("Define", mock.Anything, "rain", "en").Return(entries, nil).Once()
+
+		var got []DefinerOptions
+		var out bytes.Buffer
+		cmd := NewRootCmd(&Config{
+			Out:        &out,
+			Providers:  []string{"freedictionary"},
+			HTTP:       dictionary.HTTPOptions{Timeout: 10 * time.Second, Retries: 3, UserAgent: "termdict"},
+			NewDefiner: newDefiners(definer, &got),
+		})
+		cmd.SetArgs([]string{"cache", "refresh", "--provider", "offline,wiktionary", "--timeout", "2s", "snow", "rain"})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "Refreshed 2 words\n", out.String())
+		assert.Contains(t, got, DefinerOptions{
+			Providers: []string{"offline", "wiktionary"},
+			Refresh:   true,
+			HTTP:      dictionary.HTTPOptions{Timeout: 2 * time.Second, Retries: 3, UserAgent: "termdict"},
+		})
+	})
+
+	t.Run("all", func(t *testing.T) {
+		cache := &mockWordCache{}
+		defer cache.AssertExpectations(t)
+		cache.On("CachedWords", mock.Anything).Return(cached, nil).Once()
+		definer := &mockDefiner{}
+		defer definer.AssertExpectations(t)
+		definer.On("Define", mock.Anything, "gift", "de").Return(entries, nil).Once()
+		definer.On("Define", mock.Anything, "snow", "en").Return(entries, nil).Once()
+		definer.On("Define", mock.Anything, "rain", "en").Return(entries, nil).Once()
+
+		var got []DefinerOptions
+		var out bytes.Buffer
+		httpOpts := dictionary.HTTPOptions{Timeout: 10 * time.Second, Retries: 3, Proxy: "http://proxy:3128"}
+		cmd := NewRootCmd(&Config{Out: &out, Cache: cache, HTTP: httpOpts, NewDefiner: newDefiners(definer, &got)})
+		cmd.SetArgs([]string{"cache", "refresh", "--all"})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "Refreshed 3 words\n", out.String())
+		// Words are refreshed by the provider which cached them
+		assert.Equal(t, []DefinerOptions{
+			{HTTP: httpOpts},
+			{Providers: []string{"freedictionary"}, Refresh: true, HTTP: httpOpts},
+			{Providers: []string{"wiktionary"}, Refresh: true, HTTP: httpOpts},
+		}, got)
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		cache := &mockWordCache{}
+		defer cache.AssertExpectations(t)
+		cache.On("CachedWords", mock.Anything).Return(cached, nil).Once()
+		definer := &mockDefiner{}
+		defer definer.AssertExpectations(t)
+		definer.On("Define", mock.Anything, "gift", "de").Return(entries, nil).Once()
+		definer.On("Define", mock.Anything, "rain", "en").Return(entries, nil).Once()
+
+		var got []DefinerOptions
+		var out bytes.Buffer
+		cmd := NewRootCmd(&Config{Out: &out, Cache: cache, CacheTTL: 24 * time.Hour, NewDefiner: newDefiners(definer, &got)})
+		cmd.SetArgs([]string{"cache", "refresh", "--stale"})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "Refreshed 2 words\n", out.String())
+	})
+
+	t.Run("failures are reported", func(t *testing.T) {
+		definer := &mockDefiner{}
+		defer definer.AssertExpectations(t)
+		definer.On("Define", mock.Anything, "snow", "en").Return(entries, nil).Once()
+		definer.On("Define", mock.Anything, "snoww", "en").Return(nil, &dictionary.LookupError{Word: "snoww", Err: dictionary.ErrNotFound}).Once()
+
+		var got []DefinerOptions
+		var out, errOut bytes.Buffer
+		cmd := NewRootCmd(&Config{Out: &out, NewDefiner: newDefiners(definer, &got)})
+		cmd.SetErr(&errOut)
+		cmd.SetArgs([]string{"cache", "refresh", "snow", "snoww"})
+
+		assert.EqualError(t, cmd.Execute(), "failed to refresh 1 of 2 words")
+		assert.Equal(t, "Refreshed 1 words\n", out.String())
+		assert.Contains(t, errOut.String(), `Failed to refresh snoww: no definitions found for "snoww"`)
+	})
+
+	t.Run("invalid selection", func(t *testing.T) {
+		for name, args := range map[string][]string{
+			"nothing":         {"cache", "refresh"},
+			"words and all":   {"cache", "refresh", "--all", "snow"},
+			"all and stale":   {"cache", "refresh", "--all", "--stale"},
+			"words and stale": {"cache", "refresh", "--stale", "snow"},
+			"offline":         {"cache", "refresh", "--offline", "snow"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				cmd := NewRootCmd(&Config{Out: &bytes.Buffer{}, NewDefiner: func(DefinerOptions) (Definer, error) {
+					return &mockDefiner{}, nil
+				}})
+				cmd.SetErr(&bytes.Buffer{})
+				cmd.SetArgs(args)
+
+				assert.Error(t, cmd.Execute())
+			})
+		}
+	})
+}
+
+func TestCacheStatsCmd(t *testing.T) {
+	cache := &mockWordCache{}
+	defer cache.AssertExpectations(t)
+	cache.On("CacheStats", mock.Anything).Return(dictionary.CacheStats{
+		Words:       3,
+		Definitions: 12,
+		NotFound:    1,
+		SizeBytes:   1536,
+		Oldest:      dictionary.CachedWord{Word: "rain", Language: "en", Provider: "freedictionary", FetchedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local)},
+		Newest:      dictionary.CachedWord{Word: "gift", Language: "de", Provider: "wiktionary", FetchedAt: time.Date(2024, 3, 4, 0, 0, 0, 0, time.Local)},
+	}, nil).Once()
+
+	var out bytes.Buffer
+	cmd := NewRootCmd(&Config{Out: &out, Cache: cache})
+	cmd.SetArgs([]string{"cache", "stats"})
+
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, `Words:         3
+Definitions:   12
+Not found:     1
+Database size: 1.5 KiB
+Oldest:        rain [en] from freedictionary, fetched 2024-01-02
+Newest:        gift [de] from wiktionary, fetched 2024-03-04
+`, out.String())
+}
+
+func TestCacheListCmd(t *testing.T) {
+	t.Run("cached words", func(t *testing.T) {
+		cache := &mockWordCache{}
+		defer cache.AssertExpectations(t)
+		cache.On("CachedWords", mock.Anything).Return([]dictionary.CachedWord{
+			{Word: "gift", Language: "de", Provider: "freedictionary", FetchedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local)},
+			{Word: "serendipity", Language: "en", Provider: "wiktionary"},
+		}, nil).Once()
+
+		var out bytes.Buffer
+		cmd := NewRootCmd(&Config{Out: &out, Cache: cache})
+		cmd.SetArgs([]string{"cache", "ls"})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, `WORD         LANGUAGE  PROVIDER        FETCHED
+gift         de        freedictionary  2024-01-02
+serendipity  en        wiktionary      unknown
+`, out.String())
+	})
+
+	t.Run("empty cache", func(t *testing.T) {
+		cache := &mockWordCache{}
+		defer cache.AssertExpectations(t)
+		cache.On("CachedWords", mock.Anything).Return(nil, nil).Once()
+
+		var out bytes.Buffer
+		cmd := NewRootCmd(&Config{Out: &out, Cache: cache})
+		cmd.SetArgs([]string{"cache", "ls"})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "no cached words\n", out.String())
+	})
+}
+
+func TestCacheShowCmd(t *testing.T) {
+	t.Run("cached word", func(t *testing.T) {
+		cache := &mockWordCache{}
+		defer cache.AssertExpectations(t)
+		cache.On("CachedEntries", mock.Anything, "gift", "de").Return([]dictionary.Entry{{
+			Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "poison"}},
+			Provider:    "wiktionary",
+		}}, nil).Once()
+
+		var out bytes.Buffer
+		cmd := NewRootCmd(&Config{Out: &out, Cache: cache})
+		cmd.SetArgs([]string{"cache", "show", "--lang", "de", "gift"})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, `[
+	{
+		"Definitions": [
+			{
+				"PartOfSpeech": "noun",
+				"Meaning": "poison"
+			}
+		],
+		"Provider": "wiktionary"
+	}
+]
+`, out.String())
+	})
+
+	t.Run("word not cached", func(t *testing.T) {
+		cache := &mockWordCache{}
+		defer cache.AssertExpectations(t)
+		cache.On("CachedEntries", mock.Anything, "gift", "en").Return(nil, nil).Once()
+
+		cmd := NewRootCmd(&Config{Out: &bytes.Buffer{}, Cache: cache})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"cache", "show", "gift"})
+
+		assert.EqualError(t, cmd.Execute(), `"gift" isn't cached in language "en"`)
+	})
+}
+
+func TestCacheRemoveCmd(t *testing.T) {
+	cache := &mockWordCache{}
+	defer cache.AssertExpectations(t)
+	cache.On("RemoveCachedWords", mock.Anything, []string{"snow", "rain"}, "en").Return(2, nil).Once()
+
+	var out bytes.Buffer
+	cmd := NewRootCmd(&Config{Out: &out, Cache: cache})
+	cmd.SetArgs([]string{"cache", "rm", "snow", "rain"})
+
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "Removed 2 cached words\n", out.String())
+}
+
+func TestCachePurgeCmd(t *testing.T) {
+	cache := &mockWordCache{}
+	defer cache.AssertExpectations(t)
+	cache.On("PurgeCache", mock.Anything).Return(nil).Once()
+
+	var out bytes.Buffer
+	cmd := NewRootCmd(&Config{Out: &out, Cache: cache})
+	cmd.SetArgs([]string{"cache", "purge"})
+
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "Purged the cache\n", out.String())
+}
+
+func TestCacheVacuumCmd(t *testing.T) {
+	cache := &mockWordCache{}
+	defer cache.AssertExpectations(t)
+	cache.On("CacheStats", mock.Anything).Return(dictionary.CacheStats{SizeBytes: 3 << 20}, nil).Once()
+	cache.On("Vacuum", mock.Anything).Return(nil).Once()
+	cache.On("CacheStats", mock.Anything).Return(dictionary.CacheStats{SizeBytes: 512 << 10}, nil).Once()
+
+	var out bytes.Buffer
+	cmd := NewRootCmd(&Config{Out: &out, Cache: cache})
+	cmd.SetArgs([]string{"cache", "vacuum"})
+
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "Compacted the database from 3.0 MiB to 512.0 KiB\n", out.String())
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		0:       "0 B",
+		1023:    "1023 B",
+		1024:    "1.0 KiB",
+		1536:    "1.5 KiB",
+		5 << 30: "5.0 GiB",
+	}
+	for n, want := range tests {
+		assert.Equal(t, want, formatBytes(n), "formatBytes(%d)", n)
+	}
+}
+
+func TestCacheWarmCmd(t *testing.T) {
+	entries := []dictionary.Entry{{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "def 1"}}}}
+	list := []vocab.Entry{
+		{Word: "snow", Language: "en"},
+		{Word: "Rain", Language: "en"},
+		{Word: "hail", Language: "en"},
+		{Word: "gift", Language: "de"},
+		{Word: "sleet", Language: "en"},
+	}
+	cached := []dictionary.CachedWord{
+		{Word: "snow", Language: "en", Provider: "freedictionary", FetchedAt: time.Now()},
+		{Word: "rain", Language: "en", Provider: "freedictionary", FetchedAt: time.Now()},
+		// Expired
+		{Word: "hail", Language: "en", Provider: "freedictionary", FetchedAt: time.Now().Add(-48 * time.Hour)},
+		// Cached by a provider which isn't chosen
+		{Word: "gift", Language: "de", Provider: "wiktionary", FetchedAt: time.Now()},
+	}
+
+	t.Run("uncached words", func(t *testing.T) {
+		vocabRepo := &mockVocabRepo{}
+		defer vocabRepo.AssertExpectations(t)
+		vocabRepo.On("GetWordsInList", mock.Anything).Return(list, nil).Once()
+		cache := &mockWordCache{}
+		defer cache.AssertExpectations(t)
+		cache.On("CachedWords", mock.Anything).Return(cached, nil).Once()
+		definer := &mockDefiner{}
+		defer definer.AssertExpectations(t)
+		definer.On("Define", mock.Anything, "hail", "en").Return(entries, nil).Once()
+		definer.On("Define", mock.Anything, "gift", "de").Return(entries, nil).Once()
+		definer.On("Define", mock.Anything, "sleet", "en").Return(nil, &dictionary.LookupError{Word: "sleet", Err: dictionary.ErrTransport}).Once()
+
+		var out, errOut bytes.Buffer
+		cmd := NewRootCmd(&Config{
+			Out:       &out,
+			Vocab:     vocabRepo,
+			Dict:      definer,
+			Cache:     cache,
+			CacheTTL:  24 * time.Hour,
+			Providers: []string{"freedictionary"},
+		})
+		cmd.SetErr(&errOut)
+		cmd.SetArgs([]string{"cache", "warm", "--rate", "0"})
+
+		assert.EqualError(t, cmd.Execute(), "failed to cache 1 of 3 words")
+		assert.Equal(t, "Cached 2 of 3 words\n", out.String())
+		assert.Contains(t, errOut.String(), "Warming... 100% (3 words)")
+		assert.Contains(t, errOut.String(), "Failed to define sleet: could not reach the dictionary service")
+	})
+
+	t.Run("everything cached", func(t *testing.T) {
+		vocabRepo := &mockVocabRepo{}
+		defer vocabRepo.AssertExpectations(t)
+		vocabRepo.On("GetWordsInList", mock.Anything).Return(list[:2], nil).Once()
+		cache := &mockWordCache{}
+		defer cache.AssertExpectations(t)
+		cache.On("CachedWords", mock.Anything).Return(cached, nil).Once()
+
+		var out bytes.Buffer
+		cmd := NewRootCmd(&Config{
+			Out:       &out,
+			Vocab:     vocabRepo,
+			Dict:      &mockDefiner{},
+			Cache:     cache,
+			Providers: []string{"freedictionary"},
+		})
+		cmd.SetArgs([]string{"cache", "warm"})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "All 2 words in your vocab list are already cached\n", out.String())
+	})
+
+	t.Run("provider names with spaces", func(t *testing.T) {
+		vocabRepo := &mockVocabRepo{}
+		defer vocabRepo.AssertExpectations(t)
+		vocabRepo.On("GetWordsInList", mock.Anything).Return(list[:2], nil).Once()
+		cache := &mockWordCache{}
+		defer cache.AssertExpectations(t)
+		cache.On("CachedWords", mock.Anything).Return(cached, nil).Once()
+
+		var out bytes.Buffer
+		cmd := NewRootCmd(&Config{
+			Out:   &out,
+			Vocab: vocabRepo,
+			Dict:  &mockDefiner{},
+			Cache: cache,
+		})
+		cmd.SetArgs([]string{"cache", "warm", "--provider", " freedictionary ,"})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "All 2 words in your vocab list are already cached\n", out.String())
+	})
+	t.Run("offline", func(t *testing.T) {
+		cmd := NewRootCmd(&Config{
+			Out:   &bytes.Buffer{},
+			Vocab: &mockVocabRepo{},
+			Dict:  &mockDefiner{},
+			Cache: &mockWordCache{},
+		})
+		cmd.SetArgs([]string{"cache", "warm", "--offline"})
+
+		assert.EqualError(t, cmd.Execute(), "can't warm the cache with --offline")
+	})
+}
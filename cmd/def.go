@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/caproven/termdict/dictionary"
+	"github.com/spf13/cobra"
+)
+
+// NewDefCommand constructs the def command
+func NewDefCommand(cfg *Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "def",
+		Short: "Write your own definitions",
+		Long: `Write your own definitions of words, such as jargon and acronyms no dictionary knows. They're shown along
+with the definitions of providers, marked as custom, and are kept when the cache is refreshed or purged.
+
+Use "termdict define --edit <word>" to edit all your definitions of a word at once.`,
+	}
+
+	cmd.AddCommand(NewDefSetCommand(cfg))
+
+	return cmd
+}
+
+type defSetOptions struct {
+	word     string
+	meanings []string
+	pos      string
+	lang     string
+}
+
+// NewDefSetCommand constructs the def set subcommand
+func NewDefSetCommand(cfg *Config) *cobra.Command {
+	o := &defSetOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "set word meaning...",
+		Short: "Set your definitions of a word",
+		Long: `Set your definitions of a word for a part of speech, replacing any you wrote before for that part of speech.
+Each meaning given is a separate definition.
+
+Sample usage:
+  termdict def set k8s --pos noun "Kubernetes, a container orchestrator."
+  termdict def set yak-shave --pos verb "To solve a problem found while solving another." "To procrastinate."`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.Custom == nil {
+				return errors.New("custom definitions aren't available")
+			}
+			o.word, o.meanings = args[0], args[1:]
+
+			return o.run(cmd.Context(), cfg.Out, cfg.Custom)
+		},
+	}
+
+	cmd.Flags().StringVar(&o.pos, "pos", "", "part of speech of the definitions, such as noun or verb")
+	cmd.Flags().StringVar(&o.lang, "lang", cfg.language(), "language of the word, such as en or de")
+
+	return cmd
+}
+
+func (o *defSetOptions) run(ctx context.Context, out io.Writer, c CustomDefinitions) error {
+	defs, err := c.CustomDefinitions(ctx, o.word, o.lang)
+	if err != nil {
+		return fmt.Errorf("get custom definitions: %w", err)
+	}
+	defs = slices.DeleteFunc(defs, func(def dictionary.Definition) bool {
+		return strings.EqualFold(def.PartOfSpeech, o.pos)
+	})
+	for _, meaning := range o.meanings {
+		if strings.TrimSpace(meaning) == "" {
+			return errors.New("meaning can't be blank")
+		}
+		defs = append(defs, dictionary.Definition{PartOfSpeech: o.pos, Meaning: strings.TrimSpace(meaning)})
+	}
+
+	if err := c.SetCustomDefinitions(ctx, o.word, o.lang, defs); err != nil {
+		return fmt.Errorf("set custom definitions: %w", err)
+	}
+	_, _ = fmt.Fprintf(out, "Saved %d custom definitions of %s\n", len(defs), o.word)
+	return nil
+}
+
+// editCustomDefinitions lets the user edit their definitions of a word in their editor
+func editCustomDefinitions(ctx context.Context, out io.Writer, c CustomDefinitions, word, lang string) error {
+	defs, err := c.CustomDefinitions(ctx, word, lang)
+	if err != nil {
+		return fmt.Errorf("get custom definitions: %w", err)
+	}
+
+	f, err := os.CreateTemp("", "termdict-*.txt")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+	if err := errors.Join(writeCustomDefinitions(f, word, lang, defs), f.Close()); err != nil {
+		return fmt.Errorf("write temp file: %w", err)
+	}
+
+	if err := runEditor(ctx, f.Name()); err != nil {
+		return err
+	}
+
+	f, err = os.Open(f.Name())
+	if err != nil {
+		return fmt.Errorf("open temp file: %w", err)
+	}
+	edited, err := parseCustomDefinitions(f)
+	if err := errors.Join(err, f.Close()); err != nil {
+		return fmt.Errorf("read temp file: %w", err)
+	}
+
+	if slices.EqualFunc(edited, defs, func(a, b dictionary.Definition) bool {
+		return a.PartOfSpeech == b.PartOfSpeech && a.Meaning == b.Meaning
+	}) {
+		_, _ = fmt.Fprintln(out, "No changes made")
+		return nil
+	}
+	if err := c.SetCustomDefinitions(ctx, word, lang, edited); err != nil {
+		return fmt.Errorf("set custom definitions: %w", err)
+	}
+	if len(edited) == 0 {
+		_, _ = fmt.Fprintf(out, "Removed your definitions of %s\n", word)
+	} else {
+		_, _ = fmt.Fprintf(out, "Saved %d custom definitions of %s\n", len(edited), word)
+	}
+	return nil
+}
+
+// runEditor opens a file in the user's editor, waiting for it to close. $VISUAL is preferred over $EDITOR, and vi is
+// used if neither is set. Either may hold arguments for the editor, such as "code --wait".
+func runEditor(ctx context.Context, path string) error {
+	args := strings.Fields(cmp.Or(os.Getenv("VISUAL"), os.Getenv("EDITOR"), "vi"))
+	editor := exec.CommandContext(ctx, args[0], append(args[1:], path)...)
+	editor.Stdin, editor.Stdout, editor.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := editor.Run(); err != nil {
+		return fmt.Errorf("run editor %s: %w", args[0], err)
+	}
+	return nil
+}
+
+// writeCustomDefinitions writes definitions for editing, one a line
+func writeCustomDefinitions(w io.Writer, word, lang string, defs []dictionary.Definition) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Your definitions of %q (%s), one a line as \"[part of speech] meaning\".\n", word, lang)
+	b.WriteString("# The part of speech is optional. Lines starting with # are ignored, and removing every definition\n")
+	b.WriteString("# removes all of yours.\n")
+	for _, def := range defs {
+		b.WriteString(formatCustomDefinition(def) + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func formatCustomDefinition(def dictionary.Definition) string {
+	if def.PartOfSpeech == "" {
+		return def.Meaning
+	}
+	return fmt.Sprintf("[%s] %s", def.PartOfSpeech, def.Meaning)
+}
+
+// parseCustomDefinitions reads definitions written by writeCustomDefinitions
+func parseCustomDefinitions(r io.Reader) ([]dictionary.Definition, error) {
+	var defs []dictionary.Definition
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var def dictionary.Definition
+		if rest, ok := strings.CutPrefix(line, "["); ok {
+			pos, meaning, ok := strings.Cut(rest, "]")
+			if !ok {
+				return nil, fmt.Errorf("unclosed part of speech in %q", line)
+			}
+			def.PartOfSpeech, line = strings.TrimSpace(pos), strings.TrimSpace(meaning)
+		}
+		if line == "" {
+			return nil, fmt.Errorf("definition of %s has no meaning", def.PartOfSpeech)
+		}
+		def.Meaning = line
+		defs = append(defs, def)
+	}
+	return defs, scanner.Err()
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/caproven/termdict/dictionary"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefSetCmd(t *testing.T) {
+	existing := []dictionary.Definition{
+		{PartOfSpeech: "noun", Meaning: "Kubernetes."},
+		{PartOfSpeech: "verb", Meaning: "To deploy to Kubernetes."},
+	}
+
+	t.Run("replaces definitions of the part of speech", func(t *testing.T) {
+		custom := &mockCustomDefinitions{}
+		defer custom.AssertExpectations(t)
+		custom.On("CustomDefinitions", mock.Anything, "k8s", "en").Return(existing, nil).Once()
+		custom.On("SetCustomDefinitions", mock.Anything, "k8s", "en", []dictionary.Definition{
+			{PartOfSpeech: "verb", Meaning: "To deploy to Kubernetes."},
+			{PartOfSpeech: "noun", Meaning: "A container orchestrator."},
+			{PartOfSpeech: "noun", Meaning: "Short for Kubernetes."},
+		}).Return(nil).Once()
+
+		var out bytes.Buffer
+		cmd := NewRootCmd(&Config{Out: &out, Custom: custom})
+		cmd.SetArgs([]string{"def", "set", "k8s", "--pos", "noun", "A container orchestrator.", " Short for Kubernetes. "})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "Saved 3 custom definitions of k8s\n", out.String())
+	})
+
+	t.Run("blank meaning", func(t *testing.T) {
+		custom := &mockCustomDefinitions{}
+		custom.On("CustomDefinitions", mock.Anything, "k8s", "en").Return(nil, nil).Once()
+
+		cmd := NewRootCmd(&Config{Out: &bytes.Buffer{}, Custom: custom})
+		cmd.SetArgs([]string{"def", "set", "k8s", " "})
+
+		require.Error(t, cmd.Execute())
+		custom.AssertNotCalled(t, "SetCustomDefinitions", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("no meaning", func(t *testing.T) {
+		cmd := NewRootCmd(&Config{Out: &bytes.Buffer{}, Custom: &mockCustomDefinitions{}})
+		cmd.SetArgs([]string{"def", "set", "k8s"})
+
+		require.Error(t, cmd.Execute())
+	})
+}
+
+// setEditor makes the editor a script which replaces the edited file with content
+func setEditor(t *testing.T, content string) {
+	t.Helper()
+	dir := t.TempDir()
+	replacement := filepath.Join(dir, "replacement.txt")
+	require.NoError(t, os.WriteFile(replacement, []byte(content), 0o600))
+	script := filepath.Join(dir, "editor.sh")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\ncp '"+replacement+"' \"$1\"\n"), 0o700))
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", script)
+}
+
+func TestDefineCmd_Edit(t *testing.T) {
+	existing := []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "Kubernetes."}}
+
+	t.Run("definitions edited", func(t *testing.T) {
+		setEditor(t, "# comment\n[noun] Kubernetes.\n\n[ verb ]  To deploy to Kubernetes.\nAn abbreviation.\n")
+		custom := &mockCustomDefinitions{}
+		defer custom.AssertExpectations(t)
+		custom.On("CustomDefinitions", mock.Anything, "k8s", "de").Return(existing, nil).Once()
+		custom.On("SetCustomDefinitions", mock.Anything, "k8s", "de", []dictionary.Definition{
+			{PartOfSpeech: "noun", Meaning: "Kubernetes."},
+			{PartOfSpeech: "verb", Meaning: "To deploy to Kubernetes."},
+			{Meaning: "An abbreviation."},
+		}).Return(nil).Once()
+
+		var out bytes.Buffer
+		cmd := NewRootCmd(&Config{Out: &out, Dict: &mockDefiner{}, Custom: custom})
+		cmd.SetArgs([]string{"define", "--edit", "--lang", "de", "k8s"})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "Saved 3 custom definitions of k8s\n", out.String())
+	})
+
+	t.Run("all definitions removed", func(t *testing.T) {
+		setEditor(t, "# nothing left\n")
+		custom := &mockCustomDefinitions{}
+		defer custom.AssertExpectations(t)
+		custom.On("CustomDefinitions", mock.Anything, "k8s", "en").Return(existing, nil).Once()
+		custom.On("SetCustomDefinitions", mock.Anything, "k8s", "en", []dictionary.Definition(nil)).Return(nil).Once()
+
+		var out bytes.Buffer
+		cmd := NewRootCmd(&Config{Out: &out, Dict: &mockDefiner{}, Custom: custom})
+		cmd.SetArgs([]string{"define", "--edit", "k8s"})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "Removed your definitions of k8s\n", out.String())
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		setEditor(t, "[noun] Kubernetes.\n")
+		custom := &mockCustomDefinitions{}
+		defer custom.AssertExpectations(t)
+		custom.On("CustomDefinitions", mock.Anything, "k8s", "en").Return(existing, nil).Once()
+
+		var out bytes.Buffer
+		cmd := NewRootCmd(&Config{Out: &out, Dict: &mockDefiner{}, Custom: custom})
+		cmd.SetArgs([]string{"define", "--edit", "k8s"})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "No changes made\n", out.String())
+	})
+
+	t.Run("multiple words", func(t *testing.T) {
+		cmd := NewRootCmd(&Config{Out: &bytes.Buffer{}, Dict: &mockDefiner{}, Custom: &mockCustomDefinitions{}})
+		cmd.SetArgs([]string{"define", "--edit", "k8s", "k9s"})
+
+		require.Error(t, cmd.Execute())
+	})
+}
+
+func TestCustomDefinitionsRoundTrip(t *testing.T) {
+	defs := []dictionary.Definition{
+		{PartOfSpeech: "noun", Meaning: "Kubernetes."},
+		{Meaning: "A [bracketed] abbreviation."},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, writeCustomDefinitions(&b, "k8s", "en", defs))
+	got, err := parseCustomDefinitions(&b)
+	require.NoError(t, err)
+	assert.Equal(t, defs, got)
+
+	_, err = parseCustomDefinitions(strings.NewReader("[noun Kubernetes.\n"))
+	assert.Error(t, err)
+	_, err = parseCustomDefinitions(strings.NewReader("[noun]\n"))
+	assert.Error(t, err)
+}
+
+func TestTextPrinter_Custom(t *testing.T) {
+	entries := []dictionary.Entry{
+		{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "Kubernetes."}}, Provider: dictionary.ProviderCustom},
+		{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "A container orchestrator."}}, Provider: "freedictionary"},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, new(textPrinter).Print(&b, "k8s", entries))
+	assert.Equal(t, "k8s¹ (custom)\n[noun] Kubernetes.\n\nk8s²\n[noun] A container orchestrator.\n", b.String())
+}
@@ -6,20 +6,24 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"os"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/caproven/termdict/dictionary"
 	"github.com/caproven/termdict/rand"
+	"github.com/caproven/termdict/vocab"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
 type defineOptions struct {
-	word       string
+	words      []string
+	lang       string
 	random     bool
 	randomSeed uint64
 	save       bool
+	edit       bool
 	output     string
 	printers   map[string]defPrinter
 }
@@ -31,15 +35,29 @@ func NewDefineCommand(cfg *Config) *cobra.Command {
 	}
 
 	cmd := &cobra.Command{
-		Use:   "define word | --random",
-		Short: "Define a word",
-		Long: `Lookup the definition for a given word.
+		Use:   "define word... | --random",
+		Short: "Define words",
+		Long: `Lookup the definitions for the given words. Multiple words are looked up concurrently, and any which can't be
+defined are reported without stopping the others.
 
 Sample usage:
   termdict define organic
-  termdict define --random`,
-		Args: cobra.MaximumNArgs(1),
+  termdict define organic synthesis catalyst
+  termdict define --examples organic
+  termdict define --sources organic
+  termdict define --lang de schnee
+  termdict define --random
+  termdict define --edit k8s`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if o.edit {
+				if len(args) != 1 {
+					return errors.New("must specify a single word to edit")
+				}
+				if cfg.Custom == nil {
+					return errors.New("custom definitions aren't available")
+				}
+				return editCustomDefinitions(cmd.Context(), cfg.Out, cfg.Custom, args[0], o.lang)
+			}
 			if o.random {
 				if len(args) > 0 {
 					return errors.New("can't use --random and a specified word")
@@ -48,69 +66,120 @@ Sample usage:
 				if len(args) == 0 {
 					return errors.New("must specify word")
 				}
-				o.word = args[0]
+				o.words = args
 			}
 
-			return o.run(cmd.Context(), cfg.Out, cfg.Vocab, cfg.Dict)
+			return o.run(cmd.Context(), cfg.Out, cmd.ErrOrStderr(), cfg.Vocab, cfg.Dict)
 		},
 	}
 
 	o.registerPrinter(new(textPrinter), cmd)
 	o.registerPrinter(new(jsonPrinter), cmd)
 
+	cmd.Flags().StringVar(&o.lang, "lang", cfg.language(), "language to look the word up in, such as en or de")
 	cmd.Flags().BoolVar(&o.random, "random", false, "define a random word from your vocab list")
 	cmd.Flags().Uint64Var(&o.randomSeed, "seed", 0, "rng seed making usage of --random deterministic")
 	cmd.Flags().BoolVar(&o.save, "save", false, "add to the vocab list if the word can be defined")
-	cmd.Flags().StringVarP(&o.output, "output", "o", "text", "output format; one of text, json")
+	cmd.Flags().BoolVar(&o.edit, "edit", false, "edit your own definitions of the word in $EDITOR")
+	cmd.Flags().StringVarP(&o.output, "output", "o", "text", "output format; one of text, json (an array when several words are given)")
 	// Avoid attempting to save words already in the list.
 	cmd.MarkFlagsMutuallyExclusive("save", "random")
+	cmd.MarkFlagsMutuallyExclusive("edit", "random")
+	cmd.MarkFlagsMutuallyExclusive("edit", "save")
 
 	return cmd
 }
 
-func (o *defineOptions) run(ctx context.Context, out io.Writer, v VocabRepo, d Definer) error {
+func (o *defineOptions) run(ctx context.Context, out, errOut io.Writer, v VocabRepo, d Definer) error {
 	// Don't call anything else if output format is invalid
 	printer, err := o.getPrinter(o.output)
 	if err != nil {
 		return err
 	}
 
-	word := o.word
+	words, lang := o.words, o.lang
 	if o.random {
 		source := randSource(rand.Default{})
 		if o.randomSeed != 0 {
 			source = rand.NewRand(o.randomSeed)
 		}
 
-		var err error
-		word, err = selectRandomWord(ctx, v, source)
+		// Words are defined in the language they were listed in
+		entry, err := selectRandomWord(ctx, v, source)
 		if err != nil {
 			return err
 		}
+		words, lang = []string{entry.Word}, entry.Language
 	}
 
-	defs, err := d.Define(ctx, word)
-	if err != nil {
-		return err
+	results := dictionary.NewBatch(d, 0).DefineMany(ctx, words, lang)
+	// A single word's error is returned as is, so the exit code tells why it failed
+	if len(results) == 1 && results[0].Err != nil {
+		return results[0].Err
 	}
 
-	if o.save {
-		added, err := v.AddWordsToList(ctx, []string{word})
+	var defined []string
+	var found []dictionary.Result
+	for _, r := range results {
+		if r.Err != nil {
+			_, _ = fmt.Fprintf(errOut, "Failed to define %s: %s\n", r.Word, ErrorMessage(r.Err))
+			continue
+		}
+		found = append(found, r)
+		defined = append(defined, r.Word)
+	}
+
+	// Printers of a structured format write the words given together as one document
+	if mp, ok := printer.(multiPrinter); ok && len(results) > 1 {
+		if err := mp.PrintMany(out, found); err != nil {
+			return err
+		}
+	} else {
+		for i, r := range found {
+			if i > 0 {
+				if _, err := fmt.Fprintln(out); err != nil {
+					return err
+				}
+			}
+			if err := printer.Print(out, r.Word, r.Entries); err != nil {
+				return err
+			}
+		}
+	}
+	for _, r := range found {
+		warnIfStale(errOut, r.Word, r.Entries)
+	}
+
+	if o.save && len(defined) > 0 {
+		added, err := v.AddWordsToList(ctx, defined, lang)
 		if err != nil {
 			return fmt.Errorf("save words to list: %w", err)
 		}
 		if len(added) > 0 {
-			_, _ = fmt.Fprintf(os.Stderr, "Saved word(s) to list: %q\n", added)
+			_, _ = fmt.Fprintf(errOut, "Saved word(s) to list: %q\n", added)
 		} else {
-			_, _ = fmt.Fprintln(os.Stderr, "Word already present in list, not saving")
+			_, _ = fmt.Fprintln(errOut, "Word already present in list, not saving")
 		}
 	}
 
-	return printer.Print(out, word, defs)
+	if failed := len(results) - len(defined); failed > 0 {
+		return fmt.Errorf("failed to define %d of %d words", failed, len(results))
+	}
+	return nil
+}
+
+// warnIfStale tells the user when a word was defined from expired cached entries, which may be out of date
+func warnIfStale(w io.Writer, word string, entries []dictionary.Entry) {
+	if slices.ContainsFunc(entries, func(e dictionary.Entry) bool { return e.Stale }) {
+		_, _ = fmt.Fprintf(w, "Warning: the definitions of %s are from an expired cache entry and may be out of date\n", word)
+	}
 }
 
 func (o *defineOptions) registerPrinter(p defPrinter, cmd *cobra.Command) {
 	o.printers[p.OutputType()] = p
+	if fa, ok := p.(flagAdder); ok {
+		fa.AddFlags(cmd)
+	}
 }
 
 func (o *defineOptions) getPrinter(output string) (defPrinter, error) {
@@ -121,14 +190,14 @@ func (o *defineOptions) getPrinter(output string) (defPrinter, error) {
 	return printer, nil
 }
 
-func selectRandomWord(ctx context.Context, v VocabRepo, randSource randSource) (string, error) {
+func selectRandomWord(ctx context.Context, v VocabRepo, randSource randSource) (vocab.Entry, error) {
 	list, err := v.GetWordsInList(ctx)
 	if err != nil {
-		return "", fmt.Errorf("list words: %w", err)
+		return vocab.Entry{}, fmt.Errorf("list words: %w", err)
 	}
 
 	if len(list) == 0 {
-		return "", errors.New("no words found")
+		return vocab.Entry{}, errors.New("no words found")
 	}
 
 	return list[randSource.IntN(len(list))], nil
@@ -141,25 +210,145 @@ type randSource interface {
 
 type defPrinter interface {
 	OutputType() string
-	Print(w io.Writer, word string, defs []dictionary.Definition) error
+	Print(w io.Writer, word string, entries []dictionary.Entry) error
+}
+
+// multiPrinter is implemented by printers which print several words as a single document
+type multiPrinter interface {
+	PrintMany(w io.Writer, results []dictionary.Result) error
+}
+
+// flagAdder is implemented by printers which have their own flags
+type flagAdder interface {
+	AddFlags(cmd *cobra.Command)
 }
 
 type textPrinter struct {
+	audio    bool
+	examples bool
+	related  bool
+	sources  bool
+}
+
+func (p *textPrinter) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&p.audio, "audio", false, "show links to pronunciation recordings in text output")
+	cmd.Flags().BoolVar(&p.examples, "examples", false, "show example usages under each definition in text output")
+	cmd.Flags().BoolVar(&p.related, "related", false, "show synonyms and antonyms in text output")
+	cmd.Flags().BoolVar(&p.sources, "sources", false, "show source and license attribution in text output")
 }
 
 func (p *textPrinter) OutputType() string {
 	return "text"
 }
 
-func (p *textPrinter) Print(w io.Writer, word string, defs []dictionary.Definition) error {
+func (p *textPrinter) Print(w io.Writer, word string, entries []dictionary.Entry) error {
+	for i, entry := range entries {
+		heading := word
+		// Number homographs so senses with unrelated origins stay apart
+		if len(entries) > 1 {
+			heading += superscript(i + 1)
+			if i > 0 {
+				if _, err := fmt.Fprintln(w); err != nil {
+					return err
+				}
+			}
+		}
+		if err := p.printEntry(w, heading, entry); err != nil {
+			return err
+		}
+	}
+
+	if p.sources {
+		return printAttribution(w, entries)
+	}
+
+	return nil
+}
+
+// printAttribution writes a footer naming the distinct sources and licenses of the entries
+func printAttribution(w io.Writer, entries []dictionary.Entry) error {
+	var lines []string
+	seen := make(map[string]bool)
+	add := func(line string) {
+		if !seen[line] {
+			seen[line] = true
+			lines = append(lines, line)
+		}
+	}
+	for _, entry := range entries {
+		for _, url := range entry.SourceURLs {
+			add("Source: " + url)
+		}
+	}
+	for _, entry := range entries {
+		switch {
+		case entry.License.Name != "" && entry.License.URL != "":
+			add(fmt.Sprintf("License: %s (%s)", entry.License.Name, entry.License.URL))
+		case entry.License.Name != "":
+			add("License: " + entry.License.Name)
+		case entry.License.URL != "":
+			add("License: " + entry.License.URL)
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	if _, err := fmt.Fprintln(w); err != nil {
+		return err
+	}
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *textPrinter) printEntry(w io.Writer, heading string, entry dictionary.Entry) error {
 	green := color.New(color.FgGreen).SprintFunc()
-	if _, err := fmt.Fprintln(w, green(word)); err != nil {
+	heading = green(heading)
+	if entry.Provider == dictionary.ProviderCustom {
+		heading += " (custom)"
+	}
+	if pronunciations := entry.Pronunciations(); len(pronunciations) > 0 {
+		heading += " " + strings.Join(pronunciations, " ")
+	}
+	if _, err := fmt.Fprintln(w, heading); err != nil {
 		return err
 	}
 
+	if p.audio {
+		for _, phonetic := range entry.Phonetics {
+			if phonetic.Audio == "" {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "audio: %s\n", phonetic.Audio); err != nil {
+				return err
+			}
+		}
+	}
+
 	blue := color.New(color.FgCyan).SprintFunc()
-	for _, def := range defs {
-		if _, err := fmt.Fprintf(w, "[%s] %s\n", blue(def.PartOfSpeech), def.Meaning); err != nil {
+	for _, def := range entry.Definitions {
+		line := def.Meaning
+		// Some dictionaries, such as StarDict dumps, don't record parts of speech
+		if def.PartOfSpeech != "" {
+			line = fmt.Sprintf("[%s] %s", blue(def.PartOfSpeech), def.Meaning)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+		if p.examples && def.Example != "" {
+			if _, err := fmt.Fprintf(w, "    \"%s\"\n", def.Example); err != nil {
+				return err
+			}
+		}
+	}
+
+	if p.related {
+		if err := printRelations(w, entry.Thesaurus()); err != nil {
 			return err
 		}
 	}
@@ -176,16 +365,39 @@ func (p *jsonPrinter) OutputType() string {
 	return "json"
 }
 
-func (p *jsonPrinter) Print(w io.Writer, word string, defs []dictionary.Definition) error {
-	composite := struct {
-		Word        string
-		Definitions []dictionary.Definition
-	}{
-		Word:        word,
-		Definitions: defs,
+// jsonDefinition is the JSON representation of a defined word
+type jsonDefinition struct {
+	Word    string
+	Entries []dictionary.Entry
+}
+
+func (p *jsonPrinter) Print(w io.Writer, word string, entries []dictionary.Entry) error {
+	return writeJSON(w, jsonDefinition{Word: word, Entries: entries})
+}
+
+// PrintMany prints the words as a JSON array, so the output of several words can be parsed as one document
+func (p *jsonPrinter) PrintMany(w io.Writer, results []dictionary.Result) error {
+	defs := make([]jsonDefinition, 0, len(results))
+	for _, r := range results {
+		defs = append(defs, jsonDefinition{Word: r.Word, Entries: r.Entries})
 	}
+	return writeJSON(w, defs)
+}
+
+var superscriptDigits = []rune("⁰¹²³⁴⁵⁶⁷⁸⁹")
+
+// superscript formats a positive number using superscript digits
+func superscript(n int) string {
+	var digits []rune
+	for _, d := range strconv.Itoa(n) {
+		digits = append(digits, superscriptDigits[d-'0'])
+	}
+	return string(digits)
+}
 
-	data, err := json.MarshalIndent(composite, "", "\t")
+// writeJSON writes v to w as indented JSON followed by a newline
+func writeJSON(w io.Writer, v any) error {
+	data, err := json.MarshalIndent(v, "", "\t")
 	if err != nil {
 		return err
 	}
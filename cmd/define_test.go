@@ -2,20 +2,27 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
+	"io"
 	"os"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/caproven/termdict/dictionary"
+	"github.com/caproven/termdict/vocab"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
 func TestDefineCmd(t *testing.T) {
-	sampleDefs := []dictionary.Definition{
-		{PartOfSpeech: "noun", Meaning: "something"},
-	}
+	sampleEntries := []dictionary.Entry{{
+		Definitions: []dictionary.Definition{
+			{PartOfSpeech: "noun", Meaning: "something"},
+		},
+	}}
 	sampleErr := errors.New("failure")
 
 	t.Run("no word specified", func(t *testing.T) {
@@ -33,7 +40,7 @@ func TestDefineCmd(t *testing.T) {
 	t.Run("word not found", func(t *testing.T) {
 		definer := &mockDefiner{}
 		defer definer.AssertExpectations(t)
-		definer.On("Define", mock.Anything, "foo").Return(nil, sampleErr).Once()
+		definer.On("Define", mock.Anything, "foo", "en").Return(nil, sampleErr).Once()
 
 		cmd := NewRootCmd(&Config{
 			Out:   os.Stdout,
@@ -49,7 +56,7 @@ func TestDefineCmd(t *testing.T) {
 	t.Run("word found", func(t *testing.T) {
 		definer := &mockDefiner{}
 		defer definer.AssertExpectations(t)
-		definer.On("Define", mock.Anything, "bar").Return(sampleDefs, nil).Once()
+		definer.On("Define", mock.Anything, "bar", "en").Return(sampleEntries, nil).Once()
 
 		cmd := NewRootCmd(&Config{
 			Out:   os.Stdout,
@@ -62,10 +69,53 @@ func TestDefineCmd(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("multiple words", func(t *testing.T) {
+		definer := &mockDefiner{}
+		defer definer.AssertExpectations(t)
+		definer.On("Define", mock.Anything, "bar", "en").Return(sampleEntries, nil).Once()
+		definer.On("Define", mock.Anything, "baz", "en").Return(sampleEntries, nil).Once()
+
+		var out bytes.Buffer
+		cmd := NewRootCmd(&Config{
+			Out:   &out,
+			Vocab: &mockVocabRepo{},
+			Dict:  definer,
+		})
+		cmd.SetArgs([]string{"define", "--no-color", "bar", "baz"})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "bar\n[noun] something\n\nbaz\n[noun] something\n", out.String())
+	})
+
+	t.Run("multiple words with some not found", func(t *testing.T) {
+		vocabRepo := &mockVocabRepo{}
+		defer vocabRepo.AssertExpectations(t)
+		vocabRepo.On("AddWordsToList", mock.Anything, []string{"bar", "baz"}, "en").Return([]string{"bar", "baz"}, nil).Once()
+
+		definer := &mockDefiner{}
+		defer definer.AssertExpectations(t)
+		definer.On("Define", mock.Anything, "bar", "en").Return(sampleEntries, nil).Once()
+		definer.On("Define", mock.Anything, "barr", "en").Return(nil, &dictionary.LookupError{Word: "barr", Err: dictionary.ErrNotFound}).Once()
+		definer.On("Define", mock.Anything, "baz", "en").Return(sampleEntries, nil).Once()
+
+		var out, errOut bytes.Buffer
+		cmd := NewRootCmd(&Config{
+			Out:   &out,
+			Vocab: vocabRepo,
+			Dict:  definer,
+		})
+		cmd.SetErr(&errOut)
+		cmd.SetArgs([]string{"define", "--no-color", "--save", "bar", "barr", "baz"})
+
+		assert.EqualError(t, cmd.Execute(), "failed to define 1 of 3 words")
+		assert.Equal(t, "bar\n[noun] something\n\nbaz\n[noun] something\n", out.String())
+		assert.Contains(t, errOut.String(), `Failed to define barr: no definitions found for "barr"`)
+	})
+
 	t.Run("random with empty list", func(t *testing.T) {
 		vocabRepo := &mockVocabRepo{}
 		defer vocabRepo.AssertExpectations(t)
-		vocabRepo.On("GetWordsInList", mock.Anything).Return([]string{}, nil).Once()
+		vocabRepo.On("GetWordsInList", mock.Anything).Return([]vocab.Entry{}, nil).Once()
 
 		cmd := NewRootCmd(&Config{
 			Out:   os.Stdout,
@@ -82,11 +132,11 @@ func TestDefineCmd(t *testing.T) {
 	t.Run("random with single word in list", func(t *testing.T) {
 		vocabRepo := &mockVocabRepo{}
 		defer vocabRepo.AssertExpectations(t)
-		vocabRepo.On("GetWordsInList", mock.Anything).Return([]string{"a"}, nil).Once()
+		vocabRepo.On("GetWordsInList", mock.Anything).Return([]vocab.Entry{{Word: "a", Language: "en"}}, nil).Once()
 
 		definer := &mockDefiner{}
 		defer definer.AssertExpectations(t)
-		definer.On("Define", mock.Anything, "a").Return(sampleDefs, nil).Once()
+		definer.On("Define", mock.Anything, "a", "en").Return(sampleEntries, nil).Once()
 
 		cmd := NewRootCmd(&Config{
 			Out:   os.Stdout,
@@ -99,6 +149,229 @@ func TestDefineCmd(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("random uses the language of the listed word", func(t *testing.T) {
+		vocabRepo := &mockVocabRepo{}
+		defer vocabRepo.AssertExpectations(t)
+		vocabRepo.On("GetWordsInList", mock.Anything).Return([]vocab.Entry{{Word: "schnee", Language: "de"}}, nil).Once()
+
+		definer := &mockDefiner{}
+		defer definer.AssertExpectations(t)
+		definer.On("Define", mock.Anything, "schnee", "de").Return(sampleEntries, nil).Once()
+
+		cmd := NewRootCmd(&Config{
+			Out:   &bytes.Buffer{},
+			Vocab: vocabRepo,
+			Dict:  definer,
+		})
+		cmd.SetArgs([]string{"define", "--random"})
+
+		require.NoError(t, cmd.Execute())
+	})
+
+	t.Run("language flag", func(t *testing.T) {
+		definer := &mockDefiner{}
+		defer definer.AssertExpectations(t)
+		definer.On("Define", mock.Anything, "nieve", "es").Return(sampleEntries, nil).Once()
+
+		cmd := NewRootCmd(&Config{
+			Out:   &bytes.Buffer{},
+			Vocab: &mockVocabRepo{},
+			Dict:  definer,
+		})
+		cmd.SetArgs([]string{"define", "--lang", "es", "nieve"})
+
+		require.NoError(t, cmd.Execute())
+	})
+
+	t.Run("default language from config", func(t *testing.T) {
+		definer := &mockDefiner{}
+		defer definer.AssertExpectations(t)
+		definer.On("Define", mock.Anything, "schnee", "de").Return(sampleEntries, nil).Once()
+
+		cmd := NewRootCmd(&Config{
+			Out:      &bytes.Buffer{},
+			Vocab:    &mockVocabRepo{},
+			Dict:     definer,
+			Language: "de",
+		})
+		cmd.SetArgs([]string{"define", "schnee"})
+
+		require.NoError(t, cmd.Execute())
+	})
+
+	t.Run("provider flag", func(t *testing.T) {
+		tests := map[string]struct {
+			args          []string
+			wantProviders []string
+			wantMerge     bool
+		}{
+			"default": {
+				args:          []string{"define", "snow"},
+				wantProviders: []string{"freedictionary"},
+			},
+			"single provider": {
+				args:          []string{"define", "--provider", "wiktionary", "snow"},
+				wantProviders: []string{"wiktionary"},
+			},
+			"fallback chain": {
+				args:          []string{"define", "--provider", "offline,freedictionary", "snow"},
+				wantProviders: []string{"offline", "freedictionary"},
+			},
+			"spaces around names": {
+				args:          []string{"define", "--provider", "offline, wiktionary,", "snow"},
+				wantProviders: []string{"offline", "wiktionary"},
+			},
+			"merged chain": {
+				args:          []string{"define", "--provider", "offline", "--provider", "wiktionary", "--merge", "snow"},
+				wantProviders: []string{"offline", "wiktionary"},
+				wantMerge:     true,
+			},
+		}
+		for name, tt := range tests {
+			t.Run(name, func(t *testing.T) {
+				definer := &mockDefiner{}
+				defer definer.AssertExpectations(t)
+				definer.On("Define", mock.Anything, "snow", "en").Return(sampleEntries, nil).Once()
+
+				var gotProviders []string
+				var gotMerge bool
+				cmd := NewRootCmd(&Config{
+					Out:       &bytes.Buffer{},
+					Vocab:     &mockVocabRepo{},
+					Providers: []string{"freedictionary"},
+					NewDefiner: func(opts DefinerOptions) (Definer, error) {
+						gotProviders, gotMerge = opts.Providers, opts.Merge
+						return definer, nil
+					},
+				})
+				cmd.SetArgs(tt.args)
+
+				require.NoError(t, cmd.Execute())
+				assert.Equal(t, tt.wantProviders, gotProviders)
+				assert.Equal(t, tt.wantMerge, gotMerge)
+			})
+		}
+	})
+
+	t.Run("no cache flag", func(t *testing.T) {
+		definer := &mockDefiner{}
+		defer definer.AssertExpectations(t)
+		definer.On("Define", mock.Anything, "snow", "en").Return(sampleEntries, nil).Once()
+
+		var got DefinerOptions
+		cmd := NewRootCmd(&Config{
+			Out:       &bytes.Buffer{},
+			Vocab:     &mockVocabRepo{},
+			Providers: []string{"freedictionary"},
+			NewDefiner: func(opts DefinerOptions) (Definer, error) {
+				got = opts
+				return definer, nil
+			},
+		})
+		cmd.SetArgs([]string{"define", "--no-cache", "snow"})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, DefinerOptions{Providers: []string{"freedictionary"}, NoCache: true}, got)
+	})
+
+	t.Run("http flags", func(t *testing.T) {
+		definer := &mockDefiner{}
+		defer definer.AssertExpectations(t)
+		definer.On("Define", mock.Anything, "snow", "en").Return(sampleEntries, nil).Once()
+
+		var got DefinerOptions
+		cmd := NewRootCmd(&Config{
+			Out:       &bytes.Buffer{},
+			Vocab:     &mockVocabRepo{},
+			Providers: []string{"freedictionary"},
+			HTTP:      dictionary.HTTPOptions{Timeout: 10 * time.Second, Retries: 3, UserAgent: "termdict", CABundle: "/etc/ca.pem"},
+			NewDefiner: func(opts DefinerOptions) (Definer, error) {
+				got = opts
+				return definer, nil
+			},
+		})
+		cmd.SetArgs([]string{"define", "--timeout", "2s", "--retries", "0", "--proxy", "http://proxy:3128", "snow"})
+
+		require.NoError(t, cmd.Execute())
+		// Settings without flags are kept from the config
+		assert.Equal(t, dictionary.HTTPOptions{
+			Timeout:   2 * time.Second,
+			Retries:   0,
+			UserAgent: "termdict",
+			Proxy:     "http://proxy:3128",
+			CABundle:  "/etc/ca.pem",
+		}, got.HTTP)
+	})
+
+	t.Run("offline flag", func(t *testing.T) {
+		definer := &mockDefiner{}
+		defer definer.AssertExpectations(t)
+		definer.On("Define", mock.Anything, "snow", "en").Return(sampleEntries, nil).Once()
+
+		var got DefinerOptions
+		cmd := NewRootCmd(&Config{
+			Out:       &bytes.Buffer{},
+			Vocab:     &mockVocabRepo{},
+			Providers: []string{"freedictionary"},
+			NewDefiner: func(opts DefinerOptions) (Definer, error) {
+				got = opts
+				return definer, nil
+			},
+		})
+		cmd.SetArgs([]string{"define", "--offline", "snow"})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, DefinerOptions{Providers: []string{"freedictionary"}, Offline: true}, got)
+	})
+
+	t.Run("offline and no cache flags are mutually exclusive", func(t *testing.T) {
+		cmd := NewRootCmd(&Config{
+			Out:   &bytes.Buffer{},
+			Vocab: &mockVocabRepo{},
+			Dict:  &mockDefiner{},
+		})
+		cmd.SetArgs([]string{"define", "--offline", "--no-cache", "snow"})
+
+		require.Error(t, cmd.Execute())
+	})
+
+	t.Run("stale definitions are warned about", func(t *testing.T) {
+		stale := []dictionary.Entry{{
+			Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "something"}},
+			Stale:       true,
+		}}
+		definer := &mockDefiner{}
+		defer definer.AssertExpectations(t)
+		definer.On("Define", mock.Anything, "snow", "en").Return(stale, nil).Once()
+		definer.On("Define", mock.Anything, "rain", "en").Return(sampleEntries, nil).Once()
+
+		out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+		cmd := NewRootCmd(&Config{
+			Out:   out,
+			Vocab: &mockVocabRepo{},
+			Dict:  definer,
+		})
+		cmd.SetErr(errOut)
+		cmd.SetArgs([]string{"define", "snow", "rain"})
+
+		require.NoError(t, cmd.Execute())
+		assert.Contains(t, out.String(), "something")
+		assert.Equal(t, "Warning: the definitions of snow are from an expired cache entry and may be out of date\n", errOut.String())
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		cmd := NewRootCmd(&Config{
+			Out:   &bytes.Buffer{},
+			Vocab: &mockVocabRepo{},
+			NewDefiner: func(DefinerOptions) (Definer, error) {
+				return nil, dictionary.ErrUnknownProvider
+			},
+		})
+		cmd.SetArgs([]string{"define", "--provider", "missing", "snow"})
+
+		require.ErrorIs(t, cmd.Execute(), dictionary.ErrUnknownProvider)
+	})
+
 	t.Run("random flag cannot be given alongside a positional arg", func(t *testing.T) {
 		cmd := NewRootCmd(&Config{
 			Out:   os.Stdout,
@@ -126,7 +399,7 @@ func TestDefineCmd(t *testing.T) {
 	t.Run("json output", func(t *testing.T) {
 		definer := &mockDefiner{}
 		defer definer.AssertExpectations(t)
-		definer.On("Define", mock.Anything, "b").Return(sampleDefs, nil).Once()
+		definer.On("Define", mock.Anything, "b", "en").Return(sampleEntries, nil).Once()
 
 		cmd := NewRootCmd(&Config{
 			Out:   os.Stdout,
@@ -139,14 +412,41 @@ func TestDefineCmd(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("json output of multiple words", func(t *testing.T) {
+		definer := &mockDefiner{}
+		defer definer.AssertExpectations(t)
+		definer.On("Define", mock.Anything, "bar", "en").Return(sampleEntries, nil).Once()
+		definer.On("Define", mock.Anything, "barr", "en").Return(nil, &dictionary.LookupError{Word: "barr", Err: dictionary.ErrNotFound}).Once()
+		definer.On("Define", mock.Anything, "baz", "en").Return(sampleEntries, nil).Once()
+
+		var out bytes.Buffer
+		cmd := NewRootCmd(&Config{
+			Out:   &out,
+			Vocab: &mockVocabRepo{},
+			Dict:  definer,
+		})
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs([]string{"define", "--output", "json", "bar", "barr", "baz"})
+
+		assert.EqualError(t, cmd.Execute(), "failed to define 1 of 3 words")
+		var got []struct {
+			Word    string
+			Entries []dictionary.Entry
+		}
+		require.NoError(t, json.Unmarshal(out.Bytes(), &got), "output should be a single JSON document")
+		require.Len(t, got, 2)
+		assert.Equal(t, "bar", got[0].Word)
+		assert.Equal(t, "baz", got[1].Word)
+	})
+
 	t.Run("random with output flag", func(t *testing.T) {
 		vocabRepo := &mockVocabRepo{}
 		defer vocabRepo.AssertExpectations(t)
-		vocabRepo.On("GetWordsInList", mock.Anything).Return([]string{"c"}, nil).Once()
+		vocabRepo.On("GetWordsInList", mock.Anything).Return([]vocab.Entry{{Word: "c", Language: "en"}}, nil).Once()
 
 		definer := &mockDefiner{}
 		defer definer.AssertExpectations(t)
-		definer.On("Define", mock.Anything, "c").Return(sampleDefs, nil).Once()
+		definer.On("Define", mock.Anything, "c", "en").Return(sampleEntries, nil).Once()
 
 		cmd := NewRootCmd(&Config{
 			Out:   os.Stdout,
@@ -173,11 +473,11 @@ func TestDefineCmd(t *testing.T) {
 		defer vocabRepo.AssertExpectations(t)
 		vocabRepo.On("AddWordsToList", mock.Anything, mock.MatchedBy(func(words []string) bool {
 			return reflect.DeepEqual(words, []string{word})
-		})).Return([]string{word}, nil).Once()
+		}), "en").Return([]string{word}, nil).Once()
 
 		dict := &mockDefiner{}
 		defer dict.AssertExpectations(t)
-		dict.On("Define", mock.Anything, word).Return(sampleDefs, nil).Once()
+		dict.On("Define", mock.Anything, word, "en").Return(sampleEntries, nil).Once()
 
 		cmd := NewRootCmd(&Config{
 			Out:   os.Stdout,
@@ -197,7 +497,7 @@ func TestDefineCmd(t *testing.T) {
 
 		dict := &mockDefiner{}
 		defer dict.AssertExpectations(t)
-		dict.On("Define", mock.Anything, word).Return(nil, sampleErr).Once()
+		dict.On("Define", mock.Anything, word, "en").Return(nil, sampleErr).Once()
 
 		cmd := NewRootCmd(&Config{
 			Out:   os.Stdout,
@@ -214,11 +514,11 @@ func TestDefineCmd(t *testing.T) {
 		word := "cumulonimbus"
 		vocabRepo := &mockVocabRepo{}
 		defer vocabRepo.AssertExpectations(t)
-		vocabRepo.On("AddWordsToList", mock.Anything, mock.Anything).Return(nil, sampleErr).Once()
+		vocabRepo.On("AddWordsToList", mock.Anything, mock.Anything, "en").Return(nil, sampleErr).Once()
 
 		dict := &mockDefiner{}
 		defer dict.AssertExpectations(t)
-		dict.On("Define", mock.Anything, word).Return(sampleDefs, nil).Once()
+		dict.On("Define", mock.Anything, word, "en").Return(sampleEntries, nil).Once()
 
 		cmd := NewRootCmd(&Config{
 			Out:   os.Stdout,
@@ -236,7 +536,13 @@ func TestTextPrinter(t *testing.T) {
 	cases := []struct {
 		name        string
 		word        string
+		phonetic    string
+		phonetics   []dictionary.Phonetic
 		definitions []dictionary.Definition
+		relations   []dictionary.Relations
+		audio       bool
+		examples    bool
+		related     bool
 		expected    string
 	}{
 		{
@@ -250,6 +556,16 @@ func TestTextPrinter(t *testing.T) {
 			},
 			expected: `sponge
 [noun] A piece of porous material used for washing
+`,
+		},
+		{
+			name: "no part of speech",
+			word: "sponge",
+			definitions: []dictionary.Definition{
+				{Meaning: "A piece of porous material used for washing"},
+			},
+			expected: `sponge
+A piece of porous material used for washing
 `,
 		},
 		{
@@ -268,6 +584,76 @@ func TestTextPrinter(t *testing.T) {
 			expected: `sponge
 [noun] A piece of porous material used for washing
 [verb] To clean, soak up, or dab with a sponge
+`,
+		},
+		{
+			name:     "with pronunciations",
+			word:     "snow",
+			phonetic: "/snəʊ/",
+			phonetics: []dictionary.Phonetic{
+				{Text: "/snəʊ/", Audio: "https://example.com/snow-uk.mp3"},
+				{Text: "/snoʊ/", Audio: "https://example.com/snow-us.mp3"},
+			},
+			definitions: []dictionary.Definition{
+				{PartOfSpeech: "verb", Meaning: "To have snow fall from the sky."},
+			},
+			expected: `snow /snəʊ/ /snoʊ/
+[verb] To have snow fall from the sky.
+`,
+		},
+		{
+			name: "with audio",
+			word: "prickly",
+			phonetics: []dictionary.Phonetic{
+				{Audio: "https://example.com/prickly.mp3"},
+			},
+			definitions: []dictionary.Definition{
+				{PartOfSpeech: "adverb", Meaning: "In a prickly manner."},
+			},
+			audio: true,
+			expected: `prickly
+audio: https://example.com/prickly.mp3
+[adverb] In a prickly manner.
+`,
+		},
+		{
+			name: "examples hidden by default",
+			word: "snow",
+			definitions: []dictionary.Definition{
+				{PartOfSpeech: "verb", Meaning: "To have snow fall from the sky.", Example: "It is snowing."},
+			},
+			expected: `snow
+[verb] To have snow fall from the sky.
+`,
+		},
+		{
+			name: "with examples",
+			word: "snow",
+			definitions: []dictionary.Definition{
+				{PartOfSpeech: "noun", Meaning: "A shade of the color white."},
+				{PartOfSpeech: "verb", Meaning: "To have snow fall from the sky.", Example: "It is snowing."},
+			},
+			examples: true,
+			expected: `snow
+[noun] A shade of the color white.
+[verb] To have snow fall from the sky.
+    "It is snowing."
+`,
+		},
+		{
+			name: "with related words",
+			word: "prickly",
+			definitions: []dictionary.Definition{
+				{PartOfSpeech: "adjective", Meaning: "Easily irritated.", Antonyms: []string{"easygoing"}},
+			},
+			relations: []dictionary.Relations{
+				{PartOfSpeech: "adjective", Synonyms: []string{"spiny", "thorny"}},
+			},
+			related: true,
+			expected: `prickly
+[adjective] Easily irritated.
+[adjective] synonyms: spiny, thorny
+[adjective] antonyms: easygoing
 `,
 		},
 	}
@@ -275,8 +661,14 @@ func TestTextPrinter(t *testing.T) {
 	for _, test := range cases {
 		t.Run(test.name, func(t *testing.T) {
 			var b bytes.Buffer
-			printer := &textPrinter{}
-			if err := printer.Print(&b, test.word, test.definitions); err != nil {
+			printer := &textPrinter{audio: test.audio, examples: test.examples, related: test.related}
+			entry := dictionary.Entry{
+				Phonetic:    test.phonetic,
+				Phonetics:   test.phonetics,
+				Definitions: test.definitions,
+				Relations:   test.relations,
+			}
+			if err := printer.Print(&b, test.word, []dictionary.Entry{entry}); err != nil {
 				t.Errorf("failed to print definition: %v", err)
 			}
 
@@ -289,27 +681,200 @@ func TestTextPrinter(t *testing.T) {
 	}
 }
 
+func TestTextPrinter_Homographs(t *testing.T) {
+	entries := []dictionary.Entry{
+		{
+			Phonetic: "/snəʊ/",
+			Definitions: []dictionary.Definition{
+				{PartOfSpeech: "verb", Meaning: "To have snow fall from the sky."},
+			},
+		},
+		{
+			Definitions: []dictionary.Definition{
+				{PartOfSpeech: "noun", Meaning: "A square-rigged vessel."},
+			},
+		},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, new(textPrinter).Print(&b, "snow", entries))
+	expected := `snow¹ /snəʊ/
+[verb] To have snow fall from the sky.
+
+snow²
+[noun] A square-rigged vessel.
+`
+	if got := b.String(); got != expected {
+		t.Errorf("got %s, expected %s", got, expected)
+	}
+}
+
+func TestTextPrinter_Sources(t *testing.T) {
+	license := dictionary.License{Name: "CC BY-SA 3.0", URL: "https://creativecommons.org/licenses/by-sa/3.0"}
+	entries := []dictionary.Entry{
+		{
+			Definitions: []dictionary.Definition{{PartOfSpeech: "verb", Meaning: "To have snow fall from the sky."}},
+			SourceURLs:  []string{"https://en.wiktionary.org/wiki/snow"},
+			License:     license,
+		},
+		{
+			Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "A square-rigged vessel."}},
+			SourceURLs:  []string{"https://en.wiktionary.org/wiki/snow"},
+			License:     license,
+		},
+	}
+
+	t.Run("hidden by default", func(t *testing.T) {
+		var b bytes.Buffer
+		require.NoError(t, new(textPrinter).Print(&b, "snow", entries[:1]))
+		assert.Equal(t, "snow\n[verb] To have snow fall from the sky.\n", b.String())
+	})
+
+	t.Run("attribution deduplicated across entries", func(t *testing.T) {
+		var b bytes.Buffer
+		require.NoError(t, (&textPrinter{sources: true}).Print(&b, "snow", entries))
+		assert.Equal(t, `snow¹
+[verb] To have snow fall from the sky.
+
+snow²
+[noun] A square-rigged vessel.
+
+Source: https://en.wiktionary.org/wiki/snow
+License: CC BY-SA 3.0 (https://creativecommons.org/licenses/by-sa/3.0)
+`, b.String())
+	})
+
+	t.Run("no attribution available", func(t *testing.T) {
+		var b bytes.Buffer
+		require.NoError(t, (&textPrinter{sources: true}).Print(&b, "snow", []dictionary.Entry{{
+			Definitions: []dictionary.Definition{{PartOfSpeech: "verb", Meaning: "To have snow fall from the sky."}},
+		}}))
+		assert.Equal(t, "snow\n[verb] To have snow fall from the sky.\n", b.String())
+	})
+}
+
+func TestSuperscript(t *testing.T) {
+	assert.Equal(t, "¹", superscript(1))
+	assert.Equal(t, "¹²", superscript(12))
+	assert.Equal(t, "¹⁰", superscript(10))
+}
+
 func TestJsonPrinter(t *testing.T) {
 	cases := []struct {
-		name        string
-		word        string
-		definitions []dictionary.Definition
-		expected    string
+		name     string
+		word     string
+		entries  []dictionary.Entry
+		expected string
 	}{
 		{
-			name: "single entry",
+			name: "single definition",
 			word: "guava",
-			definitions: []dictionary.Definition{
+			entries: []dictionary.Entry{{
+				Definitions: []dictionary.Definition{
+					{PartOfSpeech: "noun", Meaning: "A tropical tree or shrub of the myrtle family"},
+				},
+			}},
+			expected: `{
+	"Word": "guava",
+	"Entries": [
+		{
+			"Definitions": [
 				{
-					PartOfSpeech: "noun", Meaning: "A tropical tree or shrub of the myrtle family",
+					"PartOfSpeech": "noun",
+					"Meaning": "A tropical tree or shrub of the myrtle family"
+				}
+			]
+		}
+	]
+}
+`,
+		},
+		{
+			name: "multiple definitions",
+			word: "super",
+			entries: []dictionary.Entry{{
+				Definitions: []dictionary.Definition{
+					{PartOfSpeech: "adjective", Meaning: "Of excellent quality"},
+					{PartOfSpeech: "adverb", Meaning: "Very; extremely"},
 				},
-			},
+			}},
+			expected: `{
+	"Word": "super",
+	"Entries": [
+		{
+			"Definitions": [
+				{
+					"PartOfSpeech": "adjective",
+					"Meaning": "Of excellent quality"
+				},
+				{
+					"PartOfSpeech": "adverb",
+					"Meaning": "Very; extremely"
+				}
+			]
+		}
+	]
+}
+`,
+		},
+		{
+			name: "with provider",
+			word: "guava",
+			entries: []dictionary.Entry{{
+				Definitions: []dictionary.Definition{
+					{PartOfSpeech: "noun", Meaning: "A tropical tree or shrub of the myrtle family"},
+				},
+				Provider: "offline",
+			}},
 			expected: `{
 	"Word": "guava",
-	"Definitions": [
+	"Entries": [
+		{
+			"Definitions": [
+				{
+					"PartOfSpeech": "noun",
+					"Meaning": "A tropical tree or shrub of the myrtle family"
+				}
+			],
+			"Provider": "offline"
+		}
+	]
+}
+`,
+		},
+		{
+			name: "with phonetics",
+			word: "snow",
+			entries: []dictionary.Entry{{
+				Phonetics: []dictionary.Phonetic{
+					{Text: "/snoʊ/", Audio: "https://example.com/snow-us.mp3"},
+				},
+				Definitions: []dictionary.Definition{
+					{PartOfSpeech: "noun", Meaning: "A shade of the color white."},
+					{PartOfSpeech: "verb", Meaning: "To have snow fall from the sky.", Example: "It is snowing."},
+				},
+			}},
+			expected: `{
+	"Word": "snow",
+	"Entries": [
 		{
-			"PartOfSpeech": "noun",
-			"Meaning": "A tropical tree or shrub of the myrtle family"
+			"Phonetics": [
+				{
+					"Text": "/snoʊ/",
+					"Audio": "https://example.com/snow-us.mp3"
+				}
+			],
+			"Definitions": [
+				{
+					"PartOfSpeech": "noun",
+					"Meaning": "A shade of the color white."
+				},
+				{
+					"PartOfSpeech": "verb",
+					"Meaning": "To have snow fall from the sky.",
+					"Example": "It is snowing."
+				}
+			]
 		}
 	]
 }
@@ -317,21 +882,40 @@ func TestJsonPrinter(t *testing.T) {
 		},
 		{
 			name: "multiple entries",
-			word: "super",
-			definitions: []dictionary.Definition{
-				{PartOfSpeech: "adjective", Meaning: "Of excellent quality"},
-				{PartOfSpeech: "adverb", Meaning: "Very; extremely"},
+			word: "snow",
+			entries: []dictionary.Entry{
+				{Definitions: []dictionary.Definition{{PartOfSpeech: "verb", Meaning: "To have snow fall from the sky."}}},
+				{
+					Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "A square-rigged vessel."}},
+					SourceURLs:  []string{"https://en.wiktionary.org/wiki/snow"},
+					License:     dictionary.License{Name: "CC BY-SA 3.0", URL: "https://creativecommons.org/licenses/by-sa/3.0"},
+				},
 			},
 			expected: `{
-	"Word": "super",
-	"Definitions": [
+	"Word": "snow",
+	"Entries": [
 		{
-			"PartOfSpeech": "adjective",
-			"Meaning": "Of excellent quality"
+			"Definitions": [
+				{
+					"PartOfSpeech": "verb",
+					"Meaning": "To have snow fall from the sky."
+				}
+			]
 		},
 		{
-			"PartOfSpeech": "adverb",
-			"Meaning": "Very; extremely"
+			"Definitions": [
+				{
+					"PartOfSpeech": "noun",
+					"Meaning": "A square-rigged vessel."
+				}
+			],
+			"SourceURLs": [
+				"https://en.wiktionary.org/wiki/snow"
+			],
+			"License": {
+				"Name": "CC BY-SA 3.0",
+				"URL": "https://creativecommons.org/licenses/by-sa/3.0"
+			}
 		}
 	]
 }
@@ -343,7 +927,7 @@ func TestJsonPrinter(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			var b bytes.Buffer
 			printer := new(jsonPrinter)
-			if err := printer.Print(&b, test.word, test.definitions); err != nil {
+			if err := printer.Print(&b, test.word, test.entries); err != nil {
 				t.Errorf("failed to print definition: %v", err)
 			}
 
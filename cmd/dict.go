@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/caproven/termdict/dictionary/dump"
+	"github.com/spf13/cobra"
+)
+
+// Formats of dictionary dumps which can be imported
+const (
+	formatWiktextract = "wiktextract"
+	formatStarDict    = "stardict"
+)
+
+// importBatchSize is the number of dump records or bundle words imported per transaction
+const importBatchSize = 1000
+
+type dictImportOptions struct {
+	file    string
+	format  string
+	lang    string
+	langSet bool
+	replace bool
+}
+
+// NewDictCommand constructs the dict command
+func NewDictCommand(cfg *Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dict",
+		Short: "Manage the offline dictionary",
+		Long: `Manage the offline dictionary, which defines words without network access.
+
+Use it by choosing the offline provider:
+  termdict define --provider offline organic`,
+	}
+
+	cmd.AddCommand(NewDictImportCommand(cfg))
+
+	return cmd
+}
+
+// NewDictImportCommand constructs the dict import command
+func NewDictImportCommand(cfg *Config) *cobra.Command {
+	o := &dictImportOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "import file",
+		Short: "Import a dictionary dump into the offline dictionary",
+		Long: `Import a local dictionary dump into the offline dictionary. Supported formats are:
+
+  wiktextract  JSONL dumps from https://kaikki.org, optionally gzip compressed (.jsonl, .jsonl.gz)
+  stardict     StarDict dictionaries, given by their .ifo file (.ifo with .idx and .dict alongside)
+
+The format is detected from the file extension unless given with --format. Entries are added to any words already
+imported, skipping those imported before; use --replace to clear the offline dictionary first. Entries are imported
+in batches, so an import which fails part way keeps the batches imported before it failed. With --replace, the
+dictionary is cleared along with the first batch, so it's only left empty if the dump is.
+
+Sample usage:
+  termdict dict import kaikki.org-dictionary-English.jsonl
+  termdict dict import --lang de kaikki.org-dictionary-German.jsonl.gz
+  termdict dict import --replace --lang es spanish.ifo`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.file = args[0]
+			o.langSet = cmd.Flags().Changed("lang")
+
+			if cfg.Dumps == nil {
+				return errors.New("offline dictionary not available")
+			}
+			return o.run(cmd.Context(), cfg.Out, cmd.ErrOrStderr(), cfg.Dumps)
+		},
+	}
+
+	cmd.Flags().StringVar(&o.format, "format", "", "format of the dump; one of wiktextract, stardict")
+	cmd.Flags().StringVar(&o.lang, "lang", cfg.language(), "language of the words in a StarDict dump; for Wiktextract dumps, only import words in this language")
+	cmd.Flags().BoolVar(&o.replace, "replace", false, "clear the offline dictionary before importing")
+
+	return cmd
+}
+
+func (o *dictImportOptions) run(ctx context.Context, out, errOut io.Writer, d DumpImporter) error {
+	format := o.format
+	if format == "" {
+		var err error
+		if format, err = detectFormat(o.file); err != nil {
+			return err
+		}
+	}
+
+	imp := &batchImporter{ctx: ctx, importer: d, replace: o.replace}
+	var err error
+	switch format {
+	case formatWiktextract:
+		err = o.importWiktextract(imp, errOut)
+	case formatStarDict:
+		err = o.importStarDict(imp, errOut)
+	default:
+		return fmt.Errorf("unknown dump format %s", format)
+	}
+	if err == nil {
+		err = imp.flush()
+	}
+	// Finish the progress line
+	_, _ = fmt.Fprintln(errOut)
+	if err != nil {
+		return fmt.Errorf("import %s: %w", o.file, err)
+	}
+
+	_, _ = fmt.Fprintf(out, "Imported %d of %d entries from %s\n", imp.added, imp.read(), o.file)
+	return nil
+}
+
+func (o *dictImportOptions) importWiktextract(imp *batchImporter, errOut io.Writer) error {
+	f, err := os.Open(o.file)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			slog.Warn("Failed to close file", "error", err)
+		}
+	}()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	// Progress is measured in bytes of the file, which are compressed for gzipped dumps
+	counter := &countingReader{r: f}
+	p := &progress{w: errOut, total: info.Size(), action: "Importing", unit: "entries"}
+	var r io.Reader = counter
+	if strings.HasSuffix(o.file, ".gz") {
+		gz, err := gzip.NewReader(counter)
+		if err != nil {
+			return fmt.Errorf("decompress: %w", err)
+		}
+		defer func() {
+			if err := gz.Close(); err != nil {
+				slog.Warn("Failed to close gzip reader", "error", err)
+			}
+		}()
+		r = gz
+	}
+
+	lang := ""
+	if o.langSet {
+		lang = o.lang
+	}
+	return dump.ReadWiktextract(r, lang, func(record dump.Record) error {
+		if err := imp.add(record); err != nil {
+			return err
+		}
+		p.update(counter.n, imp.read())
+		return nil
+	})
+}
+
+func (o *dictImportOptions) importStarDict(imp *batchImporter, errOut io.Writer) error {
+	d, err := dump.OpenStarDict(o.file)
+	if err != nil {
+		return err
+	}
+
+	p := &progress{w: errOut, total: int64(d.WordCount), action: "Importing", unit: "entries"}
+	var read int64
+	return d.Read(o.lang, func(record dump.Record) error {
+		if err := imp.add(record); err != nil {
+			return err
+		}
+		read++
+		p.update(read, imp.read())
+		return nil
+	})
+}
+
+// detectFormat determines the format of a dump from its file extension
+func detectFormat(file string) (string, error) {
+	name := strings.TrimSuffix(strings.ToLower(file), ".gz")
+	switch filepath.Ext(name) {
+	case ".jsonl", ".json":
+		return formatWiktextract, nil
+	case ".ifo":
+		return formatStarDict, nil
+	default:
+		return "", fmt.Errorf("can't detect format of %s; use --format", file)
+	}
+}
+
+// batchImporter imports records in batches, so dumps needn't fit in memory and each transaction stays small.
+type batchImporter struct {
+	ctx      context.Context
+	importer DumpImporter
+	// replace clears the words imported before along with the first batch
+	replace bool
+	batch   []dump.Record
+	// imported is the number of records given to the importer, of which added were new
+	imported int
+	added    int
+}
+
+func (b *batchImporter) add(record dump.Record) error {
+	b.batch = append(b.batch, record)
+	if len(b.batch) < importBatchSize {
+		return nil
+	}
+	return b.flush()
+}
+
+// read returns the number of records given to the importer so far
+func (b *batchImporter) read() int {
+	return b.imported + len(b.batch)
+}
+
+func (b *batchImporter) flush() error {
+	// An empty dump still replaces the words imported before
+	if len(b.batch) == 0 && !b.replace {
+		return nil
+	}
+	added, err := b.importer.ImportRecords(b.ctx, b.batch, b.replace)
+	if err != nil {
+		return err
+	}
+	b.replace = false
+	b.imported += len(b.batch)
+	b.added += added
+	b.batch = b.batch[:0]
+	return nil
+}
+
+// progress reports how far through a long running task a command is, rewriting a single line whenever the percentage
+// changes.
+type progress struct {
+	w     io.Writer
+	total int64
+	// action describes the task, such as "Importing"
+	action string
+	// unit names the things counted, such as "entries"
+	unit    string
+	percent int64
+	started bool
+}
+
+func (p *progress) update(done int64, count int) {
+	percent := int64(100)
+	if p.total > 0 {
+		percent = min(100*done/p.total, 100)
+	}
+	if p.started && percent == p.percent {
+		return
+	}
+	p.started = true
+	p.percent = percent
+	_, _ = fmt.Fprintf(p.w, "\r%s... %3d%% (%d %s)", p.action, percent, count, p.unit)
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
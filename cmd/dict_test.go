@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/caproven/termdict/dictionary"
+	"github.com/caproven/termdict/dictionary/dump"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDictImportCmd(t *testing.T) {
+	const wiktextract = `{"word": "snow", "lang_code": "en", "pos": "noun", "senses": [{"glosses": ["Frozen rain."]}]}
+{"word": "Schnee", "lang_code": "de", "pos": "noun", "senses": [{"glosses": ["snow"]}]}
+`
+	snow := dump.Record{Word: "snow", Language: "en", Entry: dictionary.Entry{
+		Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "Frozen rain."}},
+	}}
+	schnee := dump.Record{Word: "Schnee", Language: "de", Entry: dictionary.Entry{
+		Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "snow"}},
+	}}
+
+	writeDump := func(t *testing.T, name string) string {
+		path := filepath.Join(t.TempDir(), name)
+		require.NoError(t, os.WriteFile(path, []byte(wiktextract), 0o600))
+		return path
+	}
+
+	t.Run("import wiktextract", func(t *testing.T) {
+		importer := &mockDumpImporter{}
+		defer importer.AssertExpectations(t)
+		importer.On("ImportRecords", mock.Anything, []dump.Record{snow, schnee}, false).Return(2, nil).Once()
+
+		path := writeDump(t, "dump.jsonl")
+		var out, errOut bytes.Buffer
+		cmd := NewRootCmd(&Config{Out: &out, Dumps: importer})
+		cmd.SetErr(&errOut)
+		cmd.SetArgs([]string{"dict", "import", path})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "Imported 2 of 2 entries from "+path+"\n", out.String())
+		assert.Contains(t, errOut.String(), "100%")
+	})
+
+	t.Run("only import words in language", func(t *testing.T) {
+		importer := &mockDumpImporter{}
+		defer importer.AssertExpectations(t)
+		importer.On("ImportRecords", mock.Anything, []dump.Record{schnee}, false).Return(1, nil).Once()
+
+		cmd := NewRootCmd(&Config{Out: &bytes.Buffer{}, Dumps: importer})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"dict", "import", "--lang", "de", writeDump(t, "dump.jsonl")})
+
+		require.NoError(t, cmd.Execute())
+	})
+
+	t.Run("replace existing words", func(t *testing.T) {
+		importer := &mockDumpImporter{}
+		defer importer.AssertExpectations(t)
+		importer.On("ImportRecords", mock.Anything, []dump.Record{snow, schnee}, true).Return(2, nil).Once()
+
+		cmd := NewRootCmd(&Config{Out: &bytes.Buffer{}, Dumps: importer})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"dict", "import", "--replace", writeDump(t, "dump.jsonl")})
+
+		require.NoError(t, cmd.Execute())
+	})
+
+	t.Run("entries imported before are skipped", func(t *testing.T) {
+		importer := &mockDumpImporter{}
+		defer importer.AssertExpectations(t)
+		importer.On("ImportRecords", mock.Anything, []dump.Record{snow, schnee}, false).Return(0, nil).Once()
+
+		path := writeDump(t, "dump.jsonl")
+		var out bytes.Buffer
+		cmd := NewRootCmd(&Config{Out: &out, Dumps: importer})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"dict", "import", path})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "Imported 0 of 2 entries from "+path+"\n", out.String())
+	})
+
+	t.Run("replace with an empty dump", func(t *testing.T) {
+		importer := &mockDumpImporter{}
+		defer importer.AssertExpectations(t)
+		importer.On("ImportRecords", mock.Anything, []dump.Record(nil), true).Return(0, nil).Once()
+
+		path := filepath.Join(t.TempDir(), "empty.jsonl")
+		require.NoError(t, os.WriteFile(path, nil, 0o600))
+		cmd := NewRootCmd(&Config{Out: &bytes.Buffer{}, Dumps: importer})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"dict", "import", "--replace", path})
+
+		require.NoError(t, cmd.Execute())
+	})
+
+	t.Run("explicit format", func(t *testing.T) {
+		importer := &mockDumpImporter{}
+		defer importer.AssertExpectations(t)
+		importer.On("ImportRecords", mock.Anything, mock.Anything, false).Return(2, nil).Once()
+
+		cmd := NewRootCmd(&Config{Out: &bytes.Buffer{}, Dumps: importer})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"dict", "import", "--format", "wiktextract", writeDump(t, "dump.txt")})
+
+		require.NoError(t, cmd.Execute())
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		cmd := NewRootCmd(&Config{Out: &bytes.Buffer{}, Dumps: &mockDumpImporter{}})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"dict", "import", writeDump(t, "dump.txt")})
+
+		require.ErrorContains(t, cmd.Execute(), "--format")
+	})
+
+	t.Run("failure importing", func(t *testing.T) {
+		importer := &mockDumpImporter{}
+		defer importer.AssertExpectations(t)
+		importer.On("ImportRecords", mock.Anything, mock.Anything, false).Return(0, errors.New("failure")).Once()
+
+		cmd := NewRootCmd(&Config{Out: &bytes.Buffer{}, Dumps: importer})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"dict", "import", writeDump(t, "dump.jsonl")})
+
+		require.Error(t, cmd.Execute())
+	})
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := map[string]struct {
+		file    string
+		want    string
+		wantErr bool
+	}{
+		"jsonl":          {file: "dump.jsonl", want: formatWiktextract},
+		"gzipped jsonl":  {file: "dump.JSONL.gz", want: formatWiktextract},
+		"stardict":       {file: "/dicts/german.ifo", want: formatStarDict},
+		"unknown format": {file: "dump.txt", wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := detectFormat(tt.file)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/caproven/termdict/dictionary"
+)
+
+// Process exit codes. Scripts can rely on these to tell failures apart.
+const (
+	// ExitOK means the command succeeded
+	ExitOK = 0
+	// ExitError means the command failed for a reason without a more specific code
+	ExitError = 1
+	// ExitNotFound means a word has no definitions, which usually indicates a typo
+	ExitNotFound = 2
+	// ExitRateLimited means the dictionary service is rejecting requests for being sent too often
+	ExitRateLimited = 3
+	// ExitServerError means the dictionary service failed to handle a request
+	ExitServerError = 4
+	// ExitNetworkError means the dictionary service couldn't be reached
+	ExitNetworkError = 5
+)
+
+// ExitCode returns the process exit code for an error returned by a command.
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, dictionary.ErrNotFound):
+		return ExitNotFound
+	case errors.Is(err, dictionary.ErrRateLimited):
+		return ExitRateLimited
+	case errors.Is(err, dictionary.ErrServer):
+		return ExitServerError
+	case errors.Is(err, dictionary.ErrTransport), errors.Is(err, dictionary.ErrOffline):
+		return ExitNetworkError
+	default:
+		return ExitError
+	}
+}
+
+// ErrorMessage returns a message describing an error returned by a command, suitable for showing to the user.
+func ErrorMessage(err error) string {
+	word := "word"
+	var lookupErr *dictionary.LookupError
+	if errors.As(err, &lookupErr) {
+		word = fmt.Sprintf("%q", lookupErr.Word)
+	}
+
+	switch {
+	case errors.Is(err, dictionary.ErrNotFound):
+		return fmt.Sprintf("no definitions found for %s; check the spelling", word)
+	case errors.Is(err, dictionary.ErrRateLimited):
+		return "the dictionary service is rate limiting requests; try again later"
+	case errors.Is(err, dictionary.ErrServer):
+		return fmt.Sprintf("the dictionary service failed to define %s; try again later (%v)", word, err)
+	case errors.Is(err, dictionary.ErrOffline):
+		return fmt.Sprintf("%s isn't cached, so it can't be defined with --offline", word)
+	case errors.Is(err, dictionary.ErrTransport):
+		return fmt.Sprintf("could not reach the dictionary service; check your network connection (%v)", err)
+	default:
+		return err.Error()
+	}
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/caproven/termdict/dictionary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want int
+	}{
+		"no error": {
+			err:  nil,
+			want: ExitOK,
+		},
+		"generic error": {
+			err:  errors.New("failure"),
+			want: ExitError,
+		},
+		"not found": {
+			err:  fmt.Errorf("define word to be added: %w", &dictionary.LookupError{Word: "foo", Err: dictionary.ErrNotFound}),
+			want: ExitNotFound,
+		},
+		"rate limited": {
+			err:  &dictionary.LookupError{Word: "foo", Err: dictionary.ErrRateLimited},
+			want: ExitRateLimited,
+		},
+		"server error": {
+			err:  &dictionary.LookupError{Word: "foo", Err: fmt.Errorf("%w: response status 502", dictionary.ErrServer)},
+			want: ExitServerError,
+		},
+		"network error": {
+			err:  &dictionary.LookupError{Word: "foo", Err: fmt.Errorf("%w: connection refused", dictionary.ErrTransport)},
+			want: ExitNetworkError,
+		},
+		"offline": {
+			err:  &dictionary.LookupError{Word: "foo", Err: dictionary.ErrOffline},
+			want: ExitNetworkError,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExitCode(tt.err))
+		})
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want string
+	}{
+		"generic error": {
+			err:  errors.New("failure"),
+			want: "failure",
+		},
+		"not found": {
+			err:  &dictionary.LookupError{Word: "teh", Err: dictionary.ErrNotFound},
+			want: `no definitions found for "teh"; check the spelling`,
+		},
+		"rate limited": {
+			err:  &dictionary.LookupError{Word: "foo", Err: dictionary.ErrRateLimited},
+			want: "the dictionary service is rate limiting requests; try again later",
+		},
+		"network error": {
+			err:  &dictionary.LookupError{Word: "foo", Err: fmt.Errorf("%w: connection refused", dictionary.ErrTransport)},
+			want: `could not reach the dictionary service; check your network connection (define word "foo": dictionary service unreachable: connection refused)`,
+		},
+		"offline": {
+			err:  &dictionary.LookupError{Word: "foo", Err: dictionary.ErrOffline},
+			want: `"foo" isn't cached, so it can't be defined with --offline`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ErrorMessage(tt.err))
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caproven/termdict/vocab"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+type historyOptions struct {
+	filter vocab.HistoryFilter
+	since  string
+	until  string
+	// eventType is add or remove, or empty for both
+	eventType string
+	output    string
+	printers  map[string]historyPrinter
+	// now is the time durations given to --since and --until are counted back from
+	now time.Time
+}
+
+// NewHistoryCommand constructs the history command
+func NewHistoryCommand(cfg *Config) *cobra.Command {
+	o := &historyOptions{
+		printers: map[string]historyPrinter{},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "history [word]",
+		Short: "Show when words were added to and removed from your vocab list",
+		Long: `Show a timeline of the words added to and removed from your vocab list, oldest first, or only those of a word.
+Changes imported from another machine name the import they came from.
+
+--since and --until take a date (2024-03-01), a date and time (2024-03-01 15:04), or how long ago (12h, 7d).
+
+Sample usage:
+  termdict list history
+  termdict list history serendipity
+  termdict list history --since 7d --type remove
+  termdict list history --since 2024-01-01 --until 2024-02-01 -o json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				o.filter.Word = args[0]
+			}
+			o.now = time.Now()
+
+			return o.run(cmd.Context(), cfg.Out, cfg.Vocab, cfg.language())
+		},
+	}
+
+	o.registerPrinter(new(textHistoryPrinter))
+	o.registerPrinter(new(jsonHistoryPrinter))
+
+	cmd.Flags().StringVar(&o.since, "since", "", "only show changes at or after this time")
+	cmd.Flags().StringVar(&o.until, "until", "", "only show changes before this time")
+	cmd.Flags().StringVar(&o.eventType, "type", "", "only show changes of this type; one of add, remove")
+	cmd.Flags().StringVarP(&o.output, "output", "o", "text", "output format; one of text, json")
+
+	return cmd
+}
+
+func (o *historyOptions) run(ctx context.Context, out io.Writer, v VocabRepo, lang string) error {
+	printer, ok := o.printers[strings.ToLower(o.output)]
+	if !ok {
+		return fmt.Errorf("no printer registered for output %s", o.output)
+	}
+
+	var err error
+	if o.since != "" {
+		if o.filter.Since, err = parseTime(o.since, o.now); err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if o.until != "" {
+		if o.filter.Until, err = parseTime(o.until, o.now); err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+	switch t := vocab.EventType(strings.ToLower(o.eventType)); t {
+	case "", vocab.EventTypeAdd, vocab.EventTypeRemove:
+		o.filter.Type = t
+	default:
+		return fmt.Errorf("invalid type %q; must be one of add, remove", o.eventType)
+	}
+
+	history, err := v.GetHistory(ctx, o.filter)
+	if err != nil {
+		return fmt.Errorf("get history: %w", err)
+	}
+
+	return printer.Print(out, history, lang)
+}
+
+func (o *historyOptions) registerPrinter(p historyPrinter) {
+	o.printers[p.OutputType()] = p
+}
+
+// timeLayouts are the layouts times given to flags are parsed with, in the local time zone unless they say otherwise
+var timeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02"}
+
+// parseTime parses a time given to a flag, either as a date and maybe a time, or as how long before now, such as 12h or
+// 7d
+func parseTime(s string, now time.Time) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	// time.ParseDuration has no unit for days
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("%q isn't a date, such as 2024-03-01, or a duration, such as 12h or 7d", s)
+}
+
+type historyPrinter interface {
+	OutputType() string
+	// Print writes the history. Words in languages other than lang are tagged with theirs.
+	Print(w io.Writer, history []vocab.HistoryEntry, lang string) error
+}
+
+type textHistoryPrinter struct{}
+
+func (p *textHistoryPrinter) OutputType() string {
+	return "text"
+}
+
+func (p *textHistoryPrinter) Print(w io.Writer, history []vocab.HistoryEntry, lang string) error {
+	if len(history) == 0 {
+		_, err := fmt.Fprintln(w, "no history found")
+		return err
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	for _, entry := range history {
+		// Pad before coloring, since escape codes would count towards the width
+		action := green(fmt.Sprintf("%-7s", "added"))
+		if entry.Type == vocab.EventTypeRemove {
+			action = red(fmt.Sprintf("%-7s", "removed"))
+		}
+		line := fmt.Sprintf("%s  %s  %s", formatTime(time.Unix(entry.Timestamp, 0)), action, entry.Word)
+		if entry.Language != lang {
+			line += fmt.Sprintf(" [%s]", entry.Language)
+		}
+		if entry.Import != nil {
+			line += fmt.Sprintf(" (imported from %s", entry.Import.Source)
+			if !entry.Import.At.IsZero() {
+				line += " on " + formatTime(entry.Import.At)
+			}
+			line += ")"
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// formatTime formats a time in the local time zone to the minute
+func formatTime(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+type jsonHistoryPrinter struct{}
+
+func (p *jsonHistoryPrinter) OutputType() string {
+	return "json"
+}
+
+func (p *jsonHistoryPrinter) Print(w io.Writer, history []vocab.HistoryEntry, _ string) error {
+	if history == nil {
+		history = []vocab.HistoryEntry{}
+	}
+	return writeJSON(w, history)
+}
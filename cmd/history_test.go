@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/caproven/termdict/vocab"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// useUTC makes UTC the local time zone for the rest of the test
+func useUTC(t *testing.T) {
+	local := time.Local
+	time.Local = time.UTC
+	t.Cleanup(func() { time.Local = local })
+}
+
+func TestHistoryCmd(t *testing.T) {
+	useUTC(t)
+	day := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	history := []vocab.HistoryEntry{
+		{Event: vocab.Event{ID: "01J3XYZ1", Type: vocab.EventTypeAdd, Word: "snow", Language: "en", Timestamp: day.Unix()}},
+		{Event: vocab.Event{ID: "01J3XYZ2", Type: vocab.EventTypeAdd, Word: "schnee", Language: "de", Timestamp: day.Add(time.Hour).Unix()}},
+		{
+			Event:  vocab.Event{ID: "01J3XYZ3", Type: vocab.EventTypeRemove, Word: "snow", Language: "en", Timestamp: day.Add(24 * time.Hour).Unix()},
+			Import: &vocab.Import{Source: "laptop.jsonl", At: day.Add(48 * time.Hour)},
+		},
+	}
+
+	t.Run("text output", func(t *testing.T) {
+		vocabRepo := &mockVocabRepo{}
+		defer vocabRepo.AssertExpectations(t)
+		vocabRepo.On("GetHistory", mock.Anything, vocab.HistoryFilter{}).Return(history, nil).Once()
+
+		var b bytes.Buffer
+		cmd := NewRootCmd(&Config{Out: &b, Vocab: vocabRepo})
+		cmd.SetArgs([]string{"list", "history"})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, `2024-03-01 12:00  added    snow
+2024-03-01 13:00  added    schnee [de]
+2024-03-02 12:00  removed  snow (imported from laptop.jsonl on 2024-03-03 12:00)
+`, b.String())
+	})
+
+	t.Run("json output", func(t *testing.T) {
+		vocabRepo := &mockVocabRepo{}
+		defer vocabRepo.AssertExpectations(t)
+		vocabRepo.On("GetHistory", mock.Anything, vocab.HistoryFilter{Word: "snow"}).Return(history[2:], nil).Once()
+
+		var b bytes.Buffer
+		cmd := NewRootCmd(&Config{Out: &b, Vocab: vocabRepo})
+		cmd.SetArgs([]string{"list", "history", "snow", "-o", "json"})
+
+		require.NoError(t, cmd.Execute())
+		assert.JSONEq(t, `[{
+			"id": "01J3XYZ3",
+			"type": "remove",
+			"word": "snow",
+			"language": "en",
+			"timestamp": 1709380800,
+			"import": {"source": "laptop.jsonl", "at": "2024-03-03T12:00:00Z"}
+		}]`, b.String())
+	})
+
+	t.Run("no history", func(t *testing.T) {
+		for output, want := range map[string]string{"text": "no history found\n", "json": "[]\n"} {
+			vocabRepo := &mockVocabRepo{}
+			vocabRepo.On("GetHistory", mock.Anything, mock.Anything).Return(nil, nil).Once()
+
+			var b bytes.Buffer
+			cmd := NewRootCmd(&Config{Out: &b, Vocab: vocabRepo})
+			cmd.SetArgs([]string{"list", "history", "-o", output})
+
+			require.NoError(t, cmd.Execute())
+			assert.Equal(t, want, b.String())
+		}
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		vocabRepo := &mockVocabRepo{}
+		defer vocabRepo.AssertExpectations(t)
+		want := vocab.HistoryFilter{
+			Since: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Until: time.Date(2024, 2, 1, 9, 30, 0, 0, time.UTC),
+			Type:  vocab.EventTypeRemove,
+		}
+		vocabRepo.On("GetHistory", mock.Anything, want).Return(nil, nil).Once()
+
+		cmd := NewRootCmd(&Config{Out: &bytes.Buffer{}, Vocab: vocabRepo})
+		cmd.SetArgs([]string{"list", "history", "--since", "2024-01-01", "--until", "2024-02-01 09:30", "--type", "remove"})
+
+		require.NoError(t, cmd.Execute())
+	})
+
+	t.Run("invalid flags", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"--since", "last week"},
+			{"--until", "-3d"},
+			{"--type", "rename"},
+			{"-o", "yaml"},
+		} {
+			cmd := NewRootCmd(&Config{Out: &bytes.Buffer{}, Vocab: &mockVocabRepo{}})
+			cmd.SetArgs(append([]string{"list", "history"}, args...))
+
+			assert.Error(t, cmd.Execute(), "args %v", args)
+		}
+	})
+}
+
+func TestParseTime(t *testing.T) {
+	useUTC(t)
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]time.Time{
+		"2024-03-01":           time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		"2024-03-01 15:04":     time.Date(2024, 3, 1, 15, 4, 0, 0, time.UTC),
+		"2024-03-01T15:04:05Z": time.Date(2024, 3, 1, 15, 4, 5, 0, time.UTC),
+		"12h":                  time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+		"7d":                   time.Date(2024, 3, 3, 12, 0, 0, 0, time.UTC),
+	}
+	for s, want := range tests {
+		got, err := parseTime(s, now)
+		require.NoError(t, err, s)
+		assert.True(t, want.Equal(got), "parseTime(%q) = %v, expected %v", s, got, want)
+	}
+
+	_, err := parseTime("yesterday", now)
+	assert.Error(t, err)
+}
@@ -3,9 +3,11 @@ package cmd
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 
 	"github.com/caproven/termdict/vocab"
 	"github.com/spf13/cobra"
@@ -17,20 +19,34 @@ func NewImportCommand(cfg *Config) *cobra.Command {
 	o := &importOptions{}
 
 	cmd := &cobra.Command{
-		Use:   "import",
+		Use:   "import [file]",
 		Short: "Import vocab events previously exported from another machine",
-		Args: cobra.NoArgs,
-		RunE: func(cmd *cobra.Command, _ []string) error {
-			return o.run(cmd.Context(), cfg.Out, cfg.Vocab)
+		Long: `Import vocab events exported with "termdict list export" on another machine. Events are read from the given
+file, or from standard input if there's none, and "termdict list history" shows which import each came from.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			in, source := cmd.InOrStdin(), "standard input"
+			if len(args) == 1 {
+				f, err := os.Open(args[0])
+				if err != nil {
+					return fmt.Errorf("open events: %w", err)
+				}
+				defer func() {
+					err = errors.Join(err, f.Close())
+				}()
+				in, source = f, filepath.Base(args[0])
+			}
+
+			return o.run(cmd.Context(), cfg.Out, in, source, cfg.Vocab)
 		},
 	}
 
 	return cmd
 }
 
-func (o *importOptions) run(ctx context.Context, out io.Writer, v VocabRepo) error {
+func (o *importOptions) run(ctx context.Context, out io.Writer, in io.Reader, source string, v VocabRepo) error {
 	var events []vocab.Event
-	dec := json.NewDecoder(os.Stdin)
+	dec := json.NewDecoder(in)
 	for {
 		var event vocab.Event
 		if err := dec.Decode(&event); err != nil {
@@ -42,7 +58,7 @@ func (o *importOptions) run(ctx context.Context, out io.Writer, v VocabRepo) err
 		events = append(events, event)
 	}
 
-	if err := v.AddEvents(ctx, events); err != nil {
+	if _, err := v.AddEvents(ctx, source, events); err != nil {
 		return fmt.Errorf("import events: %w", err)
 	}
 
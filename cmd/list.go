@@ -9,11 +9,13 @@ import (
 )
 
 type listOptions struct {
+	// lang is the default language; words in any other language are tagged with theirs
+	lang string
 }
 
 // NewListCommand constructs the list command
 func NewListCommand(cfg *Config) *cobra.Command {
-	o := &listOptions{}
+	o := &listOptions{lang: cfg.language()}
 
 	cmd := &cobra.Command{
 		Use:   "list",
@@ -30,6 +32,7 @@ Sample usage:
 	cmd.AddCommand(NewAddCommand(cfg))
 	cmd.AddCommand(NewRemoveCommand(cfg))
 	cmd.AddCommand(NewExportCommand(cfg))
+	cmd.AddCommand(NewHistoryCommand(cfg))
 	cmd.AddCommand(NewImportCommand(cfg))
 
 	return cmd
@@ -37,18 +40,22 @@ Sample usage:
 
 // TODO support json
 func (o *listOptions) run(ctx context.Context, out io.Writer, v VocabRepo) error {
-	words, err := v.GetWordsInList(ctx)
+	entries, err := v.GetWordsInList(ctx)
 	if err != nil {
 		return fmt.Errorf("list words: %w", err)
 	}
 
-	if len(words) == 0 {
+	if len(entries) == 0 {
 		_, _ = fmt.Fprintln(out, "no words in vocab list")
 		return nil
 	}
 
-	for _, word := range words {
-		_, _ = fmt.Fprintln(out, word)
+	for _, entry := range entries {
+		if entry.Language != o.lang {
+			_, _ = fmt.Fprintf(out, "%s [%s]\n", entry.Word, entry.Language)
+			continue
+		}
+		_, _ = fmt.Fprintln(out, entry.Word)
 	}
 
 	return nil
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/caproven/termdict/dictionary/dictionarytest"
+	"github.com/caproven/termdict/vocab"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -34,7 +35,7 @@ func TestListCmd(t *testing.T) {
 	t.Run("empty list", func(t *testing.T) {
 		vocabRepo := &mockVocabRepo{}
 		defer vocabRepo.AssertExpectations(t)
-		vocabRepo.On("GetWordsInList", mock.Anything).Return([]string{}, nil).Once()
+		vocabRepo.On("GetWordsInList", mock.Anything).Return([]vocab.Entry{}, nil).Once()
 
 		var b bytes.Buffer
 		cfg := Config{
@@ -55,7 +56,7 @@ func TestListCmd(t *testing.T) {
 	t.Run("single word", func(t *testing.T) {
 		vocabRepo := &mockVocabRepo{}
 		defer vocabRepo.AssertExpectations(t)
-		vocabRepo.On("GetWordsInList", mock.Anything).Return([]string{"kappa"}, nil).Once()
+		vocabRepo.On("GetWordsInList", mock.Anything).Return([]vocab.Entry{{Word: "kappa", Language: "en"}}, nil).Once()
 
 		var b bytes.Buffer
 		cfg := Config{
@@ -76,7 +77,7 @@ func TestListCmd(t *testing.T) {
 	t.Run("multiple words", func(t *testing.T) {
 		vocabRepo := &mockVocabRepo{}
 		defer vocabRepo.AssertExpectations(t)
-		vocabRepo.On("GetWordsInList", mock.Anything).Return([]string{"kappa", "cucumber", "terminal", "dictionary"}, nil).Once()
+		vocabRepo.On("GetWordsInList", mock.Anything).Return([]vocab.Entry{{Word: "kappa", Language: "en"}, {Word: "cucumber", Language: "en"}, {Word: "terminal", Language: "en"}, {Word: "dictionary", Language: "en"}}, nil).Once()
 
 		var b bytes.Buffer
 		cfg := Config{
@@ -93,4 +94,28 @@ func TestListCmd(t *testing.T) {
 
 		assert.Equal(t, "kappa\ncucumber\nterminal\ndictionary\n", b.String())
 	})
+
+	t.Run("words in other languages are tagged", func(t *testing.T) {
+		vocabRepo := &mockVocabRepo{}
+		defer vocabRepo.AssertExpectations(t)
+		vocabRepo.On("GetWordsInList", mock.Anything).Return([]vocab.Entry{
+			{Word: "kappa", Language: "en"},
+			{Word: "schnee", Language: "de"},
+		}, nil).Once()
+
+		var b bytes.Buffer
+		cfg := Config{
+			Out:   &b,
+			Vocab: vocabRepo,
+			Dict:  dictionarytest.InMemoryDefiner{},
+		}
+
+		cmd := NewRootCmd(&cfg)
+		cmd.SetArgs([]string{"list"})
+
+		err := cmd.Execute()
+		require.NoError(t, err)
+
+		assert.Equal(t, "kappa\nschnee [de]\n", b.String())
+	})
 }
@@ -19,7 +19,7 @@ func NewRemoveCommand(cfg *Config) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "remove word ...",
 		Short: "Remove words from your vocab list",
-		Long: `Remove words from your personal vocab list.
+		Long: `Remove words from your personal vocab list, in every language they were added in.
 
 Sample usage:
   termdict list remove efficacy
@@ -2,9 +2,15 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"io"
+	"strings"
+	"time"
 
 	"github.com/caproven/termdict/dictionary"
+	"github.com/caproven/termdict/dictionary/bundle"
+	"github.com/caproven/termdict/dictionary/dump"
+	"github.com/caproven/termdict/eventsync"
 	"github.com/caproven/termdict/vocab"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -15,42 +21,190 @@ type Config struct {
 	Out   io.Writer
 	Vocab VocabRepo
 	Dict  Definer
+	// Language is the default language for lookups. English is used if empty.
+	Language string
+	// Providers are the dictionary providers, in the order they're tried. They're replaced by those chosen with
+	// --provider before a command runs.
+	Providers []string
+	// Merge combines the results of all providers, rather than using the first able to define a word. It's replaced
+	// by --merge before a command runs.
+	Merge bool
+	// Offline defines words only from the cache and offline dictionary, never using the network. It's replaced by
+	// --offline before a command runs.
+	Offline bool
+	// HTTP configures how dictionary services are reached. Its timeout, retries, proxy and CA bundle are replaced by
+	// --timeout, --retries, --proxy and --ca-bundle before a command runs.
+	HTTP dictionary.HTTPOptions
+	// NewDefiner creates a Definer with the given options. When set, Dict is replaced by the Definer for the chosen
+	// providers before a command runs.
+	NewDefiner func(opts DefinerOptions) (Definer, error)
+	// Dumps stores the words of the offline dictionary
+	Dumps DumpImporter
+	// Cache holds the words defined by every provider
+	Cache WordCache
+	// CacheTTL is how long cached words are used before they're defined again. Zero means they never expire.
+	CacheTTL time.Duration
+	// Search finds cached words by what they mean
+	Search Searcher
+	// Custom stores the user's own definitions
+	Custom CustomDefinitions
+	// Sync holds the vocab events exchanged with other devices
+	Sync eventsync.Store
+	// SyncDir is the shared directory the vocab list is synced through when --dir isn't given
+	SyncDir string
+}
+
+// DefinerOptions choose how a Definer made by Config.NewDefiner defines words
+type DefinerOptions struct {
+	// Providers are the dictionary providers to define words with, in the order they're tried
+	Providers []string
+	// Merge combines the results of all providers rather than using the first able to define a word
+	Merge bool
+	// Refresh defines words again rather than using cached entries
+	Refresh bool
+	// NoCache defines words without reading or writing the cache, including words known to have no definitions
+	NoCache bool
+	// Offline defines words only from the cache and offline dictionary, using cached entries even if they've expired
+	Offline bool
+	// HTTP configures how dictionary services are reached
+	HTTP dictionary.HTTPOptions
+}
+
+// language returns the default language for lookups
+func (c *Config) language() string {
+	if c.Language == "" {
+		return dictionary.DefaultLanguage
+	}
+	return c.Language
+}
+
+// ProviderNames returns the provider names with surrounding spaces removed, dropping empty ones, so a list such as
+// "offline, wiktionary" names the providers as they're registered
+func ProviderNames(names []string) []string {
+	var trimmed []string
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			trimmed = append(trimmed, name)
+		}
+	}
+	return trimmed
 }
 
 type Definer interface {
-	Define(ctx context.Context, word string) ([]dictionary.Definition, error)
+	Define(ctx context.Context, word, lang string) ([]dictionary.Entry, error)
+}
+
+// DumpImporter stores words imported from dictionary dumps
+type DumpImporter interface {
+	// ImportRecords adds the entries of records not imported before, returning how many were added. If replace is set,
+	// the words imported before are removed first.
+	ImportRecords(ctx context.Context, records []dump.Record, replace bool) (int, error)
+}
+
+// WordCache holds the words cached by every dictionary provider
+type WordCache interface {
+	CachedWords(ctx context.Context) ([]dictionary.CachedWord, error)
+	CachedEntries(ctx context.Context, word, lang string) ([]dictionary.Entry, error)
+	CacheStats(ctx context.Context) (dictionary.CacheStats, error)
+	RemoveCachedWords(ctx context.Context, words []string, lang string) (int, error)
+	PurgeCache(ctx context.Context) error
+	Vacuum(ctx context.Context) error
+	ExportCache(ctx context.Context, fn func(bundle.Word) error) error
+	ImportCache(ctx context.Context, words []bundle.Word, policy bundle.ConflictPolicy) (int, error)
+}
+
+// CustomDefinitions stores definitions written by the user, which are shown alongside those of providers
+type CustomDefinitions interface {
+	CustomDefinitions(ctx context.Context, word, lang string) ([]dictionary.Definition, error)
+	SetCustomDefinitions(ctx context.Context, word, lang string, defs []dictionary.Definition) error
+}
+
+// Searcher finds cached words by what their definitions mean
+type Searcher interface {
+	Search(ctx context.Context, q dictionary.SearchQuery) ([]dictionary.SearchResult, error)
 }
 
 type VocabRepo interface {
-	AddWordsToList(ctx context.Context, words []string) ([]string, error)
+	AddWordsToList(ctx context.Context, words []string, lang string) ([]string, error)
 	RemoveWordsFromList(ctx context.Context, words []string) ([]string, error)
-	GetWordsInList(ctx context.Context) ([]string, error)
+	GetWordsInList(ctx context.Context) ([]vocab.Entry, error)
 	GetEvents(ctx context.Context) ([]vocab.Event, error)
-	AddEvents(ctx context.Context, events []vocab.Event) error
+	AddEvents(ctx context.Context, source string, events []vocab.Event) (int, error)
+	GetHistory(ctx context.Context, filter vocab.HistoryFilter) ([]vocab.HistoryEntry, error)
 }
 
 type rootOptions struct {
-	noColor bool
+	noColor   bool
+	providers []string
+	merge     bool
+	noCache   bool
+	offline   bool
+	http      dictionary.HTTPOptions
 }
 
 // NewRootCmd creates and returns an instance of the root command
 func NewRootCmd(cfg *Config) *cobra.Command {
-	o := &rootOptions{}
+	o := &rootOptions{http: cfg.HTTP}
 
 	cmd := &cobra.Command{
 		Use:   "termdict",
 		Short: "A small dictionary tool for the command line",
-		PersistentPreRun: func(_ *cobra.Command, _ []string) {
+		Long: `A small dictionary tool for the command line.
+
+Exit codes:
+  0  success
+  1  general failure
+  2  no definitions found for a word
+  3  rate limited by the dictionary service
+  4  dictionary service error
+  5  dictionary service unreachable`,
+		// Errors are reported by the caller, see ErrorMessage and ExitCode
+		SilenceErrors: true,
+		PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
 			if o.noColor {
 				color.NoColor = true
 			}
+			if o.offline && o.noCache {
+				return errors.New("can't use --offline and --no-cache")
+			}
+			cfg.Providers, cfg.Merge, cfg.Offline, cfg.HTTP = ProviderNames(o.providers), o.merge, o.offline, o.http
+			if cfg.NewDefiner != nil {
+				d, err := cfg.NewDefiner(DefinerOptions{
+					Providers: cfg.Providers,
+					Merge:     cfg.Merge,
+					NoCache:   o.noCache,
+					Offline:   cfg.Offline,
+					HTTP:      cfg.HTTP,
+				})
+				if err != nil {
+					return err
+				}
+				cfg.Dict = d
+			}
+			return nil
 		},
 	}
 
 	cmd.PersistentFlags().BoolVar(&o.noColor, "no-color", false, "disable colorized output")
+	cmd.PersistentFlags().StringSliceVar(&o.providers, "provider", cfg.Providers, "dictionary providers to define words with, in order of preference")
+	cmd.PersistentFlags().BoolVar(&o.merge, "merge", cfg.Merge, "combine the definitions of all providers rather than using the first able to define a word")
+
+	cmd.PersistentFlags().BoolVar(&o.noCache, "no-cache", false, "define words without using the cache")
+	cmd.PersistentFlags().BoolVar(&o.offline, "offline", false, "never use the network; define words from the cache and offline dictionary only")
+
+	cmd.PersistentFlags().DurationVar(&o.http.Timeout, "timeout", cfg.HTTP.Timeout, "how long a request to a dictionary service may take; 0 for no limit")
+	cmd.PersistentFlags().IntVar(&o.http.Retries, "retries", cfg.HTTP.Retries, "how many times to retry requests a dictionary service fails or rate limits")
+	cmd.PersistentFlags().StringVar(&o.http.Proxy, "proxy", cfg.HTTP.Proxy, "URL of the proxy to reach dictionary services through")
+	cmd.PersistentFlags().StringVar(&o.http.CABundle, "ca-bundle", cfg.HTTP.CABundle, "PEM file of extra certificate authorities to trust")
 
+	cmd.AddCommand(NewCacheCommand(cfg))
+	cmd.AddCommand(NewDefCommand(cfg))
 	cmd.AddCommand(NewDefineCommand(cfg))
+	cmd.AddCommand(NewDictCommand(cfg))
 	cmd.AddCommand(NewListCommand(cfg))
+	cmd.AddCommand(NewSearchCommand(cfg))
+	cmd.AddCommand(NewSyncCommand(cfg))
+	cmd.AddCommand(NewThesaurusCommand(cfg))
 
 	return cmd
 }
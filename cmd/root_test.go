@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"github.com/caproven/termdict/dictionary"
+	"github.com/caproven/termdict/dictionary/bundle"
+	"github.com/caproven/termdict/dictionary/dump"
 	"github.com/caproven/termdict/vocab"
 	"github.com/stretchr/testify/mock"
 )
@@ -12,8 +14,8 @@ type mockVocabRepo struct {
 	mock.Mock
 }
 
-func (m *mockVocabRepo) AddWordsToList(ctx context.Context, words []string) ([]string, error) {
-	args := m.Called(ctx, words)
+func (m *mockVocabRepo) AddWordsToList(ctx context.Context, words []string, lang string) ([]string, error) {
+	args := m.Called(ctx, words, lang)
 	added, err := args.Get(0), args.Error(1)
 	if added == nil {
 		return nil, err
@@ -30,13 +32,13 @@ func (m *mockVocabRepo) RemoveWordsFromList(ctx context.Context, words []string)
 	return removed.([]string), err
 }
 
-func (m *mockVocabRepo) GetWordsInList(ctx context.Context) ([]string, error) {
+func (m *mockVocabRepo) GetWordsInList(ctx context.Context) ([]vocab.Entry, error) {
 	args := m.Called(ctx)
 	words, err := args.Get(0), args.Error(1)
 	if words == nil {
 		return nil, err
 	}
-	return words.([]string), err
+	return words.([]vocab.Entry), err
 }
 
 func (m *mockVocabRepo) GetEvents(ctx context.Context) ([]vocab.Event, error) {
@@ -48,20 +50,161 @@ func (m *mockVocabRepo) GetEvents(ctx context.Context) ([]vocab.Event, error) {
 	return events.([]vocab.Event), err
 }
 
-func (m *mockVocabRepo) AddEvents(ctx context.Context, events []vocab.Event) error {
-	args := m.Called(ctx, events)
-	return args.Error(0)
+func (m *mockVocabRepo) AddEvents(ctx context.Context, source string, events []vocab.Event) (int, error) {
+	args := m.Called(ctx, source, events)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockVocabRepo) GetHistory(ctx context.Context, filter vocab.HistoryFilter) ([]vocab.HistoryEntry, error) {
+	args := m.Called(ctx, filter)
+	history, err := args.Get(0), args.Error(1)
+	if history == nil {
+		return nil, err
+	}
+	return history.([]vocab.HistoryEntry), err
+}
+
+type mockDumpImporter struct {
+	mock.Mock
+}
+
+func (m *mockDumpImporter) ImportRecords(ctx context.Context, records []dump.Record, replace bool) (int, error) {
+	args := m.Called(ctx, records, replace)
+	return args.Int(0), args.Error(1)
 }
 
 type mockDefiner struct {
 	mock.Mock
 }
 
-func (m *mockDefiner) Define(ctx context.Context, word string) ([]dictionary.Definition, error) {
-	args := m.Called(ctx, word)
+func (m *mockDefiner) Define(ctx context.Context, word, lang string) ([]dictionary.Entry, error) {
+	args := m.Called(ctx, word, lang)
+	entries, err := args.Get(0), args.Error(1)
+	if entries == nil {
+		return nil, err
+	}
+	return entries.([]dictionary.Entry), err
+}
+
+type mockWordCache struct {
+	mock.Mock
+}
+
+func (m *mockWordCache) CachedWords(ctx context.Context) ([]dictionary.CachedWord, error) {
+	args := m.Called(ctx)
+	words, err := args.Get(0), args.Error(1)
+	if words == nil {
+		return nil, err
+	}
+	return words.([]dictionary.CachedWord), err
+}
+
+func (m *mockWordCache) CachedEntries(ctx context.Context, word, lang string) ([]dictionary.Entry, error) {
+	args := m.Called(ctx, word, lang)
+	entries, err := args.Get(0), args.Error(1)
+	if entries == nil {
+		return nil, err
+	}
+	return entries.([]dictionary.Entry), err
+}
+
+func (m *mockWordCache) CacheStats(ctx context.Context) (dictionary.CacheStats, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(dictionary.CacheStats), args.Error(1)
+}
+
+func (m *mockWordCache) RemoveCachedWords(ctx context.Context, words []string, lang string) (int, error) {
+	args := m.Called(ctx, words, lang)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockWordCache) PurgeCache(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *mockWordCache) Vacuum(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *mockWordCache) ExportCache(ctx context.Context, fn func(bundle.Word) error) error {
+	args := m.Called(ctx, fn)
+	if words, ok := args.Get(0).([]bundle.Word); ok {
+		for _, w := range words {
+			if err := fn(w); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *mockWordCache) ImportCache(ctx context.Context, words []bundle.Word, policy bundle.ConflictPolicy) (int, error) {
+	args := m.Called(ctx, words, policy)
+	return args.Int(0), args.Error(1)
+}
+
+type mockSearcher struct {
+	mock.Mock
+}
+
+func (m *mockSearcher) Search(ctx context.Context, q dictionary.SearchQuery) ([]dictionary.SearchResult, error) {
+	args := m.Called(ctx, q)
+	results, err := args.Get(0), args.Error(1)
+	if results == nil {
+		return nil, err
+	}
+	return results.([]dictionary.SearchResult), err
+}
+
+type mockCustomDefinitions struct {
+	mock.Mock
+}
+
+func (m *mockCustomDefinitions) CustomDefinitions(ctx context.Context, word, lang string) ([]dictionary.Definition, error) {
+	args := m.Called(ctx, word, lang)
 	defs, err := args.Get(0), args.Error(1)
 	if defs == nil {
 		return nil, err
 	}
 	return defs.([]dictionary.Definition), err
 }
+
+func (m *mockCustomDefinitions) SetCustomDefinitions(ctx context.Context, word, lang string, defs []dictionary.Definition) error {
+	args := m.Called(ctx, word, lang, defs)
+	return args.Error(0)
+}
+
+type mockSyncStore struct {
+	mock.Mock
+}
+
+func (m *mockSyncStore) DeviceID(ctx context.Context) (string, error) {
+	args := m.Called(ctx)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockSyncStore) EventsSince(ctx context.Context, seq int64, exclude string) ([]vocab.Event, int64, error) {
+	args := m.Called(ctx, seq, exclude)
+	events, err := args.Get(0), args.Error(2)
+	if events == nil {
+		return nil, args.Get(1).(int64), err
+	}
+	return events.([]vocab.Event), args.Get(1).(int64), err
+}
+
+func (m *mockSyncStore) AddEvents(ctx context.Context, source string, events []vocab.Event) (int, error) {
+	args := m.Called(ctx, source, events)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockSyncStore) SyncCursor(ctx context.Context, name string) (string, error) {
+	args := m.Called(ctx, name)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockSyncStore) SetSyncCursor(ctx context.Context, name, cursor string) error {
+	args := m.Called(ctx, name, cursor)
+	return args.Error(0)
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/caproven/termdict/dictionary"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+type searchOptions struct {
+	query    dictionary.SearchQuery
+	output   string
+	printers map[string]searchPrinter
+}
+
+// NewSearchCommand constructs the search command
+func NewSearchCommand(cfg *Config) *cobra.Command {
+	o := &searchOptions{
+		printers: map[string]searchPrinter{},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "search phrase",
+		Short: "Find words by what they mean",
+		Long: `Find cached words whose definitions match a phrase, best match first. Only words which have been defined
+before, or are in the offline dictionary, can be found.
+
+Sample usage:
+  termdict search "rain mixed with snow"
+  termdict search --pos verb "move slowly"
+  termdict search --list-only "feeling of happiness"
+  termdict search -o json "frozen water"`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.Search == nil {
+				return errors.New("search isn't available")
+			}
+			o.query.Phrase = strings.Join(args, " ")
+
+			return o.run(cmd.Context(), cfg.Out, cfg.Search)
+		},
+	}
+
+	o.registerPrinter(new(textSearchPrinter))
+	o.registerPrinter(new(jsonSearchPrinter))
+
+	cmd.Flags().StringVar(&o.query.PartOfSpeech, "pos", "", "only match definitions of this part of speech, such as noun or verb")
+	cmd.Flags().BoolVar(&o.query.ListOnly, "list-only", false, "only find words in your vocab list")
+	cmd.Flags().StringVar(&o.query.Language, "lang", "", "only find words in this language, such as en or de; all languages if unset")
+	cmd.Flags().IntVar(&o.query.Limit, "limit", 10, "most words to find; 0 for no limit")
+	cmd.Flags().StringVarP(&o.output, "output", "o", "text", "output format; one of text, json")
+
+	return cmd
+}
+
+func (o *searchOptions) run(ctx context.Context, out io.Writer, s Searcher) error {
+	printer, ok := o.printers[strings.ToLower(o.output)]
+	if !ok {
+		return fmt.Errorf("no printer registered for output %s", o.output)
+	}
+	if o.query.Limit < 0 {
+		return errors.New("limit can't be negative")
+	}
+
+	results, err := s.Search(ctx, o.query)
+	if err != nil {
+		return fmt.Errorf("search definitions: %w", err)
+	}
+
+	return printer.Print(out, results)
+}
+
+func (o *searchOptions) registerPrinter(p searchPrinter) {
+	o.printers[p.OutputType()] = p
+}
+
+type searchPrinter interface {
+	OutputType() string
+	Print(w io.Writer, results []dictionary.SearchResult) error
+}
+
+type textSearchPrinter struct{}
+
+func (p *textSearchPrinter) OutputType() string {
+	return "text"
+}
+
+func (p *textSearchPrinter) Print(w io.Writer, results []dictionary.SearchResult) error {
+	if len(results) == 0 {
+		_, err := fmt.Fprintln(w, "no matching words found")
+		return err
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	blue := color.New(color.FgCyan).SprintFunc()
+	for i, r := range results {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s (%s)\n", green(r.Word), r.Language); err != nil {
+			return err
+		}
+		for _, def := range r.Definitions {
+			line := def.Meaning
+			if def.PartOfSpeech != "" {
+				line = fmt.Sprintf("[%s] %s", blue(def.PartOfSpeech), def.Meaning)
+			}
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+type jsonSearchPrinter struct{}
+
+func (p *jsonSearchPrinter) OutputType() string {
+	return "json"
+}
+
+func (p *jsonSearchPrinter) Print(w io.Writer, results []dictionary.SearchResult) error {
+	if results == nil {
+		results = []dictionary.SearchResult{}
+	}
+	return writeJSON(w, results)
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/caproven/termdict/dictionary"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchCmd(t *testing.T) {
+	results := []dictionary.SearchResult{
+		{
+			Word:     "sleet",
+			Language: "en",
+			Definitions: []dictionary.Definition{
+				{PartOfSpeech: "noun", Meaning: "Rain mixed with snow or hail."},
+			},
+			Score: 2.5,
+		},
+		{
+			Word:        "hail",
+			Language:    "en",
+			Definitions: []dictionary.Definition{{Meaning: "Balls of ice falling like rain."}},
+			Score:       1.25,
+		},
+	}
+
+	t.Run("text output", func(t *testing.T) {
+		searcher := &mockSearcher{}
+		defer searcher.AssertExpectations(t)
+		searcher.On("Search", mock.Anything, dictionary.SearchQuery{Phrase: "icy rain", Limit: 10}).Return(results, nil).Once()
+
+		var b bytes.Buffer
+		cmd := NewRootCmd(&Config{Out: &b, Search: searcher})
+		cmd.SetArgs([]string{"search", "icy", "rain"})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "sleet (en)\n[noun] Rain mixed with snow or hail.\n\nhail (en)\nBalls of ice falling like rain.\n", b.String())
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		searcher := &mockSearcher{}
+		defer searcher.AssertExpectations(t)
+		want := dictionary.SearchQuery{Phrase: "icy rain", PartOfSpeech: "noun", Language: "en", ListOnly: true, Limit: 3}
+		searcher.On("Search", mock.Anything, want).Return(results[:1], nil).Once()
+
+		cmd := NewRootCmd(&Config{Out: &bytes.Buffer{}, Search: searcher})
+		cmd.SetArgs([]string{"search", "--pos", "noun", "--lang", "en", "--list-only", "--limit", "3", "icy rain"})
+
+		require.NoError(t, cmd.Execute())
+	})
+
+	t.Run("json output", func(t *testing.T) {
+		searcher := &mockSearcher{}
+		defer searcher.AssertExpectations(t)
+		searcher.On("Search", mock.Anything, mock.Anything).Return(results[:1], nil).Once()
+
+		var b bytes.Buffer
+		cmd := NewRootCmd(&Config{Out: &b, Search: searcher})
+		cmd.SetArgs([]string{"search", "-o", "json", "icy rain"})
+
+		require.NoError(t, cmd.Execute())
+		assert.JSONEq(t, `[{
+			"Word": "sleet",
+			"Language": "en",
+			"Definitions": [{"PartOfSpeech": "noun", "Meaning": "Rain mixed with snow or hail."}],
+			"Score": 2.5
+		}]`, b.String())
+	})
+
+	t.Run("nothing found", func(t *testing.T) {
+		for output, want := range map[string]string{
+			"text": "no matching words found\n",
+			"json": "[]\n",
+		} {
+			t.Run(output, func(t *testing.T) {
+				searcher := &mockSearcher{}
+				defer searcher.AssertExpectations(t)
+				searcher.On("Search", mock.Anything, mock.Anything).Return(nil, nil).Once()
+
+				var b bytes.Buffer
+				cmd := NewRootCmd(&Config{Out: &b, Search: searcher})
+				cmd.SetArgs([]string{"search", "-o", output, "xyzzy"})
+
+				require.NoError(t, cmd.Execute())
+				assert.Equal(t, want, b.String())
+			})
+		}
+	})
+
+	t.Run("no phrase", func(t *testing.T) {
+		cmd := NewRootCmd(&Config{Out: &bytes.Buffer{}, Search: &mockSearcher{}})
+		cmd.SetArgs([]string{"search"})
+
+		require.Error(t, cmd.Execute())
+	})
+
+	t.Run("invalid output format", func(t *testing.T) {
+		cmd := NewRootCmd(&Config{Out: &bytes.Buffer{}, Search: &mockSearcher{}})
+		cmd.SetArgs([]string{"search", "-o", "invalid", "icy rain"})
+
+		require.Error(t, cmd.Execute())
+	})
+}
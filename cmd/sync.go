@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/caproven/termdict/eventsync"
+	"github.com/spf13/cobra"
+)
+
+type syncOptions struct {
+	dir string
+}
+
+// NewSyncCommand constructs the sync command
+func NewSyncCommand(cfg *Config) *cobra.Command {
+	o := &syncOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "sync --dir path",
+		Short: "Sync your vocab list with your other devices",
+		Long: `Sync your vocab list with your other devices through a shared directory, such as a Syncthing or Dropbox folder.
+Each device appends the words it adds and removes to a file of its own in the directory, and reads those of the other
+devices from where it last left off. Every device which has synced with the others ends up with the same list.
+
+The directory can be set with the sync_dir setting instead of --dir.
+
+Sample usage:
+  termdict sync --dir ~/Sync/termdict`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if cfg.Sync == nil {
+				return errors.New("sync isn't available")
+			}
+			if o.dir == "" {
+				return errors.New("must specify a directory to sync through with --dir or the sync_dir setting")
+			}
+
+			return o.run(cmd.Context(), cfg.Out, cfg.Sync)
+		},
+	}
+
+	cmd.Flags().StringVar(&o.dir, "dir", cfg.SyncDir, "shared directory to sync through")
+
+	cmd.AddCommand(NewSyncGitCommand(cfg))
+	cmd.AddCommand(NewSyncServeCommand(cfg))
+	cmd.AddCommand(NewSyncPushCommand(cfg))
+	cmd.AddCommand(NewSyncPullCommand(cfg))
+
+	return cmd
+}
+
+func (o *syncOptions) run(ctx context.Context, out io.Writer, store eventsync.Store) error {
+	result, err := eventsync.SyncDir(ctx, store, o.dir)
+	if err != nil {
+		return fmt.Errorf("sync with %s: %w", o.dir, err)
+	}
+
+	_, err = fmt.Fprintf(out, "Synced with %s: received %d events, sent %d\n", o.dir, result.Received, result.Sent)
+	return err
+}
+
+type syncGitOptions struct {
+	repo string
+}
+
+// NewSyncGitCommand constructs the sync git command
+func NewSyncGitCommand(cfg *Config) *cobra.Command {
+	o := &syncGitOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "git repo-path",
+		Short: "Sync your vocab list through a git repository",
+		Long: `Sync your vocab list through a git working copy, such as your dotfiles. The words added and removed on every
+device are kept in ` + eventsync.GitEventFile + `. Sync pulls, adds the changes of other devices, commits this device's
+changes and pushes. Conflicts in the file are resolved by keeping the changes of both sides.
+
+Sample usage:
+  termdict sync git ~/dotfiles`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.Sync == nil {
+				return errors.New("sync isn't available")
+			}
+			o.repo = args[0]
+
+			return o.run(cmd.Context(), cfg.Out, cfg.Sync)
+		},
+	}
+
+	return cmd
+}
+
+func (o *syncGitOptions) run(ctx context.Context, out io.Writer, store eventsync.Store) error {
+	result, err := eventsync.SyncGit(ctx, store, o.repo)
+	if err != nil {
+		return fmt.Errorf("sync with %s: %w", o.repo, err)
+	}
+
+	_, err = fmt.Fprintf(out, "Synced with %s: received %d events, sent %d\n", o.repo, result.Received, result.Sent)
+	return err
+}
+
+// syncTokenEnv names the environment variable holding the sync server token when --token isn't given
+const syncTokenEnv = "TERMDICT_SYNC_TOKEN"
+
+type syncServeOptions struct {
+	addr   string
+	tokens []string
+}
+
+// NewSyncServeCommand constructs the sync serve command
+func NewSyncServeCommand(cfg *Config) *cobra.Command {
+	o := &syncServeOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "serve --addr address",
+		Short: "Host a sync server for your other devices",
+		Long: `Host your vocab list over HTTP, so your other devices can push the words they add and remove to it and pull those
+of the others with "termdict sync push" and "termdict sync pull". The server keeps its events in this device's
+vocab list.
+
+Requests must carry a token given with --token, which can be repeated to give each device its own. The token can be
+set with the ` + syncTokenEnv + ` environment variable instead. The server doesn't serve HTTPS, so put it
+behind a reverse proxy which does if it's reached over an untrusted network.
+
+Sample usage:
+  termdict sync serve --addr :8080 --token "$(cat ~/.termdict-token)"`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if cfg.Sync == nil {
+				return errors.New("sync isn't available")
+			}
+			if len(o.tokens) == 0 {
+				if token := os.Getenv(syncTokenEnv); token != "" {
+					o.tokens = []string{token}
+				}
+			}
+			if len(o.tokens) == 0 {
+				return errors.New("must specify a token with --token or " + syncTokenEnv)
+			}
+			handler, err := eventsync.NewServer(cfg.Sync, o.tokens)
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			ln, err := net.Listen("tcp", o.addr)
+			if err != nil {
+				return fmt.Errorf("listen on %s: %w", o.addr, err)
+			}
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Serving sync on %s\n", ln.Addr())
+			return o.run(ctx, ln, handler)
+		},
+	}
+
+	cmd.Flags().StringVar(&o.addr, "addr", "localhost:8080", "address to listen on")
+	// Tokens from the environment aren't flag defaults, which --help would print
+	cmd.Flags().StringArrayVar(&o.tokens, "token", nil, "token clients must send; may be repeated")
+
+	return cmd
+}
+
+// run serves handler on ln until ctx is done
+func (o *syncServeOptions) run(ctx context.Context, ln net.Listener, handler http.Handler) error {
+	srv := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errs:
+		return fmt.Errorf("serve: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shut down: %w", err)
+	}
+	return nil
+}
+
+type syncRemoteOptions struct {
+	remote string
+	token  string
+}
+
+// NewSyncPushCommand constructs the sync push command
+func NewSyncPushCommand(cfg *Config) *cobra.Command {
+	o := &syncRemoteOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "push --remote url",
+		Short: "Send your changes to a sync server",
+		Long: `Send the words added and removed on this device since the last push to a server hosted with "termdict sync serve".
+Words pulled from the server aren't sent back to it.
+
+The token is given with --token or the ` + syncTokenEnv + ` environment variable.
+
+Sample usage:
+  termdict sync push --remote https://termdict.example.com`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			remote, err := o.remoteFor(cfg)
+			if err != nil {
+				return err
+			}
+			return o.run(cmd.Context(), cfg.Out, cfg.Sync, remote, eventsync.Push)
+		},
+	}
+
+	o.addFlags(cmd)
+
+	return cmd
+}
+
+// NewSyncPullCommand constructs the sync pull command
+func NewSyncPullCommand(cfg *Config) *cobra.Command {
+	o := &syncRemoteOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "pull --remote url",
+		Short: "Get the changes of your other devices from a sync server",
+		Long: `Get the words added and removed on your other devices since the last pull from a server hosted with
+"termdict sync serve".
+
+The token is given with --token or the ` + syncTokenEnv + ` environment variable.
+
+Sample usage:
+  termdict sync pull --remote https://termdict.example.com`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			remote, err := o.remoteFor(cfg)
+			if err != nil {
+				return err
+			}
+			return o.run(cmd.Context(), cfg.Out, cfg.Sync, remote, eventsync.Pull)
+		},
+	}
+
+	o.addFlags(cmd)
+
+	return cmd
+}
+
+func (o *syncRemoteOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.remote, "remote", "", "URL of the sync server")
+	cmd.Flags().StringVar(&o.token, "token", "", "token to send the sync server")
+}
+
+// remoteFor validates the options, returning the sync server they point to
+func (o *syncRemoteOptions) remoteFor(cfg *Config) (eventsync.Remote, error) {
+	if cfg.Sync == nil {
+		return eventsync.Remote{}, errors.New("sync isn't available")
+	}
+	if o.remote == "" {
+		return eventsync.Remote{}, errors.New("must specify a sync server with --remote")
+	}
+	if o.token == "" {
+		o.token = os.Getenv(syncTokenEnv)
+	}
+	if o.token == "" {
+		return eventsync.Remote{}, errors.New("must specify a token with --token or " + syncTokenEnv)
+	}
+	return eventsync.Remote{URL: o.remote, Token: o.token}, nil
+}
+
+type syncRemoteFunc func(ctx context.Context, store eventsync.Store, remote eventsync.Remote) (eventsync.Result, error)
+
+func (o *syncRemoteOptions) run(ctx context.Context, out io.Writer, store eventsync.Store, remote eventsync.Remote, sync syncRemoteFunc) error {
+	result, err := sync(ctx, store, remote)
+	if err != nil {
+		return fmt.Errorf("sync with %s: %w", o.remote, err)
+	}
+
+	_, err = fmt.Fprintf(out, "Synced with %s: received %d events, sent %d\n", o.remote, result.Received, result.Sent)
+	return err
+}
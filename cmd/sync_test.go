@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/caproven/termdict/eventsync"
+	"github.com/caproven/termdict/vocab"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newSyncServer hosts a sync server accepting the token "secret", backed by store
+func newSyncServer(t *testing.T, store eventsync.Store) string {
+	t.Helper()
+	handler, err := eventsync.NewServer(store, []string{"secret"})
+	require.NoError(t, err)
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func TestSyncServe(t *testing.T) {
+	server := &mockSyncStore{}
+	defer server.AssertExpectations(t)
+	server.On("EventsSince", mock.Anything, int64(0), "sync push").Return(nil, int64(0), nil).Once()
+	handler, err := eventsync.NewServer(server, []string{"secret"})
+	require.NoError(t, err)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(t.Context())
+	errs := make(chan error, 1)
+	go func() {
+		errs <- (&syncServeOptions{}).run(ctx, ln, handler)
+	}()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://"+ln.Addr().String()+eventsync.EventsPath, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	cancel()
+	assert.NoError(t, <-errs, "the server shuts down once its context is done")
+}
+
+func TestSyncCmd(t *testing.T) {
+	event := vocab.Event{ID: "01J3XYZ1", Type: vocab.EventTypeAdd, Word: "snow", Language: "en", Timestamp: 100}
+
+	t.Run("dir", func(t *testing.T) {
+		dir := t.TempDir()
+		laptop := `{"id":"01J3XYZ2","type":"add","word":"rain","language":"en","timestamp":200}` + "\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "laptop.jsonl"), []byte(laptop), 0o644))
+
+		store := &mockSyncStore{}
+		defer store.AssertExpectations(t)
+		store.On("DeviceID", mock.Anything).Return("phone", nil)
+		store.On("SyncCursor", mock.Anything, mock.Anything).Return("", nil)
+		store.On("AddEvents", mock.Anything, dir, []vocab.Event{
+			{ID: "01J3XYZ2", Type: vocab.EventTypeAdd, Word: "rain", Language: "en", Timestamp: 200},
+		}).Return(1, nil).Once()
+		store.On("SetSyncCursor", mock.Anything, "dir:"+filepath.Join(dir, "laptop.jsonl"), strconv.Itoa(len(laptop))).Return(nil).Once()
+		store.On("EventsSince", mock.Anything, int64(0), dir).Return([]vocab.Event{event}, int64(1), nil).Once()
+		store.On("SetSyncCursor", mock.Anything, "dir:"+filepath.Join(dir, "phone.jsonl"), "1").Return(nil).Once()
+
+		var b bytes.Buffer
+		cmd := NewRootCmd(&Config{Out: &b, Sync: store})
+		cmd.SetArgs([]string{"sync", "--dir", dir})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "Synced with "+dir+": received 1 events, sent 1\n", b.String())
+		written, err := os.ReadFile(filepath.Join(dir, "phone.jsonl"))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"id":"01J3XYZ1","type":"add","word":"snow","language":"en","timestamp":100}`, string(written))
+	})
+
+	t.Run("dir from config", func(t *testing.T) {
+		dir := t.TempDir()
+		store := &mockSyncStore{}
+		store.On("DeviceID", mock.Anything).Return("phone", nil)
+		store.On("SyncCursor", mock.Anything, mock.Anything).Return("", nil)
+		store.On("EventsSince", mock.Anything, int64(0), dir).Return(nil, int64(0), nil).Once()
+		store.On("SetSyncCursor", mock.Anything, mock.Anything, "0").Return(nil).Once()
+
+		var b bytes.Buffer
+		cmd := NewRootCmd(&Config{Out: &b, Sync: store, SyncDir: dir})
+		cmd.SetArgs([]string{"sync"})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "Synced with "+dir+": received 0 events, sent 0\n", b.String())
+	})
+
+	t.Run("git", func(t *testing.T) {
+		if _, err := exec.LookPath("git"); err != nil {
+			t.Skip("git isn't installed")
+		}
+		t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+		t.Setenv("GIT_AUTHOR_NAME", "Test")
+		t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+		t.Setenv("GIT_COMMITTER_NAME", "Test")
+		t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+		repo := t.TempDir()
+		require.NoError(t, exec.CommandContext(t.Context(), "git", "-C", repo, "init", "--quiet").Run())
+
+		store := &mockSyncStore{}
+		defer store.AssertExpectations(t)
+		store.On("DeviceID", mock.Anything).Return("phone", nil)
+		store.On("SyncCursor", mock.Anything, "git:"+repo).Return("", nil)
+		store.On("SyncCursor", mock.Anything, "git-commit:"+repo).Return("", nil)
+		store.On("EventsSince", mock.Anything, int64(0), repo).Return([]vocab.Event{event}, int64(1), nil).Once()
+		store.On("SetSyncCursor", mock.Anything, "git-commit:"+repo, "1").Return(nil).Once()
+		store.On("SetSyncCursor", mock.Anything, "git:"+repo, mock.Anything).Return(nil).Once()
+
+		var b bytes.Buffer
+		cmd := NewRootCmd(&Config{Out: &b, Sync: store})
+		cmd.SetArgs([]string{"sync", "git", repo})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "Synced with "+repo+": received 0 events, sent 1\n", b.String())
+	})
+
+	t.Run("push", func(t *testing.T) {
+		server := &mockSyncStore{}
+		defer server.AssertExpectations(t)
+		server.On("AddEvents", mock.Anything, "sync push from phone", []vocab.Event{event}).Return(1, nil).Once()
+		url := newSyncServer(t, server)
+
+		store := &mockSyncStore{}
+		defer store.AssertExpectations(t)
+		store.On("DeviceID", mock.Anything).Return("phone", nil)
+		store.On("SyncCursor", mock.Anything, "remote-push:"+url).Return("", nil)
+		store.On("EventsSince", mock.Anything, int64(0), url).Return([]vocab.Event{event}, int64(1), nil).Once()
+		store.On("SetSyncCursor", mock.Anything, "remote-push:"+url, "1").Return(nil).Once()
+
+		var b bytes.Buffer
+		cmd := NewRootCmd(&Config{Out: &b, Sync: store})
+		cmd.SetArgs([]string{"sync", "push", "--remote", url, "--token", "secret"})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "Synced with "+url+": received 0 events, sent 1\n", b.String())
+	})
+
+	t.Run("pull", func(t *testing.T) {
+		server := &mockSyncStore{}
+		defer server.AssertExpectations(t)
+		server.On("EventsSince", mock.Anything, int64(0), "sync push from phone").Return([]vocab.Event{event}, int64(3), nil).Once()
+		url := newSyncServer(t, server)
+
+		t.Setenv("TERMDICT_SYNC_TOKEN", "secret")
+		store := &mockSyncStore{}
+		defer store.AssertExpectations(t)
+		store.On("DeviceID", mock.Anything).Return("phone", nil)
+		store.On("SyncCursor", mock.Anything, "remote-pull:"+url).Return("", nil)
+		store.On("AddEvents", mock.Anything, url, []vocab.Event{event}).Return(1, nil).Once()
+		store.On("SetSyncCursor", mock.Anything, "remote-pull:"+url, mock.AnythingOfType("string")).Return(nil).Once()
+
+		var b bytes.Buffer
+		cmd := NewRootCmd(&Config{Out: &b, Sync: store})
+		cmd.SetArgs([]string{"sync", "pull", "--remote", url})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "Synced with "+url+": received 1 events, sent 0\n", b.String())
+	})
+
+	t.Run("wrong token", func(t *testing.T) {
+		url := newSyncServer(t, &mockSyncStore{})
+		store := &mockSyncStore{}
+		store.On("DeviceID", mock.Anything).Return("phone", nil)
+		store.On("SyncCursor", mock.Anything, mock.Anything).Return("", nil)
+
+		cmd := NewRootCmd(&Config{Out: &bytes.Buffer{}, Sync: store})
+		cmd.SetArgs([]string{"sync", "pull", "--remote", url, "--token", "guess"})
+
+		assert.ErrorIs(t, cmd.Execute(), eventsync.ErrUnauthorized)
+	})
+
+	t.Run("token from the environment isn't shown in help", func(t *testing.T) {
+		t.Setenv("TERMDICT_SYNC_TOKEN", "hunter2")
+		for _, sub := range []string{"serve", "push", "pull"} {
+			var b bytes.Buffer
+			cmd := NewRootCmd(&Config{Out: &b, Sync: &mockSyncStore{}})
+			cmd.SetOut(&b)
+			cmd.SetArgs([]string{"sync", sub, "--help"})
+
+			require.NoError(t, cmd.Execute())
+			assert.Contains(t, b.String(), "--token")
+			assert.NotContains(t, b.String(), "hunter2")
+		}
+	})
+
+	t.Run("no token", func(t *testing.T) {
+		t.Setenv("TERMDICT_SYNC_TOKEN", "")
+		cmd := NewRootCmd(&Config{Out: &bytes.Buffer{}, Sync: &mockSyncStore{}})
+		cmd.SetArgs([]string{"sync", "push", "--remote", "http://localhost:8080"})
+
+		assert.ErrorContains(t, cmd.Execute(), "--token")
+	})
+
+	t.Run("serve without token", func(t *testing.T) {
+		t.Setenv("TERMDICT_SYNC_TOKEN", "")
+		cmd := NewRootCmd(&Config{Out: &bytes.Buffer{}, Sync: &mockSyncStore{}})
+		cmd.SetArgs([]string{"sync", "serve", "--addr", "127.0.0.1:0"})
+
+		assert.ErrorContains(t, cmd.Execute(), "--token")
+	})
+
+	t.Run("no dir", func(t *testing.T) {
+		cmd := NewRootCmd(&Config{Out: &bytes.Buffer{}, Sync: &mockSyncStore{}})
+		cmd.SetArgs([]string{"sync"})
+
+		assert.ErrorContains(t, cmd.Execute(), "--dir")
+	})
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/caproven/termdict/dictionary"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+type thesaurusOptions struct {
+	word     string
+	lang     string
+	output   string
+	printers map[string]relationsPrinter
+}
+
+// NewThesaurusCommand constructs the thesaurus command
+func NewThesaurusCommand(cfg *Config) *cobra.Command {
+	o := &thesaurusOptions{
+		printers: map[string]relationsPrinter{},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "thesaurus word",
+		Short: "List synonyms and antonyms of a word",
+		Long: `List the synonyms and antonyms of a word, grouped by part of speech.
+
+Sample usage:
+  termdict thesaurus prickly
+  termdict thesaurus prickly -o json
+  termdict thesaurus --lang es rápido`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.word = args[0]
+
+			return o.run(cmd.Context(), cfg.Out, cmd.ErrOrStderr(), cfg.Dict)
+		},
+	}
+
+	o.registerPrinter(new(textRelationsPrinter))
+	o.registerPrinter(new(jsonRelationsPrinter))
+
+	cmd.Flags().StringVar(&o.lang, "lang", cfg.language(), "language to look the word up in, such as en or de")
+	cmd.Flags().StringVarP(&o.output, "output", "o", "text", "output format; one of text, json")
+
+	return cmd
+}
+
+func (o *thesaurusOptions) run(ctx context.Context, out, errOut io.Writer, d Definer) error {
+	printer, ok := o.printers[strings.ToLower(o.output)]
+	if !ok {
+		return fmt.Errorf("no printer registered for output %s", o.output)
+	}
+
+	entries, err := d.Define(ctx, o.word, o.lang)
+	if err != nil {
+		return err
+	}
+	warnIfStale(errOut, o.word, entries)
+
+	// Related words aren't specific to a homograph, so group them across all entries
+	var combined dictionary.Entry
+	for _, entry := range entries {
+		combined.Definitions = append(combined.Definitions, entry.Definitions...)
+		combined.Relations = append(combined.Relations, entry.Relations...)
+	}
+
+	return printer.Print(out, o.word, combined.Thesaurus())
+}
+
+func (o *thesaurusOptions) registerPrinter(p relationsPrinter) {
+	o.printers[p.OutputType()] = p
+}
+
+type relationsPrinter interface {
+	OutputType() string
+	Print(w io.Writer, word string, relations []dictionary.Relations) error
+}
+
+type textRelationsPrinter struct{}
+
+func (p *textRelationsPrinter) OutputType() string {
+	return "text"
+}
+
+func (p *textRelationsPrinter) Print(w io.Writer, word string, relations []dictionary.Relations) error {
+	green := color.New(color.FgGreen).SprintFunc()
+	if _, err := fmt.Fprintln(w, green(word)); err != nil {
+		return err
+	}
+
+	if len(relations) == 0 {
+		_, err := fmt.Fprintln(w, "no synonyms or antonyms found")
+		return err
+	}
+
+	return printRelations(w, relations)
+}
+
+// printRelations writes a line per part of speech for each of synonyms and antonyms
+func printRelations(w io.Writer, relations []dictionary.Relations) error {
+	blue := color.New(color.FgCyan).SprintFunc()
+	for _, r := range relations {
+		if len(r.Synonyms) > 0 {
+			if _, err := fmt.Fprintf(w, "[%s] synonyms: %s\n", blue(r.PartOfSpeech), strings.Join(r.Synonyms, ", ")); err != nil {
+				return err
+			}
+		}
+		if len(r.Antonyms) > 0 {
+			if _, err := fmt.Fprintf(w, "[%s] antonyms: %s\n", blue(r.PartOfSpeech), strings.Join(r.Antonyms, ", ")); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+type jsonRelationsPrinter struct{}
+
+func (p *jsonRelationsPrinter) OutputType() string {
+	return "json"
+}
+
+func (p *jsonRelationsPrinter) Print(w io.Writer, word string, relations []dictionary.Relations) error {
+	composite := struct {
+		Word      string
+		Relations []dictionary.Relations
+	}{
+		Word:      word,
+		Relations: relations,
+	}
+	if composite.Relations == nil {
+		composite.Relations = []dictionary.Relations{}
+	}
+
+	return writeJSON(w, composite)
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/caproven/termdict/dictionary"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThesaurusCmd(t *testing.T) {
+	sampleEntries := []dictionary.Entry{{
+		Definitions: []dictionary.Definition{
+			{PartOfSpeech: "adjective", Meaning: "Easily irritated.", Synonyms: []string{"touchy"}},
+		},
+		Relations: []dictionary.Relations{
+			{PartOfSpeech: "adjective", Synonyms: []string{"spiny", "thorny"}},
+		},
+	}}
+
+	t.Run("no word specified", func(t *testing.T) {
+		cmd := NewRootCmd(&Config{
+			Out:   &bytes.Buffer{},
+			Vocab: &mockVocabRepo{},
+			Dict:  &mockDefiner{},
+		})
+		cmd.SetArgs([]string{"thesaurus"})
+
+		require.Error(t, cmd.Execute())
+	})
+
+	t.Run("word not found", func(t *testing.T) {
+		definer := &mockDefiner{}
+		defer definer.AssertExpectations(t)
+		definer.On("Define", mock.Anything, "foo", "en").Return(nil, errors.New("failure")).Once()
+
+		cmd := NewRootCmd(&Config{
+			Out:   &bytes.Buffer{},
+			Vocab: &mockVocabRepo{},
+			Dict:  definer,
+		})
+		cmd.SetArgs([]string{"thesaurus", "foo"})
+
+		require.Error(t, cmd.Execute())
+	})
+
+	t.Run("word found", func(t *testing.T) {
+		definer := &mockDefiner{}
+		defer definer.AssertExpectations(t)
+		definer.On("Define", mock.Anything, "prickly", "en").Return(sampleEntries, nil).Once()
+
+		var b bytes.Buffer
+		cmd := NewRootCmd(&Config{
+			Out:   &b,
+			Vocab: &mockVocabRepo{},
+			Dict:  definer,
+		})
+		cmd.SetArgs([]string{"thesaurus", "prickly"})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "prickly\n[adjective] synonyms: spiny, thorny, touchy\n", b.String())
+	})
+
+	t.Run("invalid output format", func(t *testing.T) {
+		cmd := NewRootCmd(&Config{
+			Out:   &bytes.Buffer{},
+			Vocab: &mockVocabRepo{},
+			Dict:  &mockDefiner{},
+		})
+		cmd.SetArgs([]string{"thesaurus", "--output", "invalid", "foo"})
+
+		require.Error(t, cmd.Execute())
+	})
+}
+
+func TestTextRelationsPrinter(t *testing.T) {
+	cases := []struct {
+		name      string
+		word      string
+		relations []dictionary.Relations
+		expected  string
+	}{
+		{
+			name:     "no relations",
+			word:     "prickly",
+			expected: "prickly\nno synonyms or antonyms found\n",
+		},
+		{
+			name: "multiple parts of speech",
+			word: "snow",
+			relations: []dictionary.Relations{
+				{PartOfSpeech: "noun", Synonyms: []string{"blow", "shash"}},
+				{PartOfSpeech: "verb", Synonyms: []string{"flurry"}, Antonyms: []string{"thaw"}},
+			},
+			expected: `snow
+[noun] synonyms: blow, shash
+[verb] synonyms: flurry
+[verb] antonyms: thaw
+`,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			var b bytes.Buffer
+			printer := new(textRelationsPrinter)
+			require.NoError(t, printer.Print(&b, test.word, test.relations))
+			assert.Equal(t, test.expected, b.String())
+		})
+	}
+}
+
+func TestJsonRelationsPrinter(t *testing.T) {
+	cases := []struct {
+		name      string
+		word      string
+		relations []dictionary.Relations
+		expected  string
+	}{
+		{
+			name: "no relations",
+			word: "prickly",
+			expected: `{
+	"Word": "prickly",
+	"Relations": []
+}
+`,
+		},
+		{
+			name: "synonyms and antonyms",
+			word: "snow",
+			relations: []dictionary.Relations{
+				{PartOfSpeech: "verb", Synonyms: []string{"flurry"}, Antonyms: []string{"thaw"}},
+			},
+			expected: `{
+	"Word": "snow",
+	"Relations": [
+		{
+			"PartOfSpeech": "verb",
+			"Synonyms": [
+				"flurry"
+			],
+			"Antonyms": [
+				"thaw"
+			]
+		}
+	]
+}
+`,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			var b bytes.Buffer
+			printer := new(jsonRelationsPrinter)
+			require.NoError(t, printer.Print(&b, test.word, test.relations))
+			assert.Equal(t, test.expected, b.String())
+		})
+	}
+}
@@ -1,11 +1,139 @@
 package config
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 )
 
-const appName string = "termdict"
+const (
+	appName        string = "termdict"
+	configFilename string = "config.json"
+)
+
+// Config holds user preferences read from the config file
+type Config struct {
+	// Language is the language words are looked up in when none is given, such as "en" or "de"
+	Language string `json:"language,omitempty"`
+	// Provider is the dictionary provider words are defined with when none is given. Several providers can be given
+	// separated by commas, and are tried in order.
+	Provider string `json:"provider,omitempty"`
+	// Merge combines the definitions of all providers rather than using the first able to define a word
+	Merge bool `json:"merge,omitempty"`
+	// Providers holds the settings of each provider, keyed by provider name
+	Providers map[string]map[string]string `json:"providers,omitempty"`
+	// CacheTTL is how long cached words are used before they're defined again. DefaultCacheTTL is used if unset,
+	// and zero means cached words never expire.
+	CacheTTL *Duration `json:"cache_ttl,omitempty"`
+	// NotFoundTTL is how long words without definitions are remembered, so they aren't looked up again.
+	// DefaultNotFoundTTL is used if unset, and zero means they aren't remembered.
+	NotFoundTTL *Duration `json:"not_found_ttl,omitempty"`
+	// CustomDefinitions is how your own definitions of a word are combined with those of providers: "merge" to show
+	// them first, followed by the provider's, or "override" to show only yours. Merge is used if unset.
+	CustomDefinitions string `json:"custom_definitions,omitempty"`
+	// SyncDir is the shared directory the vocab list is synced through, such as a Syncthing or Dropbox folder
+	SyncDir string `json:"sync_dir,omitempty"`
+	// HTTP configures how dictionary services are reached
+	HTTP HTTP `json:"http,omitzero"`
+}
+
+// HTTP configures the client used to reach dictionary services
+type HTTP struct {
+	// Timeout is how long a single request may take. DefaultTimeout is used if unset, and zero means no limit.
+	Timeout *Duration `json:"timeout,omitempty"`
+	// Retries is how many times a request is retried after the service fails or rate limits it. DefaultRetries is
+	// used if unset.
+	Retries *int `json:"retries,omitempty"`
+	// UserAgent replaces the User-Agent sent with requests
+	UserAgent string `json:"user_agent,omitempty"`
+	// Proxy is the URL of the proxy requests are sent through, rather than that of the environment
+	Proxy string `json:"proxy,omitempty"`
+	// CABundle is the path to a PEM file of certificate authorities to trust, besides those of the system
+	CABundle string `json:"ca_bundle,omitempty"`
+}
+
+const (
+	// DefaultCacheTTL is how long cached words are used for when the config doesn't say
+	DefaultCacheTTL = 30 * 24 * time.Hour
+	// DefaultNotFoundTTL is how long words without definitions are remembered when the config doesn't say
+	DefaultNotFoundTTL = 24 * time.Hour
+	// DefaultTimeout is how long a request to a dictionary service may take when the config doesn't say
+	DefaultTimeout = 10 * time.Second
+	// DefaultRetries is how many times a failed request is retried when the config doesn't say
+	DefaultRetries = 3
+)
+
+// Duration is a length of time written as a string, such as "12h" or "30d"
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string: %w", err)
+	}
+	// time.ParseDuration has no unit for days
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid duration %q", s)
+		}
+		d.Duration = time.Duration(n) * 24 * time.Hour
+		return nil
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	if v < 0 {
+		return fmt.Errorf("invalid duration %q", s)
+	}
+	d.Duration = v
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// CacheTTLOrDefault returns CacheTTL, or DefaultCacheTTL if it's unset
+func (c Config) CacheTTLOrDefault() time.Duration {
+	if c.CacheTTL == nil {
+		return DefaultCacheTTL
+	}
+	return c.CacheTTL.Duration
+}
+
+// NotFoundTTLOrDefault returns NotFoundTTL, or DefaultNotFoundTTL if it's unset
+func (c Config) NotFoundTTLOrDefault() time.Duration {
+	if c.NotFoundTTL == nil {
+		return DefaultNotFoundTTL
+	}
+	return c.NotFoundTTL.Duration
+}
+
+// TimeoutOrDefault returns Timeout, or DefaultTimeout if it's unset
+func (h HTTP) TimeoutOrDefault() time.Duration {
+	if h.Timeout == nil {
+		return DefaultTimeout
+	}
+	return h.Timeout.Duration
+}
+
+// RetriesOrDefault returns Retries, or DefaultRetries if it's unset
+func (h HTTP) RetriesOrDefault() int {
+	if h.Retries == nil {
+		return DefaultRetries
+	}
+	return *h.Retries
+}
 
 func DefaultConfigDir() string {
 	configDir, err := os.UserConfigDir()
@@ -14,3 +142,24 @@ func DefaultConfigDir() string {
 	}
 	return filepath.Join(configDir, appName)
 }
+
+// DefaultConfigPath returns the location of the config file within DefaultConfigDir.
+func DefaultConfigPath() string {
+	return filepath.Join(DefaultConfigDir(), configFilename)
+}
+
+// Load reads the config file at path. A missing file isn't an error and results in an empty config.
+func Load(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("read config file: %w", err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	tests := map[string]struct {
+		contents *string
+		want     Config
+		wantErr  bool
+	}{
+		"missing file": {
+			contents: nil,
+			want:     Config{},
+		},
+		"language set": {
+			contents: new(`{"language": "de"}`),
+			want:     Config{Language: "de"},
+		},
+		"provider settings": {
+			contents: new(`{"provider": "wiktionary", "providers": {"wiktionary": {"url": "https://de.wiktionary.org"}}}`),
+			want: Config{
+				Provider:  "wiktionary",
+				Providers: map[string]map[string]string{"wiktionary": {"url": "https://de.wiktionary.org"}},
+			},
+		},
+		"provider chain": {
+			contents: new(`{"provider": "offline,wiktionary", "merge": true}`),
+			want:     Config{Provider: "offline,wiktionary", Merge: true},
+		},
+		"cache ttl in hours": {
+			contents: new(`{"cache_ttl": "12h"}`),
+			want:     Config{CacheTTL: &Duration{12 * time.Hour}},
+		},
+		"cache ttl in days": {
+			contents: new(`{"cache_ttl": "90d"}`),
+			want:     Config{CacheTTL: &Duration{90 * 24 * time.Hour}},
+		},
+		"cache ttl disabled": {
+			contents: new(`{"cache_ttl": "0"}`),
+			want:     Config{CacheTTL: &Duration{}},
+		},
+		"not found ttl": {
+			contents: new(`{"not_found_ttl": "6h"}`),
+			want:     Config{NotFoundTTL: &Duration{6 * time.Hour}},
+		},
+		"http settings": {
+			contents: new(`{"http": {"timeout": "5s", "retries": 0, "user_agent": "acme", "proxy": "http://proxy:3128", "ca_bundle": "/etc/ca.pem"}}`),
+			want: Config{HTTP: HTTP{
+				Timeout:   &Duration{5 * time.Second},
+				Retries:   new(0),
+				UserAgent: "acme",
+				Proxy:     "http://proxy:3128",
+				CABundle:  "/etc/ca.pem",
+			}},
+		},
+		"sync dir": {
+			contents: new(`{"sync_dir": "/home/me/Sync/termdict"}`),
+			want:     Config{SyncDir: "/home/me/Sync/termdict"},
+		},
+		"invalid cache ttl": {
+			contents: new(`{"cache_ttl": "soon"}`),
+			wantErr:  true,
+		},
+		"negative cache ttl": {
+			contents: new(`{"cache_ttl": "-1h"}`),
+			wantErr:  true,
+		},
+		"empty object": {
+			contents: new(`{}`),
+			want:     Config{},
+		},
+		"malformed": {
+			contents: new(`{"language":`),
+			wantErr:  true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.json")
+			if tt.contents != nil {
+				require.NoError(t, os.WriteFile(path, []byte(*tt.contents), 0o600))
+			}
+
+			got, err := Load(path)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConfig_CacheTTLOrDefault(t *testing.T) {
+	assert.Equal(t, DefaultCacheTTL, Config{}.CacheTTLOrDefault())
+	assert.Equal(t, time.Hour, Config{CacheTTL: &Duration{time.Hour}}.CacheTTLOrDefault())
+	assert.Zero(t, Config{CacheTTL: &Duration{}}.CacheTTLOrDefault())
+}
+
+func TestConfig_NotFoundTTLOrDefault(t *testing.T) {
+	assert.Equal(t, DefaultNotFoundTTL, Config{}.NotFoundTTLOrDefault())
+	assert.Zero(t, Config{NotFoundTTL: &Duration{}}.NotFoundTTLOrDefault())
+}
+
+func TestHTTP_TimeoutOrDefault(t *testing.T) {
+	assert.Equal(t, DefaultTimeout, HTTP{}.TimeoutOrDefault())
+	assert.Zero(t, HTTP{Timeout: &Duration{}}.TimeoutOrDefault())
+}
+
+func TestHTTP_RetriesOrDefault(t *testing.T) {
+	assert.Equal(t, DefaultRetries, HTTP{}.RetriesOrDefault())
+	assert.Zero(t, HTTP{Retries: new(0)}.RetriesOrDefault())
+}
@@ -3,22 +3,38 @@ package dictionary
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 )
 
 // defaultURL is the default API's URL
 const defaultURL string = "https://api.dictionaryapi.dev"
 
 // defaultEndpoint is the API's endpoint for defining words
-const defaultEndpoint string = "/api/v2/entries/en/"
+const defaultEndpoint string = "/api/v2/entries/"
 
 // apiResponse is the dictionary API response
 type apiResponse struct {
-	Meanings []apiMeanings
+	Phonetic   string
+	Phonetics  []apiPhonetic
+	Meanings   []apiMeanings
+	License    apiLicense
+	SourceURLs []string
+}
+
+// apiLicense is the license the response's content is distributed under
+type apiLicense struct {
+	Name string
+	URL  string
+}
+
+// apiPhonetic is a single pronunciation of a word
+type apiPhonetic struct {
+	Text  string
+	Audio string
 }
 
 // apiMeanings is a series of definitions broken up
@@ -26,17 +42,22 @@ type apiResponse struct {
 type apiMeanings struct {
 	PartOfSpeech string
 	Definitions  []apiDefinition
+	Synonyms     []string
+	Antonyms     []string
 }
 
 // apiDefinition is a single definition for a word
 type apiDefinition struct {
 	Definition string
+	Example    string
+	Synonyms   []string
+	Antonyms   []string
 }
 
 // WebAPI lets you interact with a dictionary API
 type WebAPI struct {
 	url string
-	// Word to define should be appended to the end
+	// Language and word to define should be appended to the end
 	endpoint   string
 	httpClient *http.Client
 }
@@ -46,40 +67,104 @@ func NewDefaultWebAPI() WebAPI {
 	return WebAPI{
 		url:        defaultURL,
 		endpoint:   defaultEndpoint,
-		httpClient: http.DefaultClient,
+		httpClient: defaultHTTPClient,
 	}
 }
 
-func (api WebAPI) Define(ctx context.Context, word string) ([]Definition, error) {
-	apiResp, err := api.query(ctx, word)
+func (api WebAPI) Define(ctx context.Context, word, lang string) ([]Entry, error) {
+	if lang == "" {
+		lang = DefaultLanguage
+	}
+	apiResps, err := api.query(ctx, word, lang)
 	if err != nil {
-		return nil, fmt.Errorf("failed to define word '%s'", word)
+		return nil, &LookupError{Word: word, Err: err}
+	}
+
+	entries := make([]Entry, 0, len(apiResps))
+	for _, apiResp := range apiResps {
+		entries = append(entries, apiResp.toEntry())
 	}
 
-	defs := []Definition{}
+	return entries, nil
+}
+
+// toEntry converts a single response from the API into an entry
+func (apiResp apiResponse) toEntry() Entry {
+	entry := Entry{
+		Phonetic:    apiResp.Phonetic,
+		Definitions: []Definition{},
+		SourceURLs:  nonEmpty(apiResp.SourceURLs),
+		License: License{
+			Name: apiResp.License.Name,
+			URL:  apiResp.License.URL,
+		},
+	}
+
+	for _, respPhonetic := range apiResp.Phonetics {
+		// Some pronunciations have neither a transcription nor a recording
+		if respPhonetic.Text == "" && respPhonetic.Audio == "" {
+			continue
+		}
+		entry.Phonetics = append(entry.Phonetics, Phonetic{
+			Text:  respPhonetic.Text,
+			Audio: respPhonetic.Audio,
+		})
+	}
 
 	for _, respMeaning := range apiResp.Meanings {
 		for _, respDef := range respMeaning.Definitions {
 			def := Definition{
 				PartOfSpeech: respMeaning.PartOfSpeech,
 				Meaning:      respDef.Definition,
+				Example:      respDef.Example,
+				Synonyms:     nonEmpty(respDef.Synonyms),
+				Antonyms:     nonEmpty(respDef.Antonyms),
 			}
-			defs = append(defs, def)
+			entry.Definitions = append(entry.Definitions, def)
+		}
+		if len(respMeaning.Synonyms) > 0 || len(respMeaning.Antonyms) > 0 {
+			entry.Relations = append(entry.Relations, Relations{
+				PartOfSpeech: respMeaning.PartOfSpeech,
+				Synonyms:     nonEmpty(respMeaning.Synonyms),
+				Antonyms:     nonEmpty(respMeaning.Antonyms),
+			})
 		}
 	}
 
-	return defs, nil
+	return entry
 }
 
-func (api WebAPI) query(ctx context.Context, w string) (apiResponse, error) {
-	reqURL := fmt.Sprintf("%s%s%s", api.url, api.endpoint, w)
+// nonEmpty normalizes the API's empty arrays to nil
+func nonEmpty(s []string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	return s
+}
+
+// query requests the word's entries from the API. Errors wrap one of the package's sentinel errors.
+func (api WebAPI) query(ctx context.Context, w, lang string) ([]apiResponse, error) {
+	reqURL := fmt.Sprintf("%s%s%s/%s", api.url, api.endpoint, url.PathEscape(lang), url.PathEscape(w))
+	var responses []apiResponse
+	if err := getJSON(ctx, api.httpClient, reqURL, &responses); err != nil {
+		return nil, err
+	}
+	if len(responses) == 0 {
+		return nil, ErrNotFound
+	}
+	return responses, nil
+}
+
+// getJSON requests a URL and decodes the response body into v. A not found status means ErrNotFound, since the body
+// then describes the failure rather than holding a result. Errors wrap one of the package's sentinel errors.
+func getJSON(ctx context.Context, client *http.Client, reqURL string, v any) error {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
 	if err != nil {
-		return apiResponse{}, fmt.Errorf("build request: %w", err)
+		return fmt.Errorf("build request: %w", err)
 	}
-	resp, err := api.httpClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
-		return apiResponse{}, err
+		return fmt.Errorf("%w: %w", ErrTransport, err)
 	}
 	defer func(body io.Closer) {
 		if err := body.Close(); err != nil {
@@ -87,17 +172,25 @@ func (api WebAPI) query(ctx context.Context, w string) (apiResponse, error) {
 		}
 	}(resp.Body)
 
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return fmt.Errorf("%w: response status %d", ErrServer, resp.StatusCode)
+	default:
+		return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return apiResponse{}, err
+		return fmt.Errorf("%w: read response: %w", ErrTransport, err)
 	}
 
-	var responses []apiResponse
-	if err := json.Unmarshal(body, &responses); err != nil {
-		return apiResponse{}, err
-	}
-	if len(responses) == 0 {
-		return apiResponse{}, errors.New("didn't find any definitions")
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("%w: decode response: %w", ErrServer, err)
 	}
-	return responses[0], err
+	return nil
 }
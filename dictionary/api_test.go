@@ -1,6 +1,7 @@
 package dictionary
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -17,9 +18,17 @@ func TestDefine(t *testing.T) {
 		"snow":           `[{"word":"snow","phonetic":"/snəʊ/","phonetics":[{"text":"/snəʊ/","audio":"https://api.dictionaryapi.dev/media/pronunciations/en/snow-1-uk.mp3","sourceUrl":"https://commons.wikimedia.org/w/index.php?curid=9027438","license":{"name":"BY 3.0 US","url":"https://creativecommons.org/licenses/by/3.0/us"}},{"text":"/snoʊ/","audio":"https://api.dictionaryapi.dev/media/pronunciations/en/snow-1-us.mp3","sourceUrl":"https://commons.wikimedia.org/w/index.php?curid=1157887","license":{"name":"BY-SA 3.0","url":"https://creativecommons.org/licenses/by-sa/3.0"}}],"meanings":[{"partOfSpeech":"noun","definitions":[{"definition":"The frozen, crystalline state of water that falls as precipitation.","synonyms":[],"antonyms":[]},{"definition":"A snowfall; a blanket of frozen, crystalline water.","synonyms":[],"antonyms":[],"example":"We have had several heavy snows this year."},{"definition":"A shade of the color white.","synonyms":[],"antonyms":[]}],"synonyms":["blow","shash"],"antonyms":[]},{"partOfSpeech":"verb","definitions":[{"definition":"To have snow fall from the sky.","synonyms":[],"antonyms":[],"example":"It is snowing."}],"synonyms":[],"antonyms":[]}],"license":{"name":"CC BY-SA 3.0","url":"https://creativecommons.org/licenses/by-sa/3.0"},"sourceUrls":["https://en.wiktionary.org/wiki/snow"]},{"word":"snow","phonetics":[],"meanings":[{"partOfSpeech":"noun","definitions":[{"definition":"A square-rigged vessel, differing from a brig only in that she has a trysail mast close abaft the mainmast, on which a large trysail is hoisted.","synonyms":[],"antonyms":[]}],"synonyms":[],"antonyms":[]}],"license":{"name":"CC BY-SA 3.0","url":"https://creativecommons.org/licenses/by-sa/3.0"},"sourceUrls":["https://en.wiktionary.org/wiki/snow"]}]`,
 		"no_definitions": `{"title":"No Definitions Found","message":"Sorry pal, we couldn't find definitions for the word you were looking for.","resolution":"You can try the search again at later time or head to the web instead."}`,
 		"empty_response": `[]`,
+		"rate_limited":   `{"title":"Too Many Requests"}`,
+		"server_error":   `<html>Internal Server Error</html>`,
+		"malformed":      `[{"word":`,
+	}
+	statuses := map[string]int{
+		"no_definitions": http.StatusNotFound,
+		"rate_limited":   http.StatusTooManyRequests,
+		"server_error":   http.StatusBadGateway,
 	}
 
-	apiServer := mockAPIServer(mockWords, defineEndpoint)
+	apiServer := mockAPIServer(mockWords, statuses, defineEndpoint)
 	defer apiServer.Close()
 
 	api := WebAPI{
@@ -31,70 +40,194 @@ func TestDefine(t *testing.T) {
 	cases := []struct {
 		name        string
 		word        string
-		defs        []Definition
+		entries     []Entry
 		errExpected bool
+		// wantErr is the sentinel error expected to be wrapped, if any
+		wantErr error
 	}{
 		{
 			name: "standard response",
 			word: "prickly",
-			defs: []Definition{
-				{PartOfSpeech: "noun", Meaning: "Something that gives a pricking sensation; a sharp object."},
-				{PartOfSpeech: "adjective", Meaning: "Covered with sharp points."},
-				{PartOfSpeech: "adjective", Meaning: "Easily irritated."},
-				{PartOfSpeech: "adverb", Meaning: "In a prickly manner."},
-			},
+			entries: []Entry{{
+				Phonetics: []Phonetic{
+					{Audio: "https://api.dictionaryapi.dev/media/pronunciations/en/prickly.mp3"},
+				},
+				Definitions: []Definition{
+					{PartOfSpeech: "noun", Meaning: "Something that gives a pricking sensation; a sharp object."},
+					{PartOfSpeech: "adjective", Meaning: "Covered with sharp points.", Example: "The prickly pear is a cactus; you have to peel it before eating it to remove the spines and the tough skin."},
+					{PartOfSpeech: "adjective", Meaning: "Easily irritated.", Example: "He has a prickly personality. He doesn't get along with people because he is easily set off."},
+					{PartOfSpeech: "adverb", Meaning: "In a prickly manner."},
+				},
+				Relations: []Relations{
+					{PartOfSpeech: "adjective", Synonyms: []string{"spiny", "thorny"}},
+				},
+				SourceURLs: []string{"https://en.wiktionary.org/wiki/prickly"},
+				License:    License{Name: "CC BY-SA 3.0", URL: "https://creativecommons.org/licenses/by-sa/3.0"},
+			}},
 			errExpected: false,
 		},
 		{
-			name: "multiple definitions in array",
+			name: "multiple entries in array",
 			word: "snow",
-			defs: []Definition{
-				{PartOfSpeech: "noun", Meaning: "The frozen, crystalline state of water that falls as precipitation."},
-				{PartOfSpeech: "noun", Meaning: "A snowfall; a blanket of frozen, crystalline water."},
-				{PartOfSpeech: "noun", Meaning: "A shade of the color white."},
-				{PartOfSpeech: "verb", Meaning: "To have snow fall from the sky."},
-			},
+			entries: []Entry{{
+				Phonetic: "/snəʊ/",
+				Phonetics: []Phonetic{
+					{Text: "/snəʊ/", Audio: "https://api.dictionaryapi.dev/media/pronunciations/en/snow-1-uk.mp3"},
+					{Text: "/snoʊ/", Audio: "https://api.dictionaryapi.dev/media/pronunciations/en/snow-1-us.mp3"},
+				},
+				Definitions: []Definition{
+					{PartOfSpeech: "noun", Meaning: "The frozen, crystalline state of water that falls as precipitation."},
+					{PartOfSpeech: "noun", Meaning: "A snowfall; a blanket of frozen, crystalline water.", Example: "We have had several heavy snows this year."},
+					{PartOfSpeech: "noun", Meaning: "A shade of the color white."},
+					{PartOfSpeech: "verb", Meaning: "To have snow fall from the sky.", Example: "It is snowing."},
+				},
+				Relations: []Relations{
+					{PartOfSpeech: "noun", Synonyms: []string{"blow", "shash"}},
+				},
+				SourceURLs: []string{"https://en.wiktionary.org/wiki/snow"},
+				License:    License{Name: "CC BY-SA 3.0", URL: "https://creativecommons.org/licenses/by-sa/3.0"},
+			}, {
+				Definitions: []Definition{
+					{PartOfSpeech: "noun", Meaning: "A square-rigged vessel, differing from a brig only in that she has a trysail mast close abaft the mainmast, on which a large trysail is hoisted."},
+				},
+				SourceURLs: []string{"https://en.wiktionary.org/wiki/snow"},
+				License:    License{Name: "CC BY-SA 3.0", URL: "https://creativecommons.org/licenses/by-sa/3.0"},
+			}},
 			errExpected: false,
 		},
 		{
 			name:        "word with no definition from api",
 			word:        "no_definitions",
-			defs:        nil,
+			entries:     nil,
 			errExpected: true,
+			wantErr:     ErrNotFound,
 		},
 		{
 			name:        "word with empty response from api",
 			word:        "empty_response",
-			defs:        nil,
+			entries:     nil,
+			errExpected: true,
+			wantErr:     ErrNotFound,
+		},
+		{
+			name:        "rate limited",
+			word:        "rate_limited",
+			entries:     nil,
+			errExpected: true,
+			wantErr:     ErrRateLimited,
+		},
+		{
+			name:        "server error",
+			word:        "server_error",
+			entries:     nil,
+			errExpected: true,
+			wantErr:     ErrServer,
+		},
+		{
+			name:        "malformed response",
+			word:        "malformed",
+			entries:     nil,
 			errExpected: true,
+			wantErr:     ErrServer,
 		},
 	}
 
 	for _, test := range cases {
 		t.Run(test.name, func(t *testing.T) {
-			got, err := api.Define(t.Context(), test.word)
+			got, err := api.Define(t.Context(), test.word, "en")
 
 			if test.errExpected {
 				if err == nil {
 					t.Error("expected err but didn't get one")
 				}
+				if test.wantErr != nil && !errors.Is(err, test.wantErr) {
+					t.Errorf("got err %v, expected it to wrap %v", err, test.wantErr)
+				}
+				var lookupErr *LookupError
+				if !errors.As(err, &lookupErr) || lookupErr.Word != test.word {
+					t.Errorf("got err %v, expected a lookup error for word %q", err, test.word)
+				}
 			} else {
 				if err != nil {
 					t.Errorf("didn't expect err but got: %v", err)
 				}
 			}
 
-			if !reflect.DeepEqual(got, test.defs) {
-				t.Errorf("got entries %v, expected %v", got, test.defs)
+			if !reflect.DeepEqual(got, test.entries) {
+				t.Errorf("got entries %v, expected %v", got, test.entries)
+			}
+		})
+	}
+}
+
+func TestDefine_TransportError(t *testing.T) {
+	apiServer := mockAPIServer(map[string]string{}, nil, defineEndpoint)
+	apiServer.Close()
+
+	api := WebAPI{
+		url:        apiServer.URL,
+		endpoint:   defineEndpoint,
+		httpClient: http.DefaultClient,
+	}
+
+	_, err := api.Define(t.Context(), "snow", "en")
+	if !errors.Is(err, ErrTransport) {
+		t.Errorf("got err %v, expected it to wrap %v", err, ErrTransport)
+	}
+}
+
+func TestDefine_Language(t *testing.T) {
+	var gotPath string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		_, _ = fmt.Fprint(w, `[{"word":"schnee","meanings":[{"partOfSpeech":"noun","definitions":[{"definition":"snow"}]}]}]`)
+	}))
+	defer apiServer.Close()
+
+	api := WebAPI{
+		url:        apiServer.URL,
+		endpoint:   defineEndpoint,
+		httpClient: http.DefaultClient,
+	}
+
+	tests := map[string]struct {
+		lang     string
+		wantPath string
+	}{
+		"explicit language": {
+			lang:     "de",
+			wantPath: defineEndpoint + "de/schnee",
+		},
+		"default language": {
+			lang:     "",
+			wantPath: defineEndpoint + "en/schnee",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := api.Define(t.Context(), "schnee", tt.lang); err != nil {
+				t.Fatalf("didn't expect err but got: %v", err)
+			}
+			if gotPath != tt.wantPath {
+				t.Errorf("got request path %s, expected %s", gotPath, tt.wantPath)
 			}
 		})
 	}
 }
 
-func mockAPIServer(data map[string]string, endpoint string) *httptest.Server {
+// mockAPIServer serves the given response bodies by word for English lookups. Responses have a 200 status unless
+// overridden in statuses.
+func mockAPIServer(data map[string]string, statuses map[string]int, endpoint string) *httptest.Server {
 	mux := http.NewServeMux()
 	mux.HandleFunc(endpoint, func(w http.ResponseWriter, r *http.Request) {
-		word := strings.TrimPrefix(r.URL.Path, endpoint)
+		word, ok := strings.CutPrefix(r.URL.Path, endpoint+"en/")
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if status, ok := statuses[word]; ok {
+			w.WriteHeader(status)
+		}
 		_, _ = fmt.Fprint(w, data[word])
 	})
 	return httptest.NewServer(mux)
@@ -0,0 +1,92 @@
+package dictionary
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultConcurrency is the number of words a Batch defines at once when not told otherwise
+const DefaultConcurrency = 8
+
+// Result is the outcome of defining a single word of a batch
+type Result struct {
+	Word    string
+	Entries []Entry
+	Err     error
+}
+
+// Batch defines many words at once with a Definer. The same word is never defined twice at the same time; callers
+// asking for a word already being defined share the result.
+type Batch struct {
+	definer     Definer
+	concurrency int
+	group       singleflight.Group
+}
+
+// NewBatch creates a Batch defining at most concurrency words at once. DefaultConcurrency is used if concurrency
+// isn't positive.
+func NewBatch(d Definer, concurrency int) *Batch {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	return &Batch{definer: d, concurrency: concurrency}
+}
+
+// Define defines a single word, sharing the result with any concurrent callers defining the same word.
+func (b *Batch) Define(ctx context.Context, word, lang string) ([]Entry, error) {
+	if lang == "" {
+		lang = DefaultLanguage
+	}
+	key := lang + "/" + strings.ToLower(word)
+	v, err, _ := b.group.Do(key, func() (any, error) {
+		return b.definer.Define(ctx, word, lang)
+	})
+	entries, _ := v.([]Entry)
+	return entries, err
+}
+
+// DefineMany defines words in a language using a bounded pool of workers. Results are returned in the order of words,
+// each holding either the word's entries or the error defining it. Once ctx is done, words not yet defined fail with
+// its error.
+func (b *Batch) DefineMany(ctx context.Context, words []string, lang string) []Result {
+	results := make([]Result, len(words))
+	b.DefineEach(ctx, words, lang, func(i int, r Result) {
+		results[i] = r
+	})
+	return results
+}
+
+// DefineEach is like DefineMany, but passes each result to fn as soon as it's ready, along with the index of its word.
+// Calls to fn are never concurrent.
+func (b *Batch) DefineEach(ctx context.Context, words []string, lang string, fn func(i int, r Result)) {
+	indexes := make(chan int)
+	var mu sync.Mutex
+	report := func(i int, r Result) {
+		mu.Lock()
+		defer mu.Unlock()
+		fn(i, r)
+	}
+
+	var wg sync.WaitGroup
+	for range min(b.concurrency, len(words)) {
+		wg.Go(func() {
+			for i := range indexes {
+				entries, err := b.Define(ctx, words[i], lang)
+				report(i, Result{Word: words[i], Entries: entries, Err: err})
+			}
+		})
+	}
+
+	for i, word := range words {
+		if err := ctx.Err(); err != nil {
+			report(i, Result{Word: word, Err: err})
+			continue
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
@@ -0,0 +1,151 @@
+package dictionary_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/caproven/termdict/dictionary"
+	"github.com/caproven/termdict/dictionary/dictionarytest"
+)
+
+// slowDefiner records how many words it's asked to define and how many it defines at once
+type slowDefiner struct {
+	definer dictionary.Definer
+	delay   time.Duration
+
+	mu       sync.Mutex
+	calls    map[string]int
+	inFlight int
+	maxIn    int
+}
+
+func (d *slowDefiner) Define(ctx context.Context, word, lang string) ([]dictionary.Entry, error) {
+	d.mu.Lock()
+	if d.calls == nil {
+		d.calls = make(map[string]int)
+	}
+	d.calls[word]++
+	d.inFlight++
+	d.maxIn = max(d.maxIn, d.inFlight)
+	d.mu.Unlock()
+
+	time.Sleep(d.delay)
+
+	d.mu.Lock()
+	d.inFlight--
+	d.mu.Unlock()
+	return d.definer.Define(ctx, word, lang)
+}
+
+func TestBatch_DefineMany(t *testing.T) {
+	snow := []dictionary.Entry{{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "Frozen rain."}}}}
+	rain := []dictionary.Entry{{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "Water falling from clouds."}}}}
+	definer := &slowDefiner{
+		definer: dictionarytest.InMemoryDefiner{"snow": snow, "rain": rain},
+		delay:   10 * time.Millisecond,
+	}
+
+	got := dictionary.NewBatch(definer, 2).DefineMany(t.Context(), []string{"snow", "snoww", "rain"}, "en")
+
+	if len(got) != 3 {
+		t.Fatalf("DefineMany() returned %d results, want 3", len(got))
+	}
+	if got[0].Word != "snow" || got[0].Err != nil || !reflect.DeepEqual(got[0].Entries, snow) {
+		t.Errorf("DefineMany() result 0 = %+v, want entries of snow", got[0])
+	}
+	if got[1].Word != "snoww" || !errors.Is(got[1].Err, dictionary.ErrNotFound) || got[1].Entries != nil {
+		t.Errorf("DefineMany() result 1 = %+v, want ErrNotFound", got[1])
+	}
+	if got[2].Word != "rain" || got[2].Err != nil || !reflect.DeepEqual(got[2].Entries, rain) {
+		t.Errorf("DefineMany() result 2 = %+v, want entries of rain", got[2])
+	}
+}
+
+func TestBatch_DefineMany_Concurrency(t *testing.T) {
+	words := make([]string, 20)
+	defs := make(dictionarytest.InMemoryDefiner)
+	for i := range words {
+		words[i] = string(rune('a' + i))
+		defs[words[i]] = []dictionary.Entry{{Definitions: []dictionary.Definition{{Meaning: words[i]}}}}
+	}
+	definer := &slowDefiner{definer: defs, delay: 5 * time.Millisecond}
+
+	results := dictionary.NewBatch(definer, 3).DefineMany(t.Context(), words, "en")
+
+	for _, r := range results {
+		if r.Err != nil {
+			t.Errorf("DefineMany() error for %s = %v", r.Word, r.Err)
+		}
+	}
+	if definer.maxIn > 3 {
+		t.Errorf("defined %d words at once, want at most 3", definer.maxIn)
+	}
+	if definer.maxIn < 2 {
+		t.Errorf("defined %d words at once, want words defined concurrently", definer.maxIn)
+	}
+}
+
+func TestBatch_DefineMany_Deduplicates(t *testing.T) {
+	definer := &slowDefiner{
+		definer: dictionarytest.InMemoryDefiner{"snow": {{Definitions: []dictionary.Definition{{Meaning: "Frozen rain."}}}}},
+		delay:   20 * time.Millisecond,
+	}
+
+	results := dictionary.NewBatch(definer, 4).DefineMany(t.Context(), []string{"snow", "Snow", "snow", "snow"}, "en")
+
+	for _, r := range results {
+		if r.Err != nil {
+			t.Errorf("DefineMany() error for %s = %v", r.Word, r.Err)
+		}
+	}
+	if calls := definer.calls["snow"] + definer.calls["Snow"]; calls != 1 {
+		t.Errorf("snow defined %d times, want 1", calls)
+	}
+}
+
+func TestBatch_DefineMany_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	var calls atomic.Int32
+	definer := definerFunc(func(context.Context, string, string) ([]dictionary.Entry, error) {
+		calls.Add(1)
+		return nil, nil
+	})
+	results := dictionary.NewBatch(definer, 2).DefineMany(ctx, []string{"snow", "rain"}, "en")
+
+	for _, r := range results {
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("DefineMany() error for %s = %v, want context.Canceled", r.Word, r.Err)
+		}
+	}
+	if calls.Load() != 0 {
+		t.Errorf("defined %d words after cancellation, want 0", calls.Load())
+	}
+}
+
+// definerFunc adapts a function to a Definer
+type definerFunc func(ctx context.Context, word, lang string) ([]dictionary.Entry, error)
+
+func (f definerFunc) Define(ctx context.Context, word, lang string) ([]dictionary.Entry, error) {
+	return f(ctx, word, lang)
+}
+
+func TestBatch_DefineEach(t *testing.T) {
+	definer := dictionarytest.InMemoryDefiner{"snow": {{Definitions: []dictionary.Definition{{Meaning: "Frozen rain."}}}}}
+
+	seen := make(map[int]string)
+	dictionary.NewBatch(definer, 2).DefineEach(t.Context(), []string{"snow", "snoww"}, "en", func(i int, r dictionary.Result) {
+		// Calls aren't concurrent, so the map needn't be guarded
+		seen[i] = r.Word
+	})
+
+	if want := map[int]string{0: "snow", 1: "snoww"}; !reflect.DeepEqual(seen, want) {
+		t.Errorf("DefineEach() reported %v, want %v", seen, want)
+	}
+}
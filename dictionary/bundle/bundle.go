@@ -0,0 +1,152 @@
+// Package bundle reads and writes cache bundles, which carry cached words from one machine's cache to another's.
+//
+// A bundle is a JSONL file. Its first line is a header naming the format and its version, and each line after holds a
+// cached word with the entries of a single provider.
+package bundle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/caproven/termdict/dictionary"
+)
+
+const (
+	// Format identifies cache bundles
+	Format = "termdict-cache"
+	// Version is the version of the bundle format written. Bundles of later versions can't be read.
+	Version = 1
+)
+
+// Header is the first line of a bundle
+type Header struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+}
+
+// Word is a cached word held in a bundle
+type Word struct {
+	Word     string `json:"word"`
+	Language string `json:"language"`
+	// Provider is the dictionary provider which defined the word
+	Provider string `json:"provider"`
+	// FetchedAt is when the word was defined. The zero time means it isn't known.
+	FetchedAt time.Time          `json:"fetched_at,omitzero"`
+	Entries   []dictionary.Entry `json:"entries"`
+}
+
+// ConflictPolicy decides what happens when a word being imported is already cached by the same provider
+type ConflictPolicy string
+
+const (
+	// KeepNewer keeps whichever of the two was fetched most recently. A word with an unknown fetch time is older
+	// than any other, and the cached word is kept on a tie.
+	KeepNewer ConflictPolicy = "keep-newer"
+	// Overwrite replaces the cached word with the imported one
+	Overwrite ConflictPolicy = "overwrite"
+	// Skip keeps the cached word
+	Skip ConflictPolicy = "skip"
+)
+
+// ParseConflictPolicy returns the conflict policy with the given name
+func ParseConflictPolicy(s string) (ConflictPolicy, error) {
+	switch p := ConflictPolicy(s); p {
+	case KeepNewer, Overwrite, Skip:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown conflict policy %q; one of %s, %s, %s", s, KeepNewer, Overwrite, Skip)
+	}
+}
+
+// Replaces reports whether a word fetched at imported replaces one cached at cached under the policy
+func (p ConflictPolicy) Replaces(imported, cached time.Time) bool {
+	switch p {
+	case Overwrite:
+		return true
+	case KeepNewer:
+		return imported.After(cached)
+	default:
+		return false
+	}
+}
+
+// Writer writes words to a bundle
+type Writer struct {
+	enc *json.Encoder
+}
+
+// NewWriter starts a bundle in w by writing its header
+func NewWriter(w io.Writer) (*Writer, error) {
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(Header{Format: Format, Version: Version, ExportedAt: time.Now().UTC()}); err != nil {
+		return nil, fmt.Errorf("encode header: %w", err)
+	}
+	return &Writer{enc: enc}, nil
+}
+
+// Write adds a word to the bundle
+func (w *Writer) Write(word Word) error {
+	if err := w.enc.Encode(word); err != nil {
+		return fmt.Errorf("encode word %q: %w", word.Word, err)
+	}
+	return nil
+}
+
+// Read streams the words of a bundle to fn, after checking its header. Words without a name, language, provider, or
+// any definitions are rejected.
+func Read(r io.Reader, fn func(Word) error) error {
+	dec := json.NewDecoder(r)
+
+	var header Header
+	if err := dec.Decode(&header); err != nil {
+		if errors.Is(err, io.EOF) {
+			return errors.New("bundle is empty")
+		}
+		return fmt.Errorf("decode header: %w", err)
+	}
+	if header.Format != Format {
+		return fmt.Errorf("not a cache bundle: format is %q", header.Format)
+	}
+	if header.Version < 1 || header.Version > Version {
+		return fmt.Errorf("unsupported bundle version %d; the most recent supported is %d", header.Version, Version)
+	}
+
+	for {
+		var w Word
+		if err := dec.Decode(&w); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("decode word at offset %d: %w", dec.InputOffset(), err)
+		}
+		if err := validate(w); err != nil {
+			return fmt.Errorf("word at offset %d: %w", dec.InputOffset(), err)
+		}
+		if err := fn(w); err != nil {
+			return err
+		}
+	}
+}
+
+func validate(w Word) error {
+	switch {
+	case w.Word == "":
+		return errors.New("word is blank")
+	case w.Language == "":
+		return fmt.Errorf("language of %q is blank", w.Word)
+	case w.Provider == "":
+		return fmt.Errorf("provider of %q is blank", w.Word)
+	case len(w.Entries) == 0:
+		return fmt.Errorf("%q has no entries", w.Word)
+	}
+	for i, entry := range w.Entries {
+		if len(entry.Definitions) == 0 {
+			return fmt.Errorf("entry %d of %q has no definitions", i+1, w.Word)
+		}
+	}
+	return nil
+}
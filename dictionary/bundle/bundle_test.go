@@ -0,0 +1,155 @@
+package bundle
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/caproven/termdict/dictionary"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteRead(t *testing.T) {
+	words := []Word{
+		{
+			Word:      "snow",
+			Language:  "en",
+			Provider:  "freedictionary",
+			FetchedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			Entries: []dictionary.Entry{{
+				Phonetic:    "/snəʊ/",
+				Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "Frozen rain.", Synonyms: []string{"flakes"}}},
+				SourceURLs:  []string{"https://example.com/snow"},
+			}},
+		},
+		{
+			Word:     "gift",
+			Language: "de",
+			Provider: "wiktionary",
+			Entries:  []dictionary.Entry{{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "poison"}}}},
+		},
+	}
+
+	var b bytes.Buffer
+	w, err := NewWriter(&b)
+	require.NoError(t, err)
+	for _, word := range words {
+		require.NoError(t, w.Write(word))
+	}
+
+	line, _, _ := strings.Cut(b.String(), "\n")
+	assert.Contains(t, line, `"format":"termdict-cache","version":1`, "header comes first")
+
+	var got []Word
+	require.NoError(t, Read(&b, func(w Word) error {
+		got = append(got, w)
+		return nil
+	}))
+	assert.Equal(t, words, got)
+}
+
+func TestRead(t *testing.T) {
+	const header = `{"format":"termdict-cache","version":1,"exported_at":"2026-01-02T03:04:05Z"}` + "\n"
+	const snow = `{"word":"snow","language":"en","provider":"freedictionary","entries":[{"Definitions":[{"PartOfSpeech":"noun","Meaning":"Frozen rain."}]}]}` + "\n"
+
+	tests := map[string]struct {
+		input   string
+		want    int
+		wantErr bool
+	}{
+		"words": {
+			input: header + snow + snow,
+			want:  2,
+		},
+		"no words": {
+			input: header,
+		},
+		"empty": {
+			input:   "",
+			wantErr: true,
+		},
+		"other format": {
+			input:   `{"format":"wiktextract","version":1}` + "\n" + snow,
+			wantErr: true,
+		},
+		"later version": {
+			input:   `{"format":"termdict-cache","version":2}` + "\n" + snow,
+			wantErr: true,
+		},
+		"malformed word": {
+			input:   header + "{\n",
+			wantErr: true,
+		},
+		"word without provider": {
+			input:   header + `{"word":"snow","language":"en","entries":[{"Definitions":[{"Meaning":"Frozen rain."}]}]}` + "\n",
+			wantErr: true,
+		},
+		"entry without definitions": {
+			input:   header + `{"word":"snow","language":"en","provider":"freedictionary","entries":[{"Phonetic":"/snəʊ/"}]}` + "\n",
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			n := 0
+			err := Read(strings.NewReader(tt.input), func(Word) error {
+				n++
+				return nil
+			})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, n)
+		})
+	}
+
+	t.Run("callback error", func(t *testing.T) {
+		errStop := errors.New("stop")
+		err := Read(strings.NewReader(header+snow), func(Word) error {
+			return errStop
+		})
+		assert.ErrorIs(t, err, errStop)
+	})
+}
+
+func TestParseConflictPolicy(t *testing.T) {
+	for _, name := range []string{"keep-newer", "overwrite", "skip"} {
+		got, err := ParseConflictPolicy(name)
+		require.NoError(t, err)
+		assert.Equal(t, ConflictPolicy(name), got)
+	}
+
+	_, err := ParseConflictPolicy("newest")
+	assert.Error(t, err)
+}
+
+func TestConflictPolicy_Replaces(t *testing.T) {
+	older := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+
+	tests := map[string]struct {
+		policy           ConflictPolicy
+		imported, cached time.Time
+		want             bool
+	}{
+		"keep newer with newer import":     {policy: KeepNewer, imported: newer, cached: older, want: true},
+		"keep newer with older import":     {policy: KeepNewer, imported: older, cached: newer, want: false},
+		"keep newer with tie":              {policy: KeepNewer, imported: older, cached: older, want: false},
+		"keep newer with unknown cached":   {policy: KeepNewer, imported: older, want: true},
+		"keep newer with unknown imported": {policy: KeepNewer, cached: older, want: false},
+		"overwrite":                        {policy: Overwrite, imported: older, cached: newer, want: true},
+		"skip":                             {policy: Skip, imported: newer, cached: older, want: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.policy.Replaces(tt.imported, tt.cached))
+		})
+	}
+}
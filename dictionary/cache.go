@@ -2,21 +2,82 @@ package dictionary
 
 import (
 	"context"
+	"errors"
+	"slices"
+	"time"
 )
 
+// Definer looks up the entries for a word in a language, given as an ISO 639-1 code. An empty language means the
+// DefaultLanguage.
 type Definer interface {
-	Define(ctx context.Context, word string) ([]Definition, error)
+	Define(ctx context.Context, word, lang string) ([]Entry, error)
 }
 
+// Cache stores entries keyed by word and language
 type Cache interface {
-	LookupWord(ctx context.Context, word string) ([]Definition, error)
-	ContainsWord(ctx context.Context, word string) (bool, error)
-	SaveWord(ctx context.Context, word string, defs []Definition) error
+	LookupWord(ctx context.Context, word, lang string) ([]Entry, error)
+	ContainsWord(ctx context.Context, word, lang string) (bool, error)
+	// SaveWord stores the entries for a word, replacing any stored before
+	SaveWord(ctx context.Context, word, lang string, entries []Entry) error
+	// FetchedAt returns when a stored word was saved. The zero time means it isn't known.
+	FetchedAt(ctx context.Context, word, lang string) (time.Time, error)
+}
+
+// NotFoundCache remembers words a provider has no definitions for, so they aren't looked up again each time. A
+// CachedDefiner uses it when its Cache implements it.
+type NotFoundCache interface {
+	// SaveNotFound records that a word couldn't be defined
+	SaveNotFound(ctx context.Context, word, lang string) error
+	// NotFoundAt returns when a word was last found to have no definitions. The zero time means it wasn't.
+	NotFoundAt(ctx context.Context, word, lang string) (time.Time, error)
+}
+
+// CustomCache holds definitions written by the user. A CachedDefiner adds them to the entries of its fallback when
+// its Cache implements it. They're kept apart from cached entries, so refreshing or purging the cache keeps them.
+type CustomCache interface {
+	// CustomDefinitions returns the user's definitions of a word, or none if they haven't written any
+	CustomDefinitions(ctx context.Context, word, lang string) ([]Definition, error)
+}
+
+// ProviderCustom tags the entries holding the user's own definitions
+const ProviderCustom = "custom"
+
+// CustomMode is how a CachedDefiner combines the user's definitions of a word with those of its fallback
+type CustomMode string
+
+const (
+	// CustomMerge puts the user's definitions before those of the fallback. If the fallback can't define the word,
+	// the user's definitions are used alone.
+	CustomMerge CustomMode = "merge"
+	// CustomOverride uses the user's definitions instead of those of the fallback, which isn't asked
+	CustomOverride CustomMode = "override"
+)
+
+// CachePolicy controls when a CachedDefiner defines a word again rather than using its cached entries
+type CachePolicy struct {
+	// TTL is how long cached entries are used for. Zero means they never expire.
+	TTL time.Duration
+	// NotFoundTTL is how long words without definitions are remembered for. Zero means they aren't.
+	NotFoundTTL time.Duration
+	// Refresh defines words again even if their cached entries haven't expired
+	Refresh bool
+	// NoCache defines words without reading or writing the cache
+	NoCache bool
+	// StaleIfError uses expired cached entries when the service defining a word can't be reached. Such entries are
+	// marked Stale.
+	StaleIfError bool
+	// Offline never defines words, using cached entries even if they've expired. Words which aren't cached fail with
+	// ErrOffline.
+	Offline bool
+	// Custom is how the user's definitions are combined with those of the fallback. They aren't used if it's empty.
+	// To add them once to what several providers define, wrap their Chain in a CachedDefiner with NoCache set.
+	Custom CustomMode
 }
 
 type CachedDefiner struct {
 	cache    Cache
 	fallback Definer
+	policy   CachePolicy
 }
 
 func NewCachedDefiner(c Cache, d Definer) *CachedDefiner {
@@ -26,25 +87,190 @@ func NewCachedDefiner(c Cache, d Definer) *CachedDefiner {
 	}
 }
 
-func (d *CachedDefiner) Define(ctx context.Context, word string) ([]Definition, error) {
-	ok, err := d.cache.ContainsWord(ctx, word)
+// WithPolicy returns a copy of the definer which follows the given cache policy
+func (d *CachedDefiner) WithPolicy(p CachePolicy) *CachedDefiner {
+	return &CachedDefiner{
+		cache:    d.cache,
+		fallback: d.fallback,
+		policy:   p,
+	}
+}
+
+func (d *CachedDefiner) Define(ctx context.Context, word, lang string) ([]Entry, error) {
+	if lang == "" {
+		lang = DefaultLanguage
+	}
+	custom, err := d.custom(ctx, word, lang)
+	if err != nil {
+		return nil, err
+	}
+	if custom == nil {
+		return d.defineCached(ctx, word, lang)
+	}
+	if d.policy.Custom == CustomOverride {
+		return []Entry{*custom}, nil
+	}
+
+	entries, err := d.defineCached(ctx, word, lang)
+	if err != nil {
+		// The user's definitions are enough on their own, as they're often of words no dictionary knows
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return []Entry{*custom}, nil
+	}
+	return append([]Entry{*custom}, entries...), nil
+}
+
+// custom returns an entry holding the user's definitions of a word, or nil if there are none to use
+func (d *CachedDefiner) custom(ctx context.Context, word, lang string) (*Entry, error) {
+	cc, ok := d.cache.(CustomCache)
+	if !ok || d.policy.Custom == "" {
+		return nil, nil
+	}
+	defs, err := cc.CustomDefinitions(ctx, word, lang)
+	if err != nil || len(defs) == 0 {
+		return nil, err
+	}
+	return &Entry{Definitions: defs, Provider: ProviderCustom}, nil
+}
+
+// defineCached defines a word using the cache and fallback, following the definer's policy
+func (d *CachedDefiner) defineCached(ctx context.Context, word, lang string) ([]Entry, error) {
+	if d.policy.NoCache {
+		return d.define(ctx, word, lang)
+	}
+
+	cached, err := d.cache.ContainsWord(ctx, word, lang)
 	if err != nil {
 		return nil, err
 	}
-	if ok {
-		defs, err := d.cache.LookupWord(ctx, word)
+	if cached {
+		fresh, err := d.fresh(ctx, word, lang)
 		if err != nil {
 			return nil, err
 		}
-		return defs, nil
+		if fresh || d.policy.Offline {
+			return d.lookup(ctx, word, lang, !fresh)
+		}
+	}
+	notFound, err := d.knownNotFound(ctx, word, lang)
+	if err != nil {
+		return nil, err
+	}
+	if notFound {
+		return nil, &LookupError{Word: word, Err: ErrNotFound}
 	}
 
-	defs, err := d.fallback.Define(ctx, word)
+	entries, err := d.define(ctx, word, lang)
 	if err != nil {
+		if cached && d.policy.StaleIfError && errors.Is(err, ErrTransport) {
+			return d.lookup(ctx, word, lang, true)
+		}
+		// Only a word being unknown is remembered; other failures may not happen next time
+		if errors.Is(err, ErrNotFound) {
+			if saveErr := d.saveNotFound(ctx, word, lang); saveErr != nil {
+				return nil, errors.Join(err, saveErr)
+			}
+		}
 		return nil, err
 	}
-	if err = d.cache.SaveWord(ctx, word, defs); err != nil {
+	if err = d.cache.SaveWord(ctx, word, lang, entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// define defines a word with the fallback, unless the definer is offline
+func (d *CachedDefiner) define(ctx context.Context, word, lang string) ([]Entry, error) {
+	if d.policy.Offline {
+		return nil, &LookupError{Word: word, Err: ErrOffline}
+	}
+	return d.fallback.Define(ctx, word, lang)
+}
+
+// lookup returns the cached entries of a word, marking them if they've expired
+func (d *CachedDefiner) lookup(ctx context.Context, word, lang string, stale bool) ([]Entry, error) {
+	entries, err := d.cache.LookupWord(ctx, word, lang)
+	if err != nil {
 		return nil, err
 	}
-	return defs, nil
+	if stale {
+		// Don't mark entries the cache may still hold
+		entries = slices.Clone(entries)
+		for i := range entries {
+			entries[i].Stale = true
+		}
+	}
+	return entries, nil
+}
+
+// knownNotFound reports whether the word was recently found to have no definitions
+func (d *CachedDefiner) knownNotFound(ctx context.Context, word, lang string) (bool, error) {
+	nc, ok := d.cache.(NotFoundCache)
+	if !ok || d.policy.NotFoundTTL <= 0 || d.policy.Refresh {
+		return false, nil
+	}
+	at, err := nc.NotFoundAt(ctx, word, lang)
+	if err != nil || at.IsZero() {
+		return false, err
+	}
+	return !Stale(at, d.policy.NotFoundTTL, time.Now()), nil
+}
+
+func (d *CachedDefiner) saveNotFound(ctx context.Context, word, lang string) error {
+	nc, ok := d.cache.(NotFoundCache)
+	if !ok || d.policy.NotFoundTTL <= 0 {
+		return nil
+	}
+	return nc.SaveNotFound(ctx, word, lang)
+}
+
+// fresh reports whether a cached word's entries can be used without defining it again under the definer's policy
+func (d *CachedDefiner) fresh(ctx context.Context, word, lang string) (bool, error) {
+	if d.policy.Refresh {
+		return false, nil
+	}
+	if d.policy.TTL <= 0 {
+		return true, nil
+	}
+	fetchedAt, err := d.cache.FetchedAt(ctx, word, lang)
+	if err != nil {
+		return false, err
+	}
+	return !Stale(fetchedAt, d.policy.TTL, time.Now()), nil
+}
+
+// Stale reports whether entries fetched at fetchedAt have expired by now, given their TTL. Entries fetched at an
+// unknown time are always stale, while a TTL of zero means entries never expire.
+func Stale(fetchedAt time.Time, ttl time.Duration, now time.Time) bool {
+	if ttl <= 0 {
+		return false
+	}
+	return fetchedAt.IsZero() || now.Sub(fetchedAt) >= ttl
+}
+
+// CachedWord describes a word held in a cache
+type CachedWord struct {
+	Word     string
+	Language string
+	// Provider is the dictionary provider which defined the word
+	Provider string
+	// FetchedAt is when the word was defined. The zero time means it isn't known.
+	FetchedAt time.Time
+}
+
+// CacheStats summarizes the contents of a cache
+type CacheStats struct {
+	// Words is the number of cached words
+	Words int
+	// Definitions is the number of definitions across all cached words
+	Definitions int
+	// NotFound is the number of words remembered as having no definitions
+	NotFound int
+	// SizeBytes is the size of the cache's storage
+	SizeBytes int64
+	// Oldest and Newest are the cached words fetched longest ago and most recently. They're the zero value if no
+	// cached word has a known fetch time.
+	Oldest, Newest CachedWord
 }
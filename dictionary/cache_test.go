@@ -2,9 +2,11 @@ package dictionary_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/caproven/termdict/dictionary"
 	"github.com/caproven/termdict/dictionary/dictionarytest"
@@ -17,12 +19,13 @@ func TestCachedDefiner_Define(t *testing.T) {
 	}
 	type args struct {
 		word string
+		lang string
 	}
 	tests := []struct {
 		name    string
 		fields  fields
 		args    args
-		want    []dictionary.Definition
+		want    []dictionary.Entry
 		wantErr bool
 	}{
 		{
@@ -30,43 +33,49 @@ func TestCachedDefiner_Define(t *testing.T) {
 			fields: fields{
 				cache: make(memoryCache),
 				fallback: dictionarytest.InMemoryDefiner{
-					"splash": {
-						{PartOfSpeech: "noun", Meaning: "The sound made by an object hitting a liquid"},
-						{PartOfSpeech: "verb", Meaning: "To hit or agitate liquid"},
-					},
+					"splash": {{
+						Phonetic: "/splæʃ/",
+						Definitions: []dictionary.Definition{
+							{PartOfSpeech: "noun", Meaning: "The sound made by an object hitting a liquid"},
+							{PartOfSpeech: "verb", Meaning: "To hit or agitate liquid"},
+						},
+					}},
 				},
 			},
 			args: args{word: "splash"},
-			want: []dictionary.Definition{
-				{PartOfSpeech: "noun", Meaning: "The sound made by an object hitting a liquid"},
-				{PartOfSpeech: "verb", Meaning: "To hit or agitate liquid"},
-			},
+			want: []dictionary.Entry{{
+				Phonetic: "/splæʃ/",
+				Definitions: []dictionary.Definition{
+					{PartOfSpeech: "noun", Meaning: "The sound made by an object hitting a liquid"},
+					{PartOfSpeech: "verb", Meaning: "To hit or agitate liquid"},
+				},
+			}},
 			wantErr: false,
 		},
 		{
 			name: "word not defined and in cache",
 			fields: fields{
 				cache: memoryCache{
-					"photosynthesis": {{PartOfSpeech: "noun", Meaning: "Any process by which plants and other photoautotrophs convert light energy into chemical energy"}},
+					"en/photosynthesis": {{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "Any process by which plants and other photoautotrophs convert light energy into chemical energy"}}}},
 				},
 				fallback: make(dictionarytest.InMemoryDefiner),
 			},
 			args:    args{word: "photosynthesis"},
-			want:    []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "Any process by which plants and other photoautotrophs convert light energy into chemical energy"}},
+			want:    []dictionary.Entry{{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "Any process by which plants and other photoautotrophs convert light energy into chemical energy"}}}},
 			wantErr: false,
 		},
 		{
 			name: "word defined and in cache",
 			fields: fields{
 				cache: memoryCache{
-					"aardvark": {{Meaning: "cached definition"}},
+					"en/aardvark": {{Definitions: []dictionary.Definition{{Meaning: "cached definition"}}}},
 				},
 				fallback: dictionarytest.InMemoryDefiner{
-					"aardvark": {{Meaning: "fallback definition"}},
+					"aardvark": {{Definitions: []dictionary.Definition{{Meaning: "fallback definition"}}}},
 				},
 			},
 			args: args{word: "aardvark"},
-			want: []dictionary.Definition{{Meaning: "cached definition"}},
+			want: []dictionary.Entry{{Definitions: []dictionary.Definition{{Meaning: "cached definition"}}}},
 		},
 		{
 			name: "word not defined and not in cache",
@@ -78,11 +87,24 @@ func TestCachedDefiner_Define(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "word cached in a different language",
+			fields: fields{
+				cache: memoryCache{
+					"en/gift": {{Definitions: []dictionary.Definition{{Meaning: "a present"}}}},
+				},
+				fallback: dictionarytest.InMemoryDefiner{
+					"gift": {{Definitions: []dictionary.Definition{{Meaning: "poison"}}}},
+				},
+			},
+			args: args{word: "gift", lang: "de"},
+			want: []dictionary.Entry{{Definitions: []dictionary.Definition{{Meaning: "poison"}}}},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			d := dictionary.NewCachedDefiner(tt.fields.cache, tt.fields.fallback)
-			got, err := d.Define(t.Context(), tt.args.word)
+			got, err := d.Define(t.Context(), tt.args.word, tt.args.lang)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CachedDefiner.Define() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -94,11 +116,15 @@ func TestCachedDefiner_Define(t *testing.T) {
 			if !tt.wantErr {
 				// verify word was cached
 
-				found, _ := tt.fields.cache.ContainsWord(t.Context(), tt.args.word)
+				lang := tt.args.lang
+				if lang == "" {
+					lang = dictionary.DefaultLanguage
+				}
+				found, _ := tt.fields.cache.ContainsWord(t.Context(), tt.args.word, lang)
 				if !found {
 					t.Errorf("cache did not contain defined word %s", tt.args.word)
 				}
-				lookup, _ := tt.fields.cache.LookupWord(t.Context(), tt.args.word)
+				lookup, _ := tt.fields.cache.LookupWord(t.Context(), tt.args.word, lang)
 				if !reflect.DeepEqual(lookup, tt.want) {
 					t.Errorf("cached content = %v, want %v", lookup, tt.want)
 				}
@@ -107,22 +133,460 @@ func TestCachedDefiner_Define(t *testing.T) {
 	}
 }
 
-type memoryCache map[string][]dictionary.Definition
+func TestCachedDefiner_Policy(t *testing.T) {
+	cached := []dictionary.Entry{{Definitions: []dictionary.Definition{{Meaning: "cached definition"}}}}
+	fetched := []dictionary.Entry{{Definitions: []dictionary.Definition{{Meaning: "fetched definition"}}}}
+
+	tests := []struct {
+		name     string
+		policy   dictionary.CachePolicy
+		cachedAt time.Time
+		want     []dictionary.Entry
+	}{
+		{
+			name:     "no ttl",
+			cachedAt: time.Now().Add(-2 * 365 * 24 * time.Hour),
+			want:     cached,
+		},
+		{
+			name:     "fresh",
+			policy:   dictionary.CachePolicy{TTL: time.Hour},
+			cachedAt: time.Now().Add(-time.Minute),
+			want:     cached,
+		},
+		{
+			name:     "expired",
+			policy:   dictionary.CachePolicy{TTL: time.Hour},
+			cachedAt: time.Now().Add(-2 * time.Hour),
+			want:     fetched,
+		},
+		{
+			name:   "unknown fetch time",
+			policy: dictionary.CachePolicy{TTL: time.Hour},
+			want:   fetched,
+		},
+		{
+			name:     "refresh",
+			policy:   dictionary.CachePolicy{Refresh: true},
+			cachedAt: time.Now(),
+			want:     fetched,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := datedCache{
+				memoryCache: memoryCache{"en/aardvark": cached},
+				fetchedAt:   map[string]time.Time{"en/aardvark": tt.cachedAt},
+			}
+			fallback := dictionarytest.InMemoryDefiner{"aardvark": fetched}
+			d := dictionary.NewCachedDefiner(cache, fallback).WithPolicy(tt.policy)
+
+			got, err := d.Define(t.Context(), "aardvark", "en")
+			if err != nil {
+				t.Fatalf("CachedDefiner.Define() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CachedDefiner.Define() = %v, want %v", got, tt.want)
+			}
+			if lookup, _ := cache.LookupWord(t.Context(), "aardvark", "en"); !reflect.DeepEqual(lookup, tt.want) {
+				t.Errorf("cached content = %v, want %v", lookup, tt.want)
+			}
+		})
+	}
+}
+
+func TestCachedDefiner_NotFound(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     dictionary.CachePolicy
+		notFoundAt time.Time
+		fallback   dictionary.Definer
+		wantCalls  int
+		wantErr    error
+		wantSaved  bool
+	}{
+		{
+			name:      "not found is remembered",
+			policy:    dictionary.CachePolicy{NotFoundTTL: time.Hour},
+			fallback:  make(dictionarytest.InMemoryDefiner),
+			wantCalls: 1,
+			wantErr:   dictionary.ErrNotFound,
+			wantSaved: true,
+		},
+		{
+			name:       "recently not found",
+			policy:     dictionary.CachePolicy{NotFoundTTL: time.Hour},
+			notFoundAt: time.Now().Add(-time.Minute),
+			fallback:   make(dictionarytest.InMemoryDefiner),
+			wantCalls:  0,
+			wantErr:    dictionary.ErrNotFound,
+			wantSaved:  true,
+		},
+		{
+			name:       "not found long ago",
+			policy:     dictionary.CachePolicy{NotFoundTTL: time.Hour},
+			notFoundAt: time.Now().Add(-2 * time.Hour),
+			fallback:   dictionarytest.InMemoryDefiner{"ain't": {{Definitions: []dictionary.Definition{{Meaning: "am not"}}}}},
+			wantCalls:  1,
+		},
+		{
+			name:      "network errors aren't remembered",
+			policy:    dictionary.CachePolicy{NotFoundTTL: time.Hour},
+			fallback:  failingDefiner{err: dictionary.ErrTransport},
+			wantCalls: 1,
+			wantErr:   dictionary.ErrTransport,
+		},
+		{
+			name:      "not remembered without ttl",
+			fallback:  make(dictionarytest.InMemoryDefiner),
+			wantCalls: 1,
+			wantErr:   dictionary.ErrNotFound,
+		},
+		{
+			name:       "no cache",
+			policy:     dictionary.CachePolicy{NotFoundTTL: time.Hour, NoCache: true},
+			notFoundAt: time.Now(),
+			fallback:   make(dictionarytest.InMemoryDefiner),
+			wantCalls:  1,
+			wantErr:    dictionary.ErrNotFound,
+			wantSaved:  true,
+		},
+		{
+			name:       "refresh",
+			policy:     dictionary.CachePolicy{NotFoundTTL: time.Hour, Refresh: true},
+			notFoundAt: time.Now(),
+			fallback:   make(dictionarytest.InMemoryDefiner),
+			wantCalls:  1,
+			wantErr:    dictionary.ErrNotFound,
+			wantSaved:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := notFoundCache{memoryCache: make(memoryCache), notFoundAt: make(map[string]time.Time)}
+			if !tt.notFoundAt.IsZero() {
+				cache.notFoundAt["en/ain't"] = tt.notFoundAt
+			}
+			fallback := &countingDefiner{definer: tt.fallback}
+			d := dictionary.NewCachedDefiner(cache, fallback).WithPolicy(tt.policy)
+
+			_, err := d.Define(t.Context(), "ain't", "en")
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("CachedDefiner.Define() error = %v, want %v", err, tt.wantErr)
+			}
+			if fallback.calls != tt.wantCalls {
+				t.Errorf("fallback called %d times, want %d", fallback.calls, tt.wantCalls)
+			}
+			if _, saved := cache.notFoundAt["en/ain't"]; saved != tt.wantSaved {
+				t.Errorf("word remembered as not found = %v, want %v", saved, tt.wantSaved)
+			}
+		})
+	}
+}
+
+func TestCachedDefiner_StaleIfError(t *testing.T) {
+	cached := []dictionary.Entry{{Definitions: []dictionary.Definition{{Meaning: "cached definition"}}}}
+	stale := []dictionary.Entry{{Definitions: []dictionary.Definition{{Meaning: "cached definition"}}, Stale: true}}
+
+	tests := []struct {
+		name     string
+		policy   dictionary.CachePolicy
+		cachedAt time.Time
+		err      error
+		want     []dictionary.Entry
+		wantErr  error
+	}{
+		{
+			name:     "unreachable",
+			policy:   dictionary.CachePolicy{TTL: time.Hour, StaleIfError: true},
+			cachedAt: time.Now().Add(-2 * time.Hour),
+			err:      dictionary.ErrTransport,
+			want:     stale,
+		},
+		{
+			name:     "unreachable without stale if error",
+			policy:   dictionary.CachePolicy{TTL: time.Hour},
+			cachedAt: time.Now().Add(-2 * time.Hour),
+			err:      dictionary.ErrTransport,
+			wantErr:  dictionary.ErrTransport,
+		},
+		{
+			name:     "server error",
+			policy:   dictionary.CachePolicy{TTL: time.Hour, StaleIfError: true},
+			cachedAt: time.Now().Add(-2 * time.Hour),
+			err:      dictionary.ErrServer,
+			wantErr:  dictionary.ErrServer,
+		},
+		{
+			name:     "refresh",
+			policy:   dictionary.CachePolicy{Refresh: true, StaleIfError: true},
+			cachedAt: time.Now(),
+			err:      dictionary.ErrTransport,
+			want:     stale,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := datedCache{
+				memoryCache: memoryCache{"en/aardvark": cached},
+				fetchedAt:   map[string]time.Time{"en/aardvark": tt.cachedAt},
+			}
+			d := dictionary.NewCachedDefiner(cache, failingDefiner{err: tt.err}).WithPolicy(tt.policy)
+
+			got, err := d.Define(t.Context(), "aardvark", "en")
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("CachedDefiner.Define() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CachedDefiner.Define() = %v, want %v", got, tt.want)
+			}
+			if lookup, _ := cache.LookupWord(t.Context(), "aardvark", "en"); !reflect.DeepEqual(lookup, cached) {
+				t.Errorf("cached content = %v, want %v", lookup, cached)
+			}
+		})
+	}
+
+	t.Run("not cached", func(t *testing.T) {
+		cache := memoryCache{}
+		d := dictionary.NewCachedDefiner(cache, failingDefiner{err: dictionary.ErrTransport}).WithPolicy(dictionary.CachePolicy{StaleIfError: true})
 
-func (mc memoryCache) ContainsWord(_ context.Context, word string) (bool, error) {
-	_, ok := mc[word]
+		if _, err := d.Define(t.Context(), "aardvark", "en"); !errors.Is(err, dictionary.ErrTransport) {
+			t.Errorf("CachedDefiner.Define() error = %v, want ErrTransport", err)
+		}
+	})
+}
+
+func TestCachedDefiner_Offline(t *testing.T) {
+	cached := []dictionary.Entry{{Definitions: []dictionary.Definition{{Meaning: "cached definition"}}}}
+	stale := []dictionary.Entry{{Definitions: []dictionary.Definition{{Meaning: "cached definition"}}, Stale: true}}
+
+	tests := []struct {
+		name     string
+		cached   bool
+		cachedAt time.Time
+		want     []dictionary.Entry
+		wantErr  error
+	}{
+		{
+			name:     "fresh",
+			cached:   true,
+			cachedAt: time.Now(),
+			want:     cached,
+		},
+		{
+			name:     "expired",
+			cached:   true,
+			cachedAt: time.Now().Add(-2 * time.Hour),
+			want:     stale,
+		},
+		{
+			name:    "not cached",
+			wantErr: dictionary.ErrOffline,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := datedCache{memoryCache: memoryCache{}, fetchedAt: map[string]time.Time{}}
+			if tt.cached {
+				cache.memoryCache["en/aardvark"] = cached
+				cache.fetchedAt["en/aardvark"] = tt.cachedAt
+			}
+			fallback := &countingDefiner{definer: dictionarytest.InMemoryDefiner{"aardvark": cached}}
+			d := dictionary.NewCachedDefiner(cache, fallback).WithPolicy(dictionary.CachePolicy{TTL: time.Hour, Offline: true})
+
+			got, err := d.Define(t.Context(), "aardvark", "en")
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("CachedDefiner.Define() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CachedDefiner.Define() = %v, want %v", got, tt.want)
+			}
+			if fallback.calls != 0 {
+				t.Errorf("fallback called %d times, want 0", fallback.calls)
+			}
+		})
+	}
+}
+
+func TestCachedDefiner_Custom(t *testing.T) {
+	custom := []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "custom definition"}}
+	customEntry := dictionary.Entry{Definitions: custom, Provider: dictionary.ProviderCustom}
+	fetched := []dictionary.Entry{{Definitions: []dictionary.Definition{{Meaning: "fetched definition"}}}}
+
+	tests := []struct {
+		name      string
+		mode      dictionary.CustomMode
+		custom    []dictionary.Definition
+		fallback  dictionary.Definer
+		want      []dictionary.Entry
+		wantErr   error
+		wantCalls int
+	}{
+		{
+			name:      "merge",
+			mode:      dictionary.CustomMerge,
+			custom:    custom,
+			fallback:  dictionarytest.InMemoryDefiner{"k8s": fetched},
+			want:      append([]dictionary.Entry{customEntry}, fetched...),
+			wantCalls: 1,
+		},
+		{
+			name:      "merge with word unknown to the fallback",
+			mode:      dictionary.CustomMerge,
+			custom:    custom,
+			fallback:  dictionarytest.InMemoryDefiner{},
+			want:      []dictionary.Entry{customEntry},
+			wantCalls: 1,
+		},
+		{
+			name:     "override",
+			mode:     dictionary.CustomOverride,
+			custom:   custom,
+			fallback: dictionarytest.InMemoryDefiner{"k8s": fetched},
+			want:     []dictionary.Entry{customEntry},
+		},
+		{
+			name:      "no custom definitions",
+			mode:      dictionary.CustomOverride,
+			fallback:  dictionarytest.InMemoryDefiner{},
+			wantErr:   dictionary.ErrNotFound,
+			wantCalls: 1,
+		},
+		{
+			name:      "custom definitions not used",
+			custom:    custom,
+			fallback:  dictionarytest.InMemoryDefiner{"k8s": fetched},
+			want:      fetched,
+			wantCalls: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := customCache{memoryCache: memoryCache{}, custom: map[string][]dictionary.Definition{"en/k8s": tt.custom}}
+			fallback := &countingDefiner{definer: tt.fallback}
+			d := dictionary.NewCachedDefiner(cache, fallback).WithPolicy(dictionary.CachePolicy{Custom: tt.mode})
+
+			got, err := d.Define(t.Context(), "k8s", "en")
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("CachedDefiner.Define() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CachedDefiner.Define() = %v, want %v", got, tt.want)
+			}
+			if fallback.calls != tt.wantCalls {
+				t.Errorf("fallback called %d times, want %d", fallback.calls, tt.wantCalls)
+			}
+			// The user's definitions are never cached
+			if lookup, ok := cache.memoryCache["en/k8s"]; ok && !reflect.DeepEqual(lookup, fetched) {
+				t.Errorf("cached content = %v, want %v", lookup, fetched)
+			}
+		})
+	}
+}
+
+func TestStale(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		fetchedAt time.Time
+		ttl       time.Duration
+		want      bool
+	}{
+		{name: "no ttl", fetchedAt: now.Add(-1000 * time.Hour), want: false},
+		{name: "within ttl", fetchedAt: now.Add(-time.Hour), ttl: 2 * time.Hour, want: false},
+		{name: "ttl elapsed", fetchedAt: now.Add(-2 * time.Hour), ttl: 2 * time.Hour, want: true},
+		{name: "unknown fetch time", ttl: 2 * time.Hour, want: true},
+		{name: "unknown fetch time without ttl", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dictionary.Stale(tt.fetchedAt, tt.ttl, now); got != tt.want {
+				t.Errorf("Stale() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// countingDefiner counts the words it's asked to define
+type countingDefiner struct {
+	definer dictionary.Definer
+	calls   int
+}
+
+func (d *countingDefiner) Define(ctx context.Context, word, lang string) ([]dictionary.Entry, error) {
+	d.calls++
+	return d.definer.Define(ctx, word, lang)
+}
+
+// memoryCache holds entries keyed by "lang/word"
+type memoryCache map[string][]dictionary.Entry
+
+func (mc memoryCache) ContainsWord(_ context.Context, word, lang string) (bool, error) {
+	_, ok := mc[lang+"/"+word]
 	return ok, nil
 }
 
-func (mc memoryCache) SaveWord(_ context.Context, word string, defs []dictionary.Definition) error {
-	mc[word] = defs
+func (mc memoryCache) SaveWord(_ context.Context, word, lang string, entries []dictionary.Entry) error {
+	mc[lang+"/"+word] = entries
 	return nil
 }
 
-func (mc memoryCache) LookupWord(_ context.Context, word string) ([]dictionary.Definition, error) {
-	defs, ok := mc[word]
+func (mc memoryCache) LookupWord(_ context.Context, word, lang string) ([]dictionary.Entry, error) {
+	entries, ok := mc[lang+"/"+word]
 	if !ok {
 		return nil, fmt.Errorf("word %s not found in cache", word)
 	}
-	return defs, nil
+	return entries, nil
+}
+
+func (mc memoryCache) FetchedAt(_ context.Context, word, lang string) (time.Time, error) {
+	if _, ok := mc[lang+"/"+word]; !ok {
+		return time.Time{}, fmt.Errorf("word %s not found in cache", word)
+	}
+	return time.Time{}, nil
+}
+
+// datedCache is a memoryCache which records when words were saved
+type datedCache struct {
+	memoryCache
+	fetchedAt map[string]time.Time
+}
+
+func (dc datedCache) SaveWord(ctx context.Context, word, lang string, entries []dictionary.Entry) error {
+	dc.fetchedAt[lang+"/"+word] = time.Now()
+	return dc.memoryCache.SaveWord(ctx, word, lang, entries)
+}
+
+func (dc datedCache) FetchedAt(_ context.Context, word, lang string) (time.Time, error) {
+	return dc.fetchedAt[lang+"/"+word], nil
+}
+
+// notFoundCache is a memoryCache which remembers words that weren't found
+type notFoundCache struct {
+	memoryCache
+	notFoundAt map[string]time.Time
+}
+
+func (nc notFoundCache) SaveWord(ctx context.Context, word, lang string, entries []dictionary.Entry) error {
+	delete(nc.notFoundAt, lang+"/"+word)
+	return nc.memoryCache.SaveWord(ctx, word, lang, entries)
+}
+
+func (nc notFoundCache) SaveNotFound(_ context.Context, word, lang string) error {
+	nc.notFoundAt[lang+"/"+word] = time.Now()
+	return nil
+}
+
+func (nc notFoundCache) NotFoundAt(_ context.Context, word, lang string) (time.Time, error) {
+	return nc.notFoundAt[lang+"/"+word], nil
+}
+
+// customCache is a memoryCache holding the user's definitions
+type customCache struct {
+	memoryCache
+	custom map[string][]dictionary.Definition
+}
+
+func (cc customCache) CustomDefinitions(_ context.Context, word, lang string) ([]dictionary.Definition, error) {
+	return cc.custom[lang+"/"+word], nil
 }
@@ -0,0 +1,111 @@
+package dictionary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ChainMode is how a Chain combines the results of its providers
+type ChainMode string
+
+const (
+	// ChainFirstSuccess uses the entries of the first provider able to define a word
+	ChainFirstSuccess ChainMode = "first"
+	// ChainMerge combines the entries of every provider able to define a word, dropping repeated senses
+	ChainMerge ChainMode = "merge"
+)
+
+// Link is a named provider in a Chain
+type Link struct {
+	Name    string
+	Definer Definer
+}
+
+// Chain defines words using an ordered list of providers. Entries are tagged with the provider which defined them.
+type Chain struct {
+	mode  ChainMode
+	links []Link
+}
+
+func NewChain(mode ChainMode, links ...Link) (*Chain, error) {
+	if mode != ChainFirstSuccess && mode != ChainMerge {
+		return nil, fmt.Errorf("unknown chain mode %q", mode)
+	}
+	if len(links) == 0 {
+		return nil, errors.New("chain has no providers")
+	}
+	return &Chain{mode: mode, links: links}, nil
+}
+
+func (c *Chain) Define(ctx context.Context, word, lang string) ([]Entry, error) {
+	var entries []Entry
+	var errs []error
+	for _, link := range c.links {
+		linkEntries, err := link.Definer.Define(ctx, word, lang)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, entry := range linkEntries {
+			if entry.Provider == "" {
+				entry.Provider = link.Name
+			}
+			entries = append(entries, entry)
+		}
+		if c.mode == ChainFirstSuccess {
+			return entries, nil
+		}
+	}
+
+	if len(entries) == 0 {
+		return nil, chainError(errs)
+	}
+	return dedupeSenses(entries), nil
+}
+
+// chainError picks the error to report when no provider could define a word. A provider failing is more telling than
+// others not knowing the word, so the first error other than ErrNotFound is preferred.
+func chainError(errs []error) error {
+	for _, err := range errs {
+		if !errors.Is(err, ErrNotFound) {
+			return err
+		}
+	}
+	return errs[0]
+}
+
+// dedupeSenses removes definitions repeating the part of speech and meaning of an earlier one, along with entries
+// left without definitions.
+func dedupeSenses(entries []Entry) []Entry {
+	type sense struct {
+		pos, meaning string
+	}
+	seen := make(map[sense]bool)
+
+	deduped := entries[:0]
+	for _, entry := range entries {
+		var defs []Definition
+		for _, def := range entry.Definitions {
+			key := sense{pos: normalizeSense(def.PartOfSpeech), meaning: normalizeSense(def.Meaning)}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			defs = append(defs, def)
+		}
+		if len(defs) == 0 {
+			continue
+		}
+		entry.Definitions = defs
+		deduped = append(deduped, entry)
+	}
+	return deduped
+}
+
+// normalizeSense ignores differences in case, spacing, and trailing punctuation when comparing senses
+func normalizeSense(s string) string {
+	s = strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	return strings.TrimRight(s, ".;")
+}
@@ -0,0 +1,212 @@
+package dictionary_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/caproven/termdict/dictionary"
+	"github.com/caproven/termdict/dictionary/dictionarytest"
+)
+
+// failingDefiner fails to define every word
+type failingDefiner struct {
+	err error
+}
+
+func (d failingDefiner) Define(_ context.Context, word, _ string) ([]dictionary.Entry, error) {
+	return nil, &dictionary.LookupError{Word: word, Err: d.err}
+}
+
+func TestChain_Define(t *testing.T) {
+	offline := dictionarytest.InMemoryDefiner{
+		"snow": {{Definitions: []dictionary.Definition{
+			{PartOfSpeech: "noun", Meaning: "Frozen rain."},
+		}}},
+	}
+	web := dictionarytest.InMemoryDefiner{
+		"snow": {
+			{Definitions: []dictionary.Definition{
+				{PartOfSpeech: "noun", Meaning: "frozen  rain"},
+				{PartOfSpeech: "verb", Meaning: "To have snow fall from the sky."},
+			}},
+			{Definitions: []dictionary.Definition{
+				{PartOfSpeech: "Noun", Meaning: "Frozen rain"},
+			}},
+		},
+		"gift": {{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "A present."}}}},
+	}
+	down := failingDefiner{err: dictionary.ErrTransport}
+
+	tests := map[string]struct {
+		mode    dictionary.ChainMode
+		links   []dictionary.Link
+		word    string
+		want    []dictionary.Entry
+		wantErr error
+	}{
+		"first success uses first provider": {
+			mode:  dictionary.ChainFirstSuccess,
+			links: []dictionary.Link{{Name: "offline", Definer: offline}, {Name: "web", Definer: web}},
+			word:  "snow",
+			want: []dictionary.Entry{{
+				Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "Frozen rain."}},
+				Provider:    "offline",
+			}},
+		},
+		"first success falls back": {
+			mode:  dictionary.ChainFirstSuccess,
+			links: []dictionary.Link{{Name: "down", Definer: down}, {Name: "offline", Definer: offline}, {Name: "web", Definer: web}},
+			word:  "gift",
+			want: []dictionary.Entry{{
+				Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "A present."}},
+				Provider:    "web",
+			}},
+		},
+		"merge drops repeated senses": {
+			mode:  dictionary.ChainMerge,
+			links: []dictionary.Link{{Name: "offline", Definer: offline}, {Name: "web", Definer: web}},
+			word:  "snow",
+			want: []dictionary.Entry{
+				{
+					Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "Frozen rain."}},
+					Provider:    "offline",
+				},
+				{
+					Definitions: []dictionary.Definition{{PartOfSpeech: "verb", Meaning: "To have snow fall from the sky."}},
+					Provider:    "web",
+				},
+			},
+		},
+		"merge ignores failing providers": {
+			mode:  dictionary.ChainMerge,
+			links: []dictionary.Link{{Name: "down", Definer: down}, {Name: "web", Definer: web}},
+			word:  "gift",
+			want: []dictionary.Entry{{
+				Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "A present."}},
+				Provider:    "web",
+			}},
+		},
+		"not found by any provider": {
+			mode:    dictionary.ChainFirstSuccess,
+			links:   []dictionary.Link{{Name: "offline", Definer: offline}, {Name: "web", Definer: web}},
+			word:    "platypus",
+			wantErr: dictionary.ErrNotFound,
+		},
+		"failure preferred over not found": {
+			mode:    dictionary.ChainMerge,
+			links:   []dictionary.Link{{Name: "offline", Definer: offline}, {Name: "down", Definer: down}},
+			word:    "platypus",
+			wantErr: dictionary.ErrTransport,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			chain, err := dictionary.NewChain(tt.mode, tt.links...)
+			if err != nil {
+				t.Fatalf("didn't expect err but got: %v", err)
+			}
+
+			got, err := chain.Define(t.Context(), tt.word, "en")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("got err %v, expected it to wrap %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("didn't expect err but got: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got entries %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewChain(t *testing.T) {
+	link := dictionary.Link{Name: "web", Definer: dictionarytest.InMemoryDefiner{}}
+
+	if _, err := dictionary.NewChain("fastest", link); err == nil {
+		t.Error("expected err for unknown mode but didn't get one")
+	}
+	if _, err := dictionary.NewChain(dictionary.ChainMerge); err == nil {
+		t.Error("expected err for no providers but didn't get one")
+	}
+}
+
+func TestChain_Custom(t *testing.T) {
+	custom := []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "Kubernetes, a container orchestrator."}}
+	customEntry := dictionary.Entry{Definitions: custom, Provider: dictionary.ProviderCustom}
+	offline := dictionarytest.InMemoryDefiner{
+		"k8s": {{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "Kubernetes."}}}},
+	}
+	web := dictionarytest.InMemoryDefiner{
+		"k8s":  {{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "A numeronym of Kubernetes."}}}},
+		"yaml": {{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "A data serialization language."}}}},
+	}
+	cache := customCache{memoryCache: memoryCache{}, custom: map[string][]dictionary.Definition{
+		"en/k8s":  custom,
+		"en/yaml": custom,
+		"en/helm": custom,
+	}}
+
+	tests := map[string]struct {
+		mode       dictionary.ChainMode
+		customMode dictionary.CustomMode
+		word       string
+		want       []dictionary.Entry
+	}{
+		"first success isn't satisfied by the user's definitions": {
+			mode:       dictionary.ChainFirstSuccess,
+			customMode: dictionary.CustomMerge,
+			word:       "yaml",
+			want: []dictionary.Entry{customEntry, {
+				Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "A data serialization language."}},
+				Provider:    "web",
+			}},
+		},
+		"merge adds the user's definitions once": {
+			mode:       dictionary.ChainMerge,
+			customMode: dictionary.CustomMerge,
+			word:       "k8s",
+			want: []dictionary.Entry{
+				customEntry,
+				{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "Kubernetes."}}, Provider: "offline"},
+				{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "A numeronym of Kubernetes."}}, Provider: "web"},
+			},
+		},
+		"user's definitions alone when no provider knows the word": {
+			mode:       dictionary.ChainFirstSuccess,
+			customMode: dictionary.CustomMerge,
+			word:       "helm",
+			want:       []dictionary.Entry{customEntry},
+		},
+		"override uses the user's definitions once": {
+			mode:       dictionary.ChainMerge,
+			customMode: dictionary.CustomOverride,
+			word:       "k8s",
+			want:       []dictionary.Entry{customEntry},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			chain, err := dictionary.NewChain(tt.mode, dictionary.Link{Name: "offline", Definer: offline}, dictionary.Link{Name: "web", Definer: web})
+			if err != nil {
+				t.Fatalf("didn't expect err but got: %v", err)
+			}
+			d := dictionary.NewCachedDefiner(cache, chain).WithPolicy(dictionary.CachePolicy{NoCache: true, Custom: tt.customMode})
+
+			got, err := d.Define(t.Context(), tt.word, "en")
+			if err != nil {
+				t.Fatalf("didn't expect err but got: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got entries %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,111 @@
 package dictionary
 
+// DefaultLanguage is the language words are defined in when none is given
+const DefaultLanguage = "en"
+
+// Entry is a word's dictionary entry, made up of its pronunciations and definitions
+type Entry struct {
+	// Phonetic is the primary phonetic transcription of the word, if known
+	Phonetic    string     `json:",omitempty"`
+	Phonetics   []Phonetic `json:",omitempty"`
+	Definitions []Definition
+	// Relations are words related to the entry as a whole, by part of speech
+	Relations []Relations `json:",omitempty"`
+	// SourceURLs are the pages the entry was sourced from
+	SourceURLs []string `json:",omitempty"`
+	License    License  `json:",omitzero"`
+	// Provider is the name of the provider which defined the entry, if known
+	Provider string `json:",omitempty"`
+	// Stale means the entry was cached and has expired, so it may be out of date
+	Stale bool `json:",omitempty"`
+}
+
+// License is the license an entry's content is distributed under
+type License struct {
+	Name string
+	URL  string
+}
+
+// Phonetic is a single pronunciation of a word
+type Phonetic struct {
+	// Text is the IPA transcription
+	Text string `json:",omitempty"`
+	// Audio is a URL to a recording of the pronunciation
+	Audio string `json:",omitempty"`
+}
+
 // Definition is a single dictionary entry for a word
 type Definition struct {
 	PartOfSpeech string
 	Meaning      string
+	// Example is a sentence using the word in this sense, if one is known
+	Example  string   `json:",omitempty"`
+	Synonyms []string `json:",omitempty"`
+	Antonyms []string `json:",omitempty"`
+}
+
+// Relations are the synonyms and antonyms of a word for a single part of speech
+type Relations struct {
+	PartOfSpeech string
+	Synonyms     []string `json:",omitempty"`
+	Antonyms     []string `json:",omitempty"`
+}
+
+// Pronunciations returns the distinct phonetic transcriptions of the entry, primary first.
+func (e Entry) Pronunciations() []string {
+	var texts []string
+	seen := make(map[string]bool)
+	add := func(text string) {
+		if text == "" || seen[text] {
+			return
+		}
+		seen[text] = true
+		texts = append(texts, text)
+	}
+
+	add(e.Phonetic)
+	for _, p := range e.Phonetics {
+		add(p.Text)
+	}
+	return texts
+}
+
+// Thesaurus returns every synonym and antonym of the entry grouped by part of speech, combining the entry's relations
+// with those of its individual definitions. Groups are ordered by first appearance and contain no duplicates.
+func (e Entry) Thesaurus() []Relations {
+	var groups []Relations
+	index := make(map[string]int)
+	seen := make(map[string]map[string]bool)
+	add := func(pos string, synonyms, antonyms []string) {
+		i, ok := index[pos]
+		if !ok {
+			i = len(groups)
+			index[pos] = i
+			groups = append(groups, Relations{PartOfSpeech: pos})
+			seen[pos] = make(map[string]bool)
+		}
+		for _, w := range synonyms {
+			if key := "s:" + w; !seen[pos][key] {
+				seen[pos][key] = true
+				groups[i].Synonyms = append(groups[i].Synonyms, w)
+			}
+		}
+		for _, w := range antonyms {
+			if key := "a:" + w; !seen[pos][key] {
+				seen[pos][key] = true
+				groups[i].Antonyms = append(groups[i].Antonyms, w)
+			}
+		}
+	}
+
+	for _, r := range e.Relations {
+		add(r.PartOfSpeech, r.Synonyms, r.Antonyms)
+	}
+	for _, def := range e.Definitions {
+		if len(def.Synonyms) > 0 || len(def.Antonyms) > 0 {
+			add(def.PartOfSpeech, def.Synonyms, def.Antonyms)
+		}
+	}
+
+	return groups
 }
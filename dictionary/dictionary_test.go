@@ -0,0 +1,88 @@
+package dictionary
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEntry_Pronunciations(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry Entry
+		want  []string
+	}{
+		{
+			name:  "no phonetics",
+			entry: Entry{},
+			want:  nil,
+		},
+		{
+			name: "primary phonetic first without duplicates",
+			entry: Entry{
+				Phonetic: "/snəʊ/",
+				Phonetics: []Phonetic{
+					{Text: "/snoʊ/"},
+					{Text: "/snəʊ/"},
+				},
+			},
+			want: []string{"/snəʊ/", "/snoʊ/"},
+		},
+		{
+			name: "audio without transcription skipped",
+			entry: Entry{
+				Phonetics: []Phonetic{
+					{Audio: "https://example.com/prickly.mp3"},
+					{Text: "/ˈpɹɪkli/"},
+				},
+			},
+			want: []string{"/ˈpɹɪkli/"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.entry.Pronunciations(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Pronunciations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntry_Thesaurus(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry Entry
+		want  []Relations
+	}{
+		{
+			name: "no related words",
+			entry: Entry{
+				Definitions: []Definition{{PartOfSpeech: "noun", Meaning: "def 1"}},
+			},
+			want: nil,
+		},
+		{
+			name: "entry and definition relations merged by part of speech",
+			entry: Entry{
+				Definitions: []Definition{
+					{PartOfSpeech: "noun", Meaning: "def 1", Synonyms: []string{"thorn", "spine"}},
+					{PartOfSpeech: "adjective", Meaning: "def 2", Antonyms: []string{"smooth"}},
+					{PartOfSpeech: "adjective", Meaning: "def 3", Synonyms: []string{"touchy"}, Antonyms: []string{"smooth"}},
+				},
+				Relations: []Relations{
+					{PartOfSpeech: "adjective", Synonyms: []string{"spiny", "thorny"}},
+				},
+			},
+			want: []Relations{
+				{PartOfSpeech: "adjective", Synonyms: []string{"spiny", "thorny", "touchy"}, Antonyms: []string{"smooth"}},
+				{PartOfSpeech: "noun", Synonyms: []string{"thorn", "spine"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.entry.Thesaurus(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Thesaurus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,17 +2,17 @@ package dictionarytest
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/caproven/termdict/dictionary"
 )
 
-type InMemoryDefiner map[string][]dictionary.Definition
+type InMemoryDefiner map[string][]dictionary.Entry
 
-func (m InMemoryDefiner) Define(_ context.Context, word string) ([]dictionary.Definition, error) {
-	defs, ok := m[word]
+// Define returns the entries for a word, regardless of language
+func (m InMemoryDefiner) Define(_ context.Context, word, _ string) ([]dictionary.Entry, error) {
+	entries, ok := m[word]
 	if !ok {
-		return nil, fmt.Errorf("word '%s' not found", word)
+		return nil, &dictionary.LookupError{Word: word, Err: dictionary.ErrNotFound}
 	}
-	return defs, nil
+	return entries, nil
 }
@@ -1,6 +1,7 @@
 package dictionarytest
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
@@ -12,26 +13,30 @@ func TestInMemoryDefiner_Define(t *testing.T) {
 		name    string
 		m       InMemoryDefiner
 		word    string
-		want    []dictionary.Definition
+		want    []dictionary.Entry
 		wantErr bool
 	}{
 		{
 			name: "gives definition",
 			m: InMemoryDefiner{
-				"exacerbate": []dictionary.Definition{
+				"exacerbate": []dictionary.Entry{{
+					Definitions: []dictionary.Definition{
+						{
+							PartOfSpeech: "verb",
+							Meaning:      "To make worse",
+						},
+					},
+				}},
+			},
+			word: "exacerbate",
+			want: []dictionary.Entry{{
+				Definitions: []dictionary.Definition{
 					{
 						PartOfSpeech: "verb",
 						Meaning:      "To make worse",
 					},
 				},
-			},
-			word: "exacerbate",
-			want: []dictionary.Definition{
-				{
-					PartOfSpeech: "verb",
-					Meaning:      "To make worse",
-				},
-			},
+			}},
 			wantErr: false,
 		},
 		{
@@ -44,11 +49,14 @@ func TestInMemoryDefiner_Define(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := tt.m.Define(t.Context(), tt.word)
+			got, err := tt.m.Define(t.Context(), tt.word, "en")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Define() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if tt.wantErr && !errors.Is(err, dictionary.ErrNotFound) {
+				t.Errorf("Define() error = %v, want it to wrap %v", err, dictionary.ErrNotFound)
+			}
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Define() got = %v, want %v", got, tt.want)
 			}
@@ -0,0 +1,11 @@
+// Package dump reads dictionary dumps, so words can be defined without access to a dictionary service.
+package dump
+
+import "github.com/caproven/termdict/dictionary"
+
+// Record is a single entry for a word read from a dump
+type Record struct {
+	Word     string
+	Language string
+	Entry    dictionary.Entry
+}
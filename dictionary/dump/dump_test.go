@@ -0,0 +1,203 @@
+package dump
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/caproven/termdict/dictionary"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadWiktextract(t *testing.T) {
+	gift1 := Record{Word: "gift", Language: "en", Entry: dictionary.Entry{
+		Phonetic: "/ɡɪft/",
+		Phonetics: []dictionary.Phonetic{
+			{Text: "/ɡɪft/"},
+			{Audio: "https://example.com/gift.mp3"},
+		},
+		Definitions: []dictionary.Definition{
+			{PartOfSpeech: "noun", Meaning: "Something given to another voluntarily, without charge.", Example: "She received a gift.", Synonyms: []string{"present"}},
+			{PartOfSpeech: "noun", Meaning: "A talent or natural ability."},
+			{PartOfSpeech: "verb", Meaning: "To give as a gift."},
+		},
+		Relations: []dictionary.Relations{{PartOfSpeech: "noun", Synonyms: []string{"donation"}}},
+	}}
+	gift2 := Record{Word: "gift", Language: "en", Entry: dictionary.Entry{
+		Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "Obsolete form of poison."}},
+	}}
+	german := Record{Word: "Gift", Language: "de", Entry: dictionary.Entry{
+		Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "poison"}},
+	}}
+
+	tests := map[string]struct {
+		lang string
+		want []Record
+	}{
+		"all languages": {
+			want: []Record{gift1, gift2, german},
+		},
+		"single language": {
+			lang: "de",
+			want: []Record{german},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f, err := os.Open(filepath.Join("testdata", "wiktextract.jsonl"))
+			require.NoError(t, err)
+			defer closeAndWarn(f)
+
+			var got []Record
+			err = ReadWiktextract(f, tt.lang, func(r Record) error {
+				got = append(got, r)
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("malformed line", func(t *testing.T) {
+		err := ReadWiktextract(strings.NewReader(`{"word": "gift"`), "", func(Record) error { return nil })
+		assert.Error(t, err)
+	})
+
+	t.Run("callback error stops reading", func(t *testing.T) {
+		f, err := os.Open(filepath.Join("testdata", "wiktextract.jsonl"))
+		require.NoError(t, err)
+		defer closeAndWarn(f)
+
+		stop := errors.New("stop")
+		calls := 0
+		err = ReadWiktextract(f, "", func(Record) error {
+			calls++
+			return stop
+		})
+		assert.ErrorIs(t, err, stop)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+// starDictWord is a word to write to a test StarDict dictionary
+type starDictWord struct {
+	word string
+	data []byte
+}
+
+// writeStarDict writes a StarDict dictionary to dir, returning the path of its .ifo file. The .idx and .dict files are
+// compressed if requested.
+func writeStarDict(t *testing.T, dir, ifo string, words []starDictWord, compress bool) string {
+	t.Helper()
+	var idx, dict bytes.Buffer
+	for _, w := range words {
+		idx.WriteString(w.word)
+		idx.WriteByte(0)
+		require.NoError(t, binary.Write(&idx, binary.BigEndian, uint32(dict.Len())))
+		require.NoError(t, binary.Write(&idx, binary.BigEndian, uint32(len(w.data))))
+		dict.Write(w.data)
+	}
+
+	base := filepath.Join(dir, "test")
+	require.NoError(t, os.WriteFile(base+".ifo", []byte(ifo), 0o600))
+	write := func(path string, data []byte) {
+		if !compress {
+			require.NoError(t, os.WriteFile(path, data, 0o600))
+			return
+		}
+		var b bytes.Buffer
+		gz := gzip.NewWriter(&b)
+		_, err := gz.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+		require.NoError(t, os.WriteFile(path, b.Bytes(), 0o600))
+	}
+	if compress {
+		write(base+".idx.gz", idx.Bytes())
+		write(base+".dict.dz", dict.Bytes())
+	} else {
+		write(base+".idx", idx.Bytes())
+		write(base+".dict", dict.Bytes())
+	}
+	return base + ".ifo"
+}
+
+func TestStarDict(t *testing.T) {
+	t.Run("same type sequence", func(t *testing.T) {
+		for name, compress := range map[string]bool{"plain": false, "compressed": true} {
+			t.Run(name, func(t *testing.T) {
+				ifo := "StarDict's dict ifo file\nversion=2.4.2\nbookname=Test Dictionary\nwordcount=3\nsametypesequence=tm\n"
+				path := writeStarDict(t, t.TempDir(), ifo, []starDictWord{
+					{word: "apple", data: []byte("ˈæp.əl\x00A fruit.\nA tree.")},
+					{word: "empty", data: []byte("\x00")},
+					{word: "pear", data: []byte("\x00A fruit.")},
+				}, compress)
+
+				d, err := OpenStarDict(path)
+				require.NoError(t, err)
+				assert.Equal(t, "Test Dictionary", d.Name)
+				assert.Equal(t, 3, d.WordCount)
+
+				var got []Record
+				require.NoError(t, d.Read("en", func(r Record) error {
+					got = append(got, r)
+					return nil
+				}))
+				assert.Equal(t, []Record{
+					{Word: "apple", Language: "en", Entry: dictionary.Entry{
+						Phonetic:  "ˈæp.əl",
+						Phonetics: []dictionary.Phonetic{{Text: "ˈæp.əl"}},
+						Definitions: []dictionary.Definition{
+							{Meaning: "A fruit."},
+							{Meaning: "A tree."},
+						},
+					}},
+					{Word: "pear", Language: "en", Entry: dictionary.Entry{
+						Definitions: []dictionary.Definition{{Meaning: "A fruit."}},
+					}},
+				}, got)
+			})
+		}
+	})
+
+	t.Run("typed fields", func(t *testing.T) {
+		ifo := "StarDict's dict ifo file\nversion=2.4.2\nbookname=Typed\nwordcount=1\n"
+		var data bytes.Buffer
+		data.WriteString("h<b>bold</b> text<br>second &amp; line\x00")
+		data.WriteString("W")
+		require.NoError(t, binary.Write(&data, binary.BigEndian, uint32(3)))
+		data.Write([]byte{1, 2, 3})
+		data.WriteString("mplain\x00")
+		path := writeStarDict(t, t.TempDir(), ifo, []starDictWord{{word: "word", data: data.Bytes()}}, false)
+
+		d, err := OpenStarDict(path)
+		require.NoError(t, err)
+
+		var got []Record
+		require.NoError(t, d.Read("en", func(r Record) error {
+			got = append(got, r)
+			return nil
+		}))
+		assert.Equal(t, []Record{{Word: "word", Language: "en", Entry: dictionary.Entry{
+			Definitions: []dictionary.Definition{
+				{Meaning: "bold text"},
+				{Meaning: "second & line"},
+				{Meaning: "plain"},
+			},
+		}}}, got)
+	})
+
+	t.Run("not an ifo file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "bad.ifo")
+		require.NoError(t, os.WriteFile(path, []byte("bookname=bad\n"), 0o600))
+
+		_, err := OpenStarDict(path)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,306 @@
+package dump
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/caproven/termdict/dictionary"
+)
+
+// starDictMagic is the first line of every StarDict .ifo file
+const starDictMagic = "StarDict's dict ifo file"
+
+// StarDict is a dictionary in the StarDict format, made up of an .ifo file describing it, an .idx file listing its
+// words, and a .dict file holding their definitions. The .idx and .dict files may be compressed as .idx.gz and .dict.dz.
+// See https://github.com/huzheng001/stardict-3/blob/master/dict/doc/StarDictFileFormat for the format.
+type StarDict struct {
+	// Name is the name of the dictionary
+	Name string
+	// WordCount is the number of words in the dictionary
+	WordCount int
+
+	// base is the path of the .ifo file without its extension
+	base string
+	// sameTypeSequence lists the type of each field in the definitions, if all definitions share the same fields
+	sameTypeSequence string
+	// offsetBits is the size of offsets in the .idx file
+	offsetBits int
+}
+
+// OpenStarDict reads the .ifo file of a StarDict dictionary
+func OpenStarDict(ifoPath string) (*StarDict, error) {
+	data, err := os.ReadFile(ifoPath)
+	if err != nil {
+		return nil, fmt.Errorf("read ifo file: %w", err)
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	if strings.TrimSpace(lines[0]) != starDictMagic {
+		return nil, fmt.Errorf("%s is not a StarDict ifo file", ifoPath)
+	}
+	d := &StarDict{
+		base:       strings.TrimSuffix(ifoPath, ".ifo"),
+		offsetBits: 32,
+	}
+	for _, line := range lines[1:] {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "bookname":
+			d.Name = strings.TrimSpace(value)
+		case "wordcount":
+			if d.WordCount, err = strconv.Atoi(strings.TrimSpace(value)); err != nil {
+				return nil, fmt.Errorf("parse wordcount: %w", err)
+			}
+		case "sametypesequence":
+			d.sameTypeSequence = strings.TrimSpace(value)
+		case "idxoffsetbits":
+			if d.offsetBits, err = strconv.Atoi(strings.TrimSpace(value)); err != nil {
+				return nil, fmt.Errorf("parse idxoffsetbits: %w", err)
+			}
+			if d.offsetBits != 32 && d.offsetBits != 64 {
+				return nil, fmt.Errorf("unsupported idxoffsetbits %d", d.offsetBits)
+			}
+		}
+	}
+
+	return d, nil
+}
+
+// Read streams the words of the dictionary to fn, all tagged with lang since StarDict doesn't record a language.
+// Each line of a definition's text becomes a separate definition.
+func (d *StarDict) Read(lang string, fn func(Record) error) error {
+	idx, err := openMaybeCompressed(d.base + ".idx")
+	if err != nil {
+		return fmt.Errorf("open idx file: %w", err)
+	}
+	defer closeAndWarn(idx)
+
+	dict, err := openDict(d.base + ".dict")
+	if err != nil {
+		return fmt.Errorf("open dict file: %w", err)
+	}
+	defer closeAndWarn(dict)
+
+	r := bufio.NewReader(idx)
+	for {
+		word, err := r.ReadString(0)
+		if errors.Is(err, io.EOF) && word == "" {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read idx word: %w", err)
+		}
+		word = strings.TrimSuffix(word, "\x00")
+
+		var offset, size uint64
+		if d.offsetBits == 64 {
+			err = binary.Read(r, binary.BigEndian, &offset)
+		} else {
+			var offset32 uint32
+			err = binary.Read(r, binary.BigEndian, &offset32)
+			offset = uint64(offset32)
+		}
+		if err != nil {
+			return fmt.Errorf("read idx offset for word %q: %w", word, err)
+		}
+		var size32 uint32
+		if err := binary.Read(r, binary.BigEndian, &size32); err != nil {
+			return fmt.Errorf("read idx size for word %q: %w", word, err)
+		}
+		size = uint64(size32)
+
+		data := make([]byte, size)
+		if _, err := dict.ReadAt(data, int64(offset)); err != nil {
+			return fmt.Errorf("read definition of word %q: %w", word, err)
+		}
+
+		entry, err := d.parseEntry(data)
+		if err != nil {
+			return fmt.Errorf("parse definition of word %q: %w", word, err)
+		}
+		if len(entry.Definitions) == 0 {
+			continue
+		}
+		if err := fn(Record{Word: word, Language: lang, Entry: entry}); err != nil {
+			return err
+		}
+	}
+}
+
+// parseEntry converts the fields of a definition into an entry. Only textual fields are kept.
+func (d *StarDict) parseEntry(data []byte) (dictionary.Entry, error) {
+	var entry dictionary.Entry
+	add := func(fieldType byte, value []byte) {
+		text := string(value)
+		switch fieldType {
+		case 't', 'y':
+			// Phonetic transcriptions
+			if text = strings.TrimSpace(text); text != "" {
+				if entry.Phonetic == "" {
+					entry.Phonetic = text
+				}
+				entry.Phonetics = append(entry.Phonetics, dictionary.Phonetic{Text: text})
+			}
+			return
+		case 'g', 'h', 'x', 'k':
+			// Markup; break lines at the tags which separate them before removing the rest
+			for _, tag := range []string{"<br>", "<br/>", "<br />", "</p>", "</div>", "</li>"} {
+				text = strings.ReplaceAll(text, tag, "\n")
+			}
+		case 'm', 'l', 'w', 'n':
+		default:
+			return
+		}
+		for _, line := range strings.Split(text, "\n") {
+			if meaning := dictionary.StripTags(line); meaning != "" {
+				entry.Definitions = append(entry.Definitions, dictionary.Definition{Meaning: meaning})
+			}
+		}
+	}
+
+	if d.sameTypeSequence != "" {
+		for i := 0; i < len(d.sameTypeSequence); i++ {
+			last := i == len(d.sameTypeSequence)-1
+			var value []byte
+			var err error
+			value, data, err = readField(d.sameTypeSequence[i], data, last)
+			if err != nil {
+				return entry, err
+			}
+			add(d.sameTypeSequence[i], value)
+		}
+		return entry, nil
+	}
+
+	for len(data) > 0 {
+		fieldType := data[0]
+		var value []byte
+		var err error
+		value, data, err = readField(fieldType, data[1:], false)
+		if err != nil {
+			return entry, err
+		}
+		add(fieldType, value)
+	}
+	return entry, nil
+}
+
+// readField splits the next field of a definition from the remaining data. Lowercase types are text terminated by
+// a null byte, and uppercase types are binary data prefixed by their size. The last field of a same type sequence has
+// neither, taking up the rest of the data.
+func readField(fieldType byte, data []byte, last bool) (value, rest []byte, err error) {
+	if last {
+		return data, nil, nil
+	}
+	if fieldType >= 'a' && fieldType <= 'z' {
+		end := bytes.IndexByte(data, 0)
+		if end < 0 {
+			// Tolerate a missing terminator at the end of the data
+			return data, nil, nil
+		}
+		return data[:end], data[end+1:], nil
+	}
+	if len(data) < 4 {
+		return nil, nil, fmt.Errorf("truncated size of field %q", fieldType)
+	}
+	size := binary.BigEndian.Uint32(data)
+	data = data[4:]
+	if uint64(len(data)) < uint64(size) {
+		return nil, nil, fmt.Errorf("truncated field %q", fieldType)
+	}
+	return data[:size], data[size:], nil
+}
+
+// openMaybeCompressed opens path, or its gzip compressed version with a .gz extension if path doesn't exist
+func openMaybeCompressed(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err == nil {
+		return f, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	f, err = os.Open(path + ".gz")
+	if err != nil {
+		return nil, err
+	}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, errors.Join(err, f.Close())
+	}
+	return readCloser{Reader: gz, closers: []io.Closer{gz, f}}, nil
+}
+
+// dictReader provides random access to the definitions in a .dict file
+type dictReader interface {
+	io.ReaderAt
+	io.Closer
+}
+
+// openDict opens the .dict file at path. If only the compressed .dict.dz exists, it's decompressed to a temporary
+// file, since definitions are read at arbitrary offsets.
+func openDict(path string) (dictReader, error) {
+	f, err := os.Open(path)
+	if err == nil {
+		return f, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	compressed, err := os.Open(path + ".dz")
+	if err != nil {
+		return nil, err
+	}
+	defer closeAndWarn(compressed)
+	gz, err := gzip.NewReader(compressed)
+	if err != nil {
+		return nil, fmt.Errorf("decompress: %w", err)
+	}
+
+	tmp, err := os.CreateTemp("", "termdict-*.dict")
+	if err != nil {
+		return nil, err
+	}
+	// The file stays readable through the open handle
+	if err := os.Remove(tmp.Name()); err != nil {
+		slog.Warn("Failed to remove temporary file", "file", tmp.Name(), "error", err)
+	}
+	if _, err := io.Copy(tmp, gz); err != nil {
+		return nil, errors.Join(fmt.Errorf("decompress: %w", err), tmp.Close())
+	}
+	return tmp, nil
+}
+
+// readCloser closes multiple underlying readers
+type readCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (rc readCloser) Close() error {
+	var errs []error
+	for _, c := range rc.closers {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
+
+func closeAndWarn(c io.Closer) {
+	if err := c.Close(); err != nil {
+		slog.Warn("Failed to close file", "error", err)
+	}
+}
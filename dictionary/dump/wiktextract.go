@@ -0,0 +1,141 @@
+package dump
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/caproven/termdict/dictionary"
+)
+
+// wiktextractWord is a line of a Wiktextract dump, holding a word's senses for a single part of speech.
+// See https://github.com/tatuylonen/wiktextract for the full format.
+type wiktextractWord struct {
+	Word            string             `json:"word"`
+	LangCode        string             `json:"lang_code"`
+	POS             string             `json:"pos"`
+	EtymologyNumber int                `json:"etymology_number"`
+	Senses          []wiktextractSense `json:"senses"`
+	Sounds          []wiktextractSound `json:"sounds"`
+	Synonyms        []wiktextractLink  `json:"synonyms"`
+	Antonyms        []wiktextractLink  `json:"antonyms"`
+}
+
+type wiktextractSense struct {
+	// Glosses go from the most general sense to the most specific
+	Glosses  []string `json:"glosses"`
+	Examples []struct {
+		Text string `json:"text"`
+	} `json:"examples"`
+	Synonyms []wiktextractLink `json:"synonyms"`
+	Antonyms []wiktextractLink `json:"antonyms"`
+}
+
+type wiktextractSound struct {
+	IPA    string `json:"ipa"`
+	MP3URL string `json:"mp3_url"`
+}
+
+type wiktextractLink struct {
+	Word string `json:"word"`
+}
+
+// ReadWiktextract streams the words of a Wiktextract JSONL dump to fn. Consecutive lines for the same word and
+// etymology are combined into a single entry. If lang is not empty, words in other languages are skipped.
+func ReadWiktextract(r io.Reader, lang string, fn func(Record) error) error {
+	dec := json.NewDecoder(r)
+
+	type groupKey struct {
+		word, lang string
+		etymology  int
+	}
+	var current Record
+	var currentKey groupKey
+	flush := func() error {
+		if len(current.Entry.Definitions) == 0 {
+			return nil
+		}
+		return fn(current)
+	}
+
+	for {
+		var w wiktextractWord
+		if err := dec.Decode(&w); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return fmt.Errorf("decode word at offset %d: %w", dec.InputOffset(), err)
+		}
+		if w.Word == "" || (lang != "" && w.LangCode != lang) {
+			continue
+		}
+
+		key := groupKey{word: w.Word, lang: w.LangCode, etymology: w.EtymologyNumber}
+		if key != currentKey {
+			if err := flush(); err != nil {
+				return err
+			}
+			current = Record{Word: w.Word, Language: w.LangCode}
+			currentKey = key
+		}
+		w.addTo(&current.Entry)
+	}
+
+	return flush()
+}
+
+// addTo appends the word's senses and pronunciations to entry
+func (w wiktextractWord) addTo(entry *dictionary.Entry) {
+	for _, sound := range w.Sounds {
+		if sound.IPA == "" && sound.MP3URL == "" {
+			continue
+		}
+		if entry.Phonetic == "" {
+			entry.Phonetic = sound.IPA
+		}
+		p := dictionary.Phonetic{Text: sound.IPA, Audio: sound.MP3URL}
+		if !slices.Contains(entry.Phonetics, p) {
+			entry.Phonetics = append(entry.Phonetics, p)
+		}
+	}
+
+	for _, sense := range w.Senses {
+		if len(sense.Glosses) == 0 {
+			continue
+		}
+		def := dictionary.Definition{
+			PartOfSpeech: w.POS,
+			Meaning:      dictionary.StripTags(sense.Glosses[len(sense.Glosses)-1]),
+			Synonyms:     linkWords(sense.Synonyms),
+			Antonyms:     linkWords(sense.Antonyms),
+		}
+		if def.Meaning == "" {
+			continue
+		}
+		if len(sense.Examples) > 0 {
+			def.Example = dictionary.StripTags(sense.Examples[0].Text)
+		}
+		entry.Definitions = append(entry.Definitions, def)
+	}
+
+	if len(w.Synonyms) > 0 || len(w.Antonyms) > 0 {
+		entry.Relations = append(entry.Relations, dictionary.Relations{
+			PartOfSpeech: w.POS,
+			Synonyms:     linkWords(w.Synonyms),
+			Antonyms:     linkWords(w.Antonyms),
+		})
+	}
+}
+
+// linkWords returns the words being linked to, or nil if there are none
+func linkWords(links []wiktextractLink) []string {
+	var words []string
+	for _, link := range links {
+		if link.Word != "" {
+			words = append(words, link.Word)
+		}
+	}
+	return words
+}
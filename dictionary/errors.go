@@ -0,0 +1,34 @@
+package dictionary
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotFound means the dictionary has no definitions for a word
+	ErrNotFound = errors.New("no definitions found")
+	// ErrRateLimited means the dictionary service rejected a request for being sent too often
+	ErrRateLimited = errors.New("rate limited by dictionary service")
+	// ErrServer means the dictionary service failed to handle a request or sent back a malformed response
+	ErrServer = errors.New("dictionary service error")
+	// ErrTransport means the dictionary service couldn't be reached
+	ErrTransport = errors.New("dictionary service unreachable")
+	// ErrOffline means a word wasn't cached and couldn't be defined because the network isn't used
+	ErrOffline = errors.New("not cached and offline")
+)
+
+// LookupError records a failure to define a word. The cause can be checked with errors.Is against the sentinel errors
+// of this package.
+type LookupError struct {
+	Word string
+	Err  error
+}
+
+func (e *LookupError) Error() string {
+	return fmt.Sprintf("define word %q: %v", e.Word, e.Err)
+}
+
+func (e *LookupError) Unwrap() error {
+	return e.Err
+}
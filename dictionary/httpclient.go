@@ -0,0 +1,218 @@
+package dictionary
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultUserAgent identifies termdict to dictionary services
+	DefaultUserAgent = "termdict (+https://github.com/caproven/termdict)"
+	// DefaultTimeout is how long a single request to a dictionary service may take when not told otherwise
+	DefaultTimeout = 10 * time.Second
+	// DefaultRetries is how many times a failed request is retried when not told otherwise
+	DefaultRetries = 3
+)
+
+const (
+	// retryBackoff is the longest wait before the first retry. It doubles with each retry, up to maxRetryBackoff.
+	retryBackoff    = 500 * time.Millisecond
+	maxRetryBackoff = 10 * time.Second
+	// maxRetryAfter is the longest a service can ask to be waited for before a retry. Asking for longer fails the
+	// request instead, rather than leaving the user waiting.
+	maxRetryAfter = 30 * time.Second
+)
+
+// HTTPOptions configure the client used to reach dictionary services
+type HTTPOptions struct {
+	// Timeout is how long a single request may take, including reading the response. Zero means no limit.
+	Timeout time.Duration
+	// Retries is how many times a request is retried after the service fails or rate limits it. Retries back off
+	// exponentially with jitter, or wait as long as the service asks with a Retry-After header.
+	Retries int
+	// UserAgent is sent with every request
+	UserAgent string
+	// Proxy is the URL of the proxy requests are sent through. The HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment
+	// variables are used if it's empty.
+	Proxy string
+	// CABundle is the path to a PEM file of certificate authorities to trust, besides those of the system
+	CABundle string
+}
+
+// DefaultHTTPOptions returns the options of the client used when not told otherwise
+func DefaultHTTPOptions() HTTPOptions {
+	return HTTPOptions{
+		Timeout:   DefaultTimeout,
+		Retries:   DefaultRetries,
+		UserAgent: DefaultUserAgent,
+	}
+}
+
+// defaultHTTPClient is the client of providers created without one. The default options need no files or parsing,
+// so can't fail.
+var defaultHTTPClient, _ = NewHTTPClient(DefaultHTTPOptions())
+
+// NewHTTPClient creates a client for reaching dictionary services with the given options
+func NewHTTPClient(opts HTTPOptions) (*http.Client, error) {
+	if opts.Timeout < 0 {
+		return nil, errors.New("timeout can't be negative")
+	}
+	if opts.Retries < 0 {
+		return nil, errors.New("retries can't be negative")
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if opts.Proxy != "" {
+		proxy, err := url.Parse(opts.Proxy)
+		if err != nil || proxy.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", opts.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	if opts.CABundle != "" {
+		pool, err := certPool(opts.CABundle)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return &http.Client{Transport: &retryTransport{
+		next:      transport,
+		userAgent: opts.UserAgent,
+		timeout:   opts.Timeout,
+		retries:   opts.Retries,
+		sleep:     sleep,
+	}}, nil
+}
+
+// certPool returns the system's certificate authorities along with those of a PEM file
+func certPool(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read CA bundle: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in CA bundle %s", path)
+	}
+	return pool, nil
+}
+
+// retryTransport sends requests with a timeout and User-Agent, retrying those the service fails or rate limits
+type retryTransport struct {
+	next      http.RoundTripper
+	userAgent string
+	timeout   time.Duration
+	retries   int
+	// sleep waits for d, or until ctx is done
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.userAgent != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+	// Requests with a body can't be sent again, and only requests without side effects are safe to repeat
+	retryable := req.Body == nil && (req.Method == http.MethodGet || req.Method == http.MethodHead)
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.attempt(req)
+		if err != nil || !retryable || attempt == t.retries || !retryStatus(resp.StatusCode) {
+			return resp, err
+		}
+		wait, ok := retryWait(resp, attempt)
+		if !ok {
+			return resp, nil
+		}
+		// The response is dropped, so its connection can be reused once it's read
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		if err := t.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// attempt sends a request once, limiting it to the transport's timeout
+func (t *retryTransport) attempt(req *http.Request) (*http.Response, error) {
+	if t.timeout <= 0 {
+		return t.next.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// The timeout covers reading the body, so it only ends once the body is closed
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelBody cancels the context of its request when closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// retryStatus reports whether a response with the status is worth retrying
+func retryStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// retryWait returns how long to wait before retrying a request after a response. A service asking with Retry-After
+// is waited for as long as it asks, unless that's longer than maxRetryAfter, in which case the request isn't retried.
+// Otherwise, the wait is a random duration up to an exponentially growing limit, so that clients failing together
+// don't retry together.
+func retryWait(resp *http.Response, attempt int) (time.Duration, bool) {
+	if after, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+		return after, after <= maxRetryAfter
+	}
+	limit := min(retryBackoff<<attempt, maxRetryBackoff)
+	return rand.N(limit) + 1, true
+}
+
+// parseRetryAfter parses a Retry-After header, given either as seconds or an HTTP date
+func parseRetryAfter(header string, now time.Time) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second, true
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,328 @@
+package dictionary
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// faultServer serves a definition of snow after failing the first failures requests with status and headers
+func faultServer(failures int, status int, header http.Header) (*httptest.Server, *atomic.Int32) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if int(attempts.Add(1)) <= failures {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(status)
+			return
+		}
+		_, _ = fmt.Fprint(w, `[{"word":"snow","meanings":[{"partOfSpeech":"noun","definitions":[{"definition":"Frozen water."}]}]}]`)
+	}))
+	return server, &attempts
+}
+
+// newTestClient creates a client with opts which records how long it waits between retries rather than waiting
+func newTestClient(t *testing.T, opts HTTPOptions, waits *[]time.Duration) *http.Client {
+	t.Helper()
+	client, err := NewHTTPClient(opts)
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %v", err)
+	}
+	client.Transport.(*retryTransport).sleep = func(_ context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return nil
+	}
+	return client
+}
+
+func TestHTTPClient_Retries(t *testing.T) {
+	cases := []struct {
+		name         string
+		retries      int
+		failures     int
+		status       int
+		header       http.Header
+		wantErr      error
+		wantAttempts int32
+		// wantWaits are the waits expected between retries, if they aren't random
+		wantWaits []time.Duration
+	}{
+		{
+			name:         "server errors then success",
+			retries:      3,
+			failures:     2,
+			status:       http.StatusServiceUnavailable,
+			wantAttempts: 3,
+		},
+		{
+			name:         "rate limited then success",
+			retries:      3,
+			failures:     1,
+			status:       http.StatusTooManyRequests,
+			wantAttempts: 2,
+		},
+		{
+			name:         "retry after seconds",
+			retries:      3,
+			failures:     2,
+			status:       http.StatusTooManyRequests,
+			header:       http.Header{"Retry-After": {"2"}},
+			wantAttempts: 3,
+			wantWaits:    []time.Duration{2 * time.Second, 2 * time.Second},
+		},
+		{
+			name:         "retry after too long",
+			retries:      3,
+			failures:     1,
+			status:       http.StatusTooManyRequests,
+			header:       http.Header{"Retry-After": {"3600"}},
+			wantErr:      ErrRateLimited,
+			wantAttempts: 1,
+			wantWaits:    []time.Duration{},
+		},
+		{
+			name:         "retries exhausted",
+			retries:      2,
+			failures:     10,
+			status:       http.StatusBadGateway,
+			wantErr:      ErrServer,
+			wantAttempts: 3,
+		},
+		{
+			name:         "retries disabled",
+			retries:      0,
+			failures:     1,
+			status:       http.StatusServiceUnavailable,
+			wantErr:      ErrServer,
+			wantAttempts: 1,
+			wantWaits:    []time.Duration{},
+		},
+		{
+			name:         "not found isn't retried",
+			retries:      3,
+			failures:     1,
+			status:       http.StatusNotFound,
+			wantErr:      ErrNotFound,
+			wantAttempts: 1,
+			wantWaits:    []time.Duration{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server, attempts := faultServer(tc.failures, tc.status, tc.header)
+			defer server.Close()
+
+			waits := []time.Duration{}
+			api := WebAPI{
+				url:        server.URL,
+				endpoint:   defineEndpoint,
+				httpClient: newTestClient(t, HTTPOptions{Retries: tc.retries}, &waits),
+			}
+
+			_, err := api.Define(t.Context(), "snow", "en")
+			if tc.wantErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("got err %v, expected it to wrap %v", err, tc.wantErr)
+			}
+			if got := attempts.Load(); got != tc.wantAttempts {
+				t.Errorf("got %d attempts, expected %d", got, tc.wantAttempts)
+			}
+			if len(waits) != int(tc.wantAttempts)-1 && tc.wantErr == nil {
+				t.Errorf("got %d waits, expected one between each attempt", len(waits))
+			}
+			if tc.wantWaits != nil && fmt.Sprint(waits) != fmt.Sprint(tc.wantWaits) {
+				t.Errorf("got waits %v, expected %v", waits, tc.wantWaits)
+			}
+		})
+	}
+}
+
+func TestHTTPClient_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewHTTPClient(HTTPOptions{Timeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %v", err)
+	}
+	api := WebAPI{url: server.URL, endpoint: defineEndpoint, httpClient: client}
+
+	start := time.Now()
+	_, err = api.Define(t.Context(), "snow", "en")
+	if !errors.Is(err, ErrTransport) {
+		t.Errorf("got err %v, expected it to wrap %v", err, ErrTransport)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("request took %v, expected it to time out", elapsed)
+	}
+}
+
+func TestHTTPClient_CanceledWhileWaiting(t *testing.T) {
+	server, attempts := faultServer(1, http.StatusServiceUnavailable, http.Header{"Retry-After": {"20"}})
+	defer server.Close()
+
+	client, err := NewHTTPClient(HTTPOptions{Retries: 3})
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %v", err)
+	}
+	api := WebAPI{url: server.URL, endpoint: defineEndpoint, httpClient: client}
+
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := api.Define(ctx, "snow", "en"); err == nil {
+		t.Error("expected an error once the context is done")
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("got %d attempts, expected 1", got)
+	}
+}
+
+func TestHTTPClient_UserAgent(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.UserAgent()
+		_, _ = fmt.Fprint(w, `[]`)
+	}))
+	defer server.Close()
+
+	client, err := NewHTTPClient(DefaultHTTPOptions())
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %v", err)
+	}
+	api := WebAPI{url: server.URL, endpoint: defineEndpoint, httpClient: client}
+	_, _ = api.Define(t.Context(), "snow", "en")
+
+	if got != DefaultUserAgent {
+		t.Errorf("got User-Agent %q, expected %q", got, DefaultUserAgent)
+	}
+}
+
+func TestHTTPClient_CABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(w, `[{"word":"snow","meanings":[{"partOfSpeech":"noun","definitions":[{"definition":"Frozen water."}]}]}]`)
+	}))
+	defer server.Close()
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("untrusted without bundle", func(t *testing.T) {
+		client, err := NewHTTPClient(HTTPOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error creating client: %v", err)
+		}
+		api := WebAPI{url: server.URL, endpoint: defineEndpoint, httpClient: client}
+		if _, err := api.Define(t.Context(), "snow", "en"); !errors.Is(err, ErrTransport) {
+			t.Errorf("got err %v, expected it to wrap %v", err, ErrTransport)
+		}
+	})
+
+	t.Run("trusted with bundle", func(t *testing.T) {
+		client, err := NewHTTPClient(HTTPOptions{CABundle: bundle})
+		if err != nil {
+			t.Fatalf("unexpected error creating client: %v", err)
+		}
+		api := WebAPI{url: server.URL, endpoint: defineEndpoint, httpClient: client}
+		if _, err := api.Define(t.Context(), "snow", "en"); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("bundle without certificates", func(t *testing.T) {
+		empty := filepath.Join(t.TempDir(), "empty.pem")
+		if err := os.WriteFile(empty, []byte("not a certificate"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := NewHTTPClient(HTTPOptions{CABundle: empty}); err == nil {
+			t.Error("expected an error for a bundle without certificates")
+		}
+	})
+
+	t.Run("missing bundle", func(t *testing.T) {
+		if _, err := NewHTTPClient(HTTPOptions{CABundle: filepath.Join(t.TempDir(), "missing.pem")}); err == nil {
+			t.Error("expected an error for a missing bundle")
+		}
+	})
+}
+
+func TestHTTPClient_Proxy(t *testing.T) {
+	var gotHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Requests through a proxy carry the full URL of the service
+		gotHost = r.URL.Host
+		_, _ = fmt.Fprint(w, `[{"word":"snow","meanings":[{"partOfSpeech":"noun","definitions":[{"definition":"Frozen water."}]}]}]`)
+	}))
+	defer proxy.Close()
+
+	client, err := NewHTTPClient(HTTPOptions{Proxy: proxy.URL})
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %v", err)
+	}
+	api := WebAPI{url: "http://dictionary.invalid", endpoint: defineEndpoint, httpClient: client}
+	if _, err := api.Define(t.Context(), "snow", "en"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotHost != "dictionary.invalid" {
+		t.Errorf("proxy got request for host %q, expected dictionary.invalid", gotHost)
+	}
+
+	if _, err := NewHTTPClient(HTTPOptions{Proxy: "not a url"}); err == nil {
+		t.Error("expected an error for an invalid proxy URL")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		header string
+		want   time.Duration
+		wantOK bool
+	}{
+		{header: "", wantOK: false},
+		{header: "5", want: 5 * time.Second, wantOK: true},
+		{header: "-5", want: 0, wantOK: true},
+		{header: "Fri, 01 Mar 2024 12:00:30 GMT", want: 30 * time.Second, wantOK: true},
+		{header: "Fri, 01 Mar 2024 11:00:00 GMT", want: 0, wantOK: true},
+		{header: "soon", wantOK: false},
+	}
+
+	for _, tc := range cases {
+		got, ok := parseRetryAfter(tc.header, now)
+		if got != tc.want || ok != tc.wantOK {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; expected %v, %v", tc.header, got, ok, tc.want, tc.wantOK)
+		}
+	}
+}
+
+func TestRetryWait_Backoff(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	for attempt := range 8 {
+		limit := min(retryBackoff<<attempt, maxRetryBackoff)
+		for range 20 {
+			wait, ok := retryWait(resp, attempt)
+			if !ok || wait <= 0 || wait > limit {
+				t.Fatalf("attempt %d waited %v, expected up to %v", attempt, wait, limit)
+			}
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package dictionary
+
+import (
+	"context"
+)
+
+// ProviderOffline is the name of the provider serving words imported from a local dictionary dump
+const ProviderOffline = "offline"
+
+// OfflineDefiner defines words using only those stored locally, such as words imported from a dictionary dump. It
+// never accesses the network.
+type OfflineDefiner struct {
+	words Cache
+}
+
+func NewOfflineDefiner(words Cache) *OfflineDefiner {
+	return &OfflineDefiner{words: words}
+}
+
+func (d *OfflineDefiner) Define(ctx context.Context, word, lang string) ([]Entry, error) {
+	if lang == "" {
+		lang = DefaultLanguage
+	}
+	ok, err := d.words.ContainsWord(ctx, word, lang)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, &LookupError{Word: word, Err: ErrNotFound}
+	}
+	return d.words.LookupWord(ctx, word, lang)
+}
@@ -0,0 +1,61 @@
+package dictionary_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/caproven/termdict/dictionary"
+)
+
+func TestOfflineDefiner_Define(t *testing.T) {
+	words := memoryCache{
+		"en/snow": {{Definitions: []dictionary.Definition{{Meaning: "frozen rain"}}}},
+		"de/gift": {{Definitions: []dictionary.Definition{{Meaning: "poison"}}}},
+	}
+	d := dictionary.NewOfflineDefiner(words)
+
+	tests := map[string]struct {
+		word    string
+		lang    string
+		want    []dictionary.Entry
+		wantErr error
+	}{
+		"word found": {
+			word: "snow",
+			lang: "en",
+			want: words["en/snow"],
+		},
+		"default language": {
+			word: "snow",
+			want: words["en/snow"],
+		},
+		"other language": {
+			word: "gift",
+			lang: "de",
+			want: words["de/gift"],
+		},
+		"word not found": {
+			word:    "gift",
+			lang:    "en",
+			wantErr: dictionary.ErrNotFound,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := d.Define(t.Context(), tt.word, tt.lang)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("got err %v, expected it to wrap %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("didn't expect err but got: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got entries %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package dictionary
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimitedDefiner spaces out the words defined by a Definer, so a dictionary service isn't sent requests more
+// often than it allows.
+type RateLimitedDefiner struct {
+	definer  Definer
+	interval time.Duration
+
+	mu sync.Mutex
+	// next is the earliest time the next word may be defined
+	next time.Time
+}
+
+// NewRateLimitedDefiner creates a definer which defines at most perSecond words a second. A rate which isn't positive
+// means no limit.
+func NewRateLimitedDefiner(d Definer, perSecond float64) *RateLimitedDefiner {
+	var interval time.Duration
+	if perSecond > 0 {
+		interval = time.Duration(float64(time.Second) / perSecond)
+	}
+	return &RateLimitedDefiner{definer: d, interval: interval}
+}
+
+func (d *RateLimitedDefiner) Define(ctx context.Context, word, lang string) ([]Entry, error) {
+	if wait := d.reserve(); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return d.definer.Define(ctx, word, lang)
+}
+
+// reserve claims the next slot for defining a word, returning how long to wait until it
+func (d *RateLimitedDefiner) reserve() time.Duration {
+	if d.interval <= 0 {
+		return 0
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	if d.next.Before(now) {
+		d.next = now
+	}
+	wait := d.next.Sub(now)
+	d.next = d.next.Add(d.interval)
+	return wait
+}
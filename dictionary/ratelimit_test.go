@@ -0,0 +1,63 @@
+package dictionary_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/caproven/termdict/dictionary"
+	"github.com/caproven/termdict/dictionary/dictionarytest"
+)
+
+func TestRateLimitedDefiner_Define(t *testing.T) {
+	definer := dictionarytest.InMemoryDefiner{"snow": {{Definitions: []dictionary.Definition{{Meaning: "Frozen rain."}}}}}
+
+	t.Run("spaces out words", func(t *testing.T) {
+		d := dictionary.NewRateLimitedDefiner(definer, 50)
+
+		start := time.Now()
+		var wg sync.WaitGroup
+		for range 5 {
+			wg.Go(func() {
+				if _, err := d.Define(t.Context(), "snow", "en"); err != nil {
+					t.Errorf("Define() error = %v", err)
+				}
+			})
+		}
+		wg.Wait()
+
+		// The first word is defined right away, and each of the others 20ms after the last
+		if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+			t.Errorf("defined 5 words in %v, want at least 80ms", elapsed)
+		}
+	})
+
+	t.Run("no limit", func(t *testing.T) {
+		d := dictionary.NewRateLimitedDefiner(definer, 0)
+
+		start := time.Now()
+		for range 100 {
+			if _, err := d.Define(t.Context(), "snow", "en"); err != nil {
+				t.Fatalf("Define() error = %v", err)
+			}
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("defined 100 words in %v without a limit", elapsed)
+		}
+	})
+
+	t.Run("cancelled while waiting", func(t *testing.T) {
+		d := dictionary.NewRateLimitedDefiner(definer, 0.1)
+		if _, err := d.Define(t.Context(), "snow", "en"); err != nil {
+			t.Fatalf("Define() error = %v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+		defer cancel()
+		if _, err := d.Define(ctx, "snow", "en"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Define() error = %v, want context.DeadlineExceeded", err)
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package dictionary
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+)
+
+// Names of the built-in providers
+const (
+	ProviderFreeDictionary = "freedictionary"
+	ProviderWiktionary     = "wiktionary"
+)
+
+// DefaultProvider is the provider used when none is chosen
+const DefaultProvider = ProviderFreeDictionary
+
+// ErrUnknownProvider means no provider is registered under a name
+var ErrUnknownProvider = errors.New("unknown dictionary provider")
+
+// Settings are the provider-specific options a provider is created with, such as the URL of the service
+type Settings map[string]string
+
+// ProviderFactory creates a provider's Definer from its settings
+type ProviderFactory func(settings Settings) (Definer, error)
+
+// Registry holds the providers words can be defined by, keyed by name.
+type Registry struct {
+	factories map[string]ProviderFactory
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[string]ProviderFactory)}
+}
+
+// NewDefaultRegistry creates a registry holding the built-in providers, which reach their services with client. A
+// client with the default HTTPOptions is used if it's nil.
+func NewDefaultRegistry(client *http.Client) *Registry {
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	r := NewRegistry()
+	// Names are distinct, so registration can't fail
+	_ = r.Register(ProviderFreeDictionary, newFreeDictionaryProvider(client))
+	_ = r.Register(ProviderWiktionary, newWiktionaryProvider(client))
+	return r
+}
+
+// Register adds a provider under a name. Names can only be registered once.
+func (r *Registry) Register(name string, factory ProviderFactory) error {
+	if name == "" {
+		return errors.New("provider name is blank")
+	}
+	if _, ok := r.factories[name]; ok {
+		return fmt.Errorf("provider %q already registered", name)
+	}
+	r.factories[name] = factory
+	return nil
+}
+
+// Provider creates the Definer for the named provider using the given settings.
+func (r *Registry) Provider(name string, settings Settings) (Definer, error) {
+	factory, ok := r.factories[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q; must be one of %v", ErrUnknownProvider, name, r.Names())
+	}
+	d, err := factory(settings)
+	if err != nil {
+		return nil, fmt.Errorf("configure provider %q: %w", name, err)
+	}
+	return d, nil
+}
+
+// Names returns the registered provider names in sorted order
+func (r *Registry) Names() []string {
+	return slices.Sorted(maps.Keys(r.factories))
+}
+
+// settingURL is the setting overriding the base URL of a provider's service
+const settingURL = "url"
+
+// checkSettings returns an error if settings has keys outside of those allowed, catching typos in config.
+func checkSettings(settings Settings, allowed ...string) error {
+	for _, key := range slices.Sorted(maps.Keys(settings)) {
+		if !slices.Contains(allowed, key) {
+			return fmt.Errorf("unknown setting %q", key)
+		}
+	}
+	return nil
+}
+
+func newFreeDictionaryProvider(client *http.Client) ProviderFactory {
+	return func(settings Settings) (Definer, error) {
+		if err := checkSettings(settings, settingURL); err != nil {
+			return nil, err
+		}
+		api := NewDefaultWebAPI()
+		api.httpClient = client
+		if u := settings[settingURL]; u != "" {
+			api.url = u
+		}
+		return api, nil
+	}
+}
+
+func newWiktionaryProvider(client *http.Client) ProviderFactory {
+	return func(settings Settings) (Definer, error) {
+		if err := checkSettings(settings, settingURL); err != nil {
+			return nil, err
+		}
+		api := NewDefaultWiktionaryAPI()
+		api.httpClient = client
+		if u := settings[settingURL]; u != "" {
+			api.url = u
+		}
+		return api, nil
+	}
+}
@@ -0,0 +1,99 @@
+package dictionary
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type stubDefiner struct {
+	settings Settings
+}
+
+func (d stubDefiner) Define(context.Context, string, string) ([]Entry, error) {
+	return nil, nil
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	factory := func(settings Settings) (Definer, error) {
+		return stubDefiner{settings: settings}, nil
+	}
+
+	if err := r.Register("stub", factory); err != nil {
+		t.Fatalf("didn't expect err but got: %v", err)
+	}
+	if err := r.Register("stub", factory); err == nil {
+		t.Error("expected err registering a duplicate name but didn't get one")
+	}
+	if err := r.Register("", factory); err == nil {
+		t.Error("expected err registering a blank name but didn't get one")
+	}
+
+	settings := Settings{"key": "value"}
+	d, err := r.Provider("stub", settings)
+	if err != nil {
+		t.Fatalf("didn't expect err but got: %v", err)
+	}
+	if got := d.(stubDefiner).settings; !reflect.DeepEqual(got, settings) {
+		t.Errorf("got settings %v, expected %v", got, settings)
+	}
+
+	if _, err := r.Provider("missing", nil); !errors.Is(err, ErrUnknownProvider) {
+		t.Errorf("got err %v, expected it to wrap %v", err, ErrUnknownProvider)
+	}
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	r := NewDefaultRegistry(nil)
+
+	want := []string{ProviderFreeDictionary, ProviderWiktionary}
+	if got := r.Names(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got names %v, expected %v", got, want)
+	}
+
+	tests := map[string]struct {
+		name     string
+		settings Settings
+		want     Definer
+		wantErr  bool
+	}{
+		"free dictionary defaults": {
+			name: ProviderFreeDictionary,
+			want: NewDefaultWebAPI(),
+		},
+		"free dictionary url": {
+			name:     ProviderFreeDictionary,
+			settings: Settings{"url": "http://localhost:8080"},
+			want:     WebAPI{url: "http://localhost:8080", endpoint: defaultEndpoint, httpClient: NewDefaultWebAPI().httpClient},
+		},
+		"wiktionary url": {
+			name:     ProviderWiktionary,
+			settings: Settings{"url": "https://de.wiktionary.org"},
+			want:     WiktionaryAPI{url: "https://de.wiktionary.org", httpClient: NewDefaultWiktionaryAPI().httpClient},
+		},
+		"unknown setting": {
+			name:     ProviderWiktionary,
+			settings: Settings{"uri": "https://de.wiktionary.org"},
+			wantErr:  true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := r.Provider(tt.name, tt.settings)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected err but didn't get one")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("didn't expect err but got: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got provider %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package dictionary
+
+// SearchQuery finds cached words by what their definitions mean
+type SearchQuery struct {
+	// Phrase is matched against the meanings of definitions. Words are stemmed, so "freezing" matches "freezes" and
+	// "freeze" alike.
+	Phrase string
+	// PartOfSpeech only matches definitions of this part of speech, if set
+	PartOfSpeech string
+	// Language only matches words in this language, if set
+	Language string
+	// ListOnly only matches words in the vocab list
+	ListOnly bool
+	// Limit is the most words to find. Zero means no limit.
+	Limit int
+}
+
+// SearchResult is a word with definitions matching a search
+type SearchResult struct {
+	Word     string
+	Language string
+	// Definitions are the word's definitions which matched, best match first
+	Definitions []Definition
+	// Score is how well the word's best definition matched. Higher is better.
+	Score float64
+}
@@ -0,0 +1,120 @@
+package dictionary
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// defaultWiktionaryURL is the default Wiktionary instance's URL
+const defaultWiktionaryURL string = "https://en.wiktionary.org"
+
+// wiktionaryEndpoint is the REST endpoint for defining words
+const wiktionaryEndpoint string = "/api/rest_v1/page/definition/"
+
+// wiktionaryLicense is the license Wiktionary content is distributed under
+var wiktionaryLicense = License{
+	Name: "CC BY-SA 4.0",
+	URL:  "https://creativecommons.org/licenses/by-sa/4.0",
+}
+
+// wiktionaryResponse maps a language code to the word's usages in that language
+type wiktionaryResponse map[string][]wiktionaryUsage
+
+// wiktionaryUsage is a series of definitions for a specific part of speech
+type wiktionaryUsage struct {
+	PartOfSpeech string
+	Language     string
+	Definitions  []wiktionaryDefinition
+}
+
+// wiktionaryDefinition is a single definition for a word. Text fields hold HTML markup.
+type wiktionaryDefinition struct {
+	Definition string
+	Examples   []string
+}
+
+// WiktionaryAPI defines words using the REST API of a Wiktionary instance
+type WiktionaryAPI struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewDefaultWiktionaryAPI creates a new instance for connecting to the English Wiktionary
+func NewDefaultWiktionaryAPI() WiktionaryAPI {
+	return WiktionaryAPI{
+		url:        defaultWiktionaryURL,
+		httpClient: defaultHTTPClient,
+	}
+}
+
+func (api WiktionaryAPI) Define(ctx context.Context, word, lang string) ([]Entry, error) {
+	if lang == "" {
+		lang = DefaultLanguage
+	}
+	entry, err := api.query(ctx, word, lang)
+	if err != nil {
+		return nil, &LookupError{Word: word, Err: err}
+	}
+	return []Entry{entry}, nil
+}
+
+// query requests the word's definitions in a language. Wiktionary groups usages by part of speech rather than by
+// etymology, so they're combined into a single entry. Errors wrap one of the package's sentinel errors.
+func (api WiktionaryAPI) query(ctx context.Context, word, lang string) (Entry, error) {
+	reqURL := api.url + wiktionaryEndpoint + url.PathEscape(word)
+	var resp wiktionaryResponse
+	if err := getJSON(ctx, api.httpClient, reqURL, &resp); err != nil {
+		return Entry{}, err
+	}
+
+	entry := Entry{
+		SourceURLs: []string{api.url + "/wiki/" + url.PathEscape(word)},
+		License:    wiktionaryLicense,
+	}
+	for _, usage := range resp[lang] {
+		for _, respDef := range usage.Definitions {
+			meaning := StripTags(respDef.Definition)
+			// Some definitions are only headings for nested senses
+			if meaning == "" {
+				continue
+			}
+			def := Definition{
+				PartOfSpeech: strings.ToLower(usage.PartOfSpeech),
+				Meaning:      meaning,
+			}
+			for _, example := range respDef.Examples {
+				if def.Example = StripTags(example); def.Example != "" {
+					break
+				}
+			}
+			entry.Definitions = append(entry.Definitions, def)
+		}
+	}
+	if len(entry.Definitions) == 0 {
+		return Entry{}, fmt.Errorf("%w in language %q", ErrNotFound, lang)
+	}
+
+	return entry, nil
+}
+
+// StripTags removes HTML markup from s, leaving its unescaped text with whitespace collapsed. Providers and dumps use
+// it to turn HTML definitions into plain text.
+func StripTags(s string) string {
+	var b strings.Builder
+	inTag := false
+	for _, r := range s {
+		switch {
+		case r == '<':
+			inTag = true
+		case r == '>' && inTag:
+			inTag = false
+		case !inTag:
+			b.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(html.UnescapeString(b.String())), " ")
+}
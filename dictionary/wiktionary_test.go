@@ -0,0 +1,166 @@
+package dictionary
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWiktionaryDefine(t *testing.T) {
+	mockWords := map[string]string{
+		"gift":         `{"en":[{"partOfSpeech":"Noun","language":"English","definitions":[{"definition":"Something given to another <a rel=\"mw:WikiLink\" href=\"/wiki/voluntarily\">voluntarily</a>, without charge.","examples":["","She received a <b>gift</b> from her friend."]},{"definition":""}]},{"partOfSpeech":"Verb","language":"English","definitions":[{"definition":"To give as a gift.","examples":[]}]}],"de":[{"partOfSpeech":"Noun","language":"German","definitions":[{"definition":"<span>poison</span> &amp; venom"}]}]}`,
+		"missing":      `{"title":"Not found."}`,
+		"rate_limited": `{}`,
+		"server_error": `<html>Internal Server Error</html>`,
+		"malformed":    `{"en":`,
+	}
+	statuses := map[string]int{
+		"missing":      http.StatusNotFound,
+		"rate_limited": http.StatusTooManyRequests,
+		"server_error": http.StatusServiceUnavailable,
+	}
+
+	apiServer := mockWiktionaryServer(mockWords, statuses)
+	defer apiServer.Close()
+
+	api := WiktionaryAPI{
+		url:        apiServer.URL,
+		httpClient: http.DefaultClient,
+	}
+	source := []string{apiServer.URL + "/wiki/gift"}
+
+	cases := []struct {
+		name    string
+		word    string
+		lang    string
+		entries []Entry
+		wantErr error
+	}{
+		{
+			name: "english",
+			word: "gift",
+			lang: "en",
+			entries: []Entry{{
+				Definitions: []Definition{
+					{PartOfSpeech: "noun", Meaning: "Something given to another voluntarily, without charge.", Example: "She received a gift from her friend."},
+					{PartOfSpeech: "verb", Meaning: "To give as a gift."},
+				},
+				SourceURLs: source,
+				License:    wiktionaryLicense,
+			}},
+		},
+		{
+			name: "german",
+			word: "gift",
+			lang: "de",
+			entries: []Entry{{
+				Definitions: []Definition{{PartOfSpeech: "noun", Meaning: "poison & venom"}},
+				SourceURLs:  source,
+				License:     wiktionaryLicense,
+			}},
+		},
+		{
+			name: "default language",
+			word: "gift",
+			entries: []Entry{{
+				Definitions: []Definition{
+					{PartOfSpeech: "noun", Meaning: "Something given to another voluntarily, without charge.", Example: "She received a gift from her friend."},
+					{PartOfSpeech: "verb", Meaning: "To give as a gift."},
+				},
+				SourceURLs: source,
+				License:    wiktionaryLicense,
+			}},
+		},
+		{
+			name:    "no usages in language",
+			word:    "gift",
+			lang:    "es",
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "word not found",
+			word:    "missing",
+			lang:    "en",
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "rate limited",
+			word:    "rate_limited",
+			lang:    "en",
+			wantErr: ErrRateLimited,
+		},
+		{
+			name:    "server error",
+			word:    "server_error",
+			lang:    "en",
+			wantErr: ErrServer,
+		},
+		{
+			name:    "malformed response",
+			word:    "malformed",
+			lang:    "en",
+			wantErr: ErrServer,
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := api.Define(t.Context(), test.word, test.lang)
+
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Errorf("got err %v, expected it to wrap %v", err, test.wantErr)
+				}
+				var lookupErr *LookupError
+				if !errors.As(err, &lookupErr) || lookupErr.Word != test.word {
+					t.Errorf("got err %v, expected a lookup error for word %q", err, test.word)
+				}
+			} else if err != nil {
+				t.Errorf("didn't expect err but got: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, test.entries) {
+				t.Errorf("got entries %v, expected %v", got, test.entries)
+			}
+		})
+	}
+}
+
+func TestStripTags(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want string
+	}{
+		"plain text":   {in: "a gift", want: "a gift"},
+		"nested tags":  {in: `<span><a href="/wiki/x">linked</a> text</span>`, want: "linked text"},
+		"entities":     {in: "salt &amp; pepper", want: "salt & pepper"},
+		"whitespace":   {in: " lots\n of  <br/> space ", want: "lots of space"},
+		"only markup":  {in: "<span></span>", want: ""},
+		"unclosed tag": {in: "text <span", want: "text"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := StripTags(tt.in); got != tt.want {
+				t.Errorf("got %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// mockWiktionaryServer serves the given response bodies by word. Responses have a 200 status unless overridden in
+// statuses.
+func mockWiktionaryServer(data map[string]string, statuses map[string]int) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc(wiktionaryEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		word := strings.TrimPrefix(r.URL.Path, wiktionaryEndpoint)
+		if status, ok := statuses[word]; ok {
+			w.WriteHeader(status)
+		}
+		_, _ = fmt.Fprint(w, data[word])
+	})
+	return httptest.NewServer(mux)
+}
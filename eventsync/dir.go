@@ -0,0 +1,225 @@
+package eventsync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/caproven/termdict/vocab"
+)
+
+// eventFileExt is the extension of the files of events in a sync directory, which hold an event as JSON per line
+const eventFileExt = ".jsonl"
+
+// SyncDir syncs with the devices sharing dir, such as a folder kept in sync between machines by Syncthing or Dropbox.
+// Each device appends the events it hasn't yet shared to a file of its own, named by its device ID, and reads the
+// files of other devices from where it last left off. Files of other devices are never written.
+//
+// Events received are added to the store as an import from dir.
+func SyncDir(ctx context.Context, store Store, dir string) (Result, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return Result{}, fmt.Errorf("resolve sync directory: %w", err)
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return Result{}, fmt.Errorf("open sync directory: %w", err)
+	}
+	if !info.IsDir() {
+		return Result{}, fmt.Errorf("%s isn't a directory", dir)
+	}
+
+	device, err := store.DeviceID(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+	ownFile := device + eventFileExt
+
+	received, err := readDir(ctx, store, dir, ownFile)
+	if err != nil {
+		return Result{}, err
+	}
+	sent, err := writeDir(ctx, store, dir, ownFile)
+	if err != nil {
+		return Result{Received: received}, err
+	}
+
+	return Result{Received: received, Sent: sent}, nil
+}
+
+// dirCursor names the cursor of a file in a sync directory. The cursor of another device's file is how many bytes of
+// it have been read, and that of this device's file is the seq of the last event written to it.
+func dirCursor(dir, file string) string {
+	return "dir:" + filepath.Join(dir, file)
+}
+
+// readDir adds the events of other devices' files in dir which haven't been read before, returning how many were new
+// to the store
+func readDir(ctx context.Context, store Store, dir, ownFile string) (int, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, fmt.Errorf("list sync directory: %w", err)
+	}
+
+	var events []vocab.Event
+	offsets := make(map[string]int64)
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || !strings.HasSuffix(name, eventFileExt) || name == ownFile {
+			continue
+		}
+		cursor, err := store.SyncCursor(ctx, dirCursor(dir, name))
+		if err != nil {
+			return 0, err
+		}
+		offset, err := parseCursor(cursor)
+		if err != nil {
+			return 0, err
+		}
+		fileEvents, next, err := readEventFile(filepath.Join(dir, name), offset)
+		if err != nil {
+			return 0, err
+		}
+		events = append(events, fileEvents...)
+		offsets[name] = next
+	}
+
+	var added int
+	if len(events) > 0 {
+		if added, err = store.AddEvents(ctx, dir, events); err != nil {
+			return 0, fmt.Errorf("add events: %w", err)
+		}
+	}
+	// Cursors are only moved once the events are stored. If saving them fails, the events are read again next time
+	// and ignored, since they're already stored.
+	for name, offset := range offsets {
+		if err := store.SetSyncCursor(ctx, dirCursor(dir, name), strconv.FormatInt(offset, 10)); err != nil {
+			return 0, err
+		}
+	}
+
+	return added, nil
+}
+
+// readEventFile reads the events of a file from offset, returning the offset to read from next time. A line without a
+// newline may still be being written or synced, so it's left until it's complete.
+func readEventFile(path string, offset int64) ([]vocab.Event, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("open events: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, 0, fmt.Errorf("stat events: %w", err)
+	}
+	// A file shorter than what's been read was replaced rather than appended to, so is read again from the start
+	if info.Size() < offset {
+		offset = 0
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, 0, fmt.Errorf("seek events: %w", err)
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, 0, fmt.Errorf("read events: %w", err)
+	}
+	data = data[:bytes.LastIndexByte(data, '\n')+1]
+
+	var events []vocab.Event
+	for line := range bytes.Lines(data) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var event vocab.Event
+		if err := json.Unmarshal(line, &event); err != nil {
+			return nil, 0, fmt.Errorf("parse event in %s: %w", path, err)
+		}
+		events = append(events, event)
+	}
+
+	return events, offset + int64(len(data)), nil
+}
+
+// writeDir appends the events this device hasn't yet shared to its file in dir, returning how many there were. Events
+// received from dir are left out, since every device already has them.
+func writeDir(ctx context.Context, store Store, dir, ownFile string) (int, error) {
+	path := filepath.Join(dir, ownFile)
+	cursor, err := store.SyncCursor(ctx, dirCursor(dir, ownFile))
+	if err != nil {
+		return 0, err
+	}
+	seq, err := parseCursor(cursor)
+	if err != nil {
+		return 0, err
+	}
+	// A file that's gone missing is written again from the first event
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		seq = 0
+	}
+
+	events, last, err := store.EventsSince(ctx, seq, dir)
+	if err != nil {
+		return 0, fmt.Errorf("get events: %w", err)
+	}
+	if len(events) > 0 {
+		if err := appendEvents(path, events); err != nil {
+			return 0, err
+		}
+	}
+	if err := store.SetSyncCursor(ctx, dirCursor(dir, ownFile), strconv.FormatInt(last, 10)); err != nil {
+		return 0, err
+	}
+
+	return len(events), nil
+}
+
+// appendEvents appends events to a file as a line of JSON each. They're written at once, so other devices don't see
+// some of them without the rest.
+func appendEvents(path string, events []vocab.Event) (err error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, event := range events {
+		if err := enc.Encode(event); err != nil {
+			return fmt.Errorf("encode event: %w", err)
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("open events: %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, f.Close())
+	}()
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("write events: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("sync events: %w", err)
+	}
+	return nil
+}
+
+// parseCursor parses a cursor saved as a number, which is zero if it's never been saved
+func parseCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(cursor, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid sync cursor %q: %w", cursor, err)
+	}
+	return n, nil
+}
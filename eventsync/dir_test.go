@@ -0,0 +1,171 @@
+package eventsync_test
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/caproven/termdict/eventsync"
+	"github.com/caproven/termdict/storage/sqlite"
+	"github.com/caproven/termdict/vocab"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+// newStore creates a store in a new in-memory database, as a device of its own
+func newStore(t *testing.T) *sqlite.Store {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, db.Close()) })
+	store, err := sqlite.NewStore(t.Context(), db)
+	require.NoError(t, err)
+	return store
+}
+
+func list(t *testing.T, store *sqlite.Store) []vocab.Entry {
+	t.Helper()
+	entries, err := store.GetWordsInList(t.Context())
+	require.NoError(t, err)
+	return entries
+}
+
+func TestSyncDir(t *testing.T) {
+	t.Run("devices converge", func(t *testing.T) {
+		dir := t.TempDir()
+		laptop, phone := newStore(t), newStore(t)
+
+		_, err := laptop.AddWordsToList(t.Context(), []string{"snow", "rain"}, "en")
+		require.NoError(t, err)
+		got, err := eventsync.SyncDir(t.Context(), laptop, dir)
+		require.NoError(t, err)
+		assert.Equal(t, eventsync.Result{Sent: 2}, got)
+
+		_, err = phone.RemoveWordsFromList(t.Context(), []string{"snow"})
+		require.NoError(t, err)
+		_, err = phone.AddWordsToList(t.Context(), []string{"hail"}, "de")
+		require.NoError(t, err)
+		got, err = eventsync.SyncDir(t.Context(), phone, dir)
+		require.NoError(t, err)
+		assert.Equal(t, eventsync.Result{Received: 2, Sent: 1}, got, "events received from the directory aren't sent back")
+
+		_, err = phone.RemoveWordsFromList(t.Context(), []string{"snow"})
+		require.NoError(t, err)
+		got, err = eventsync.SyncDir(t.Context(), phone, dir)
+		require.NoError(t, err)
+		assert.Equal(t, eventsync.Result{Sent: 1}, got)
+
+		got, err = eventsync.SyncDir(t.Context(), laptop, dir)
+		require.NoError(t, err)
+		assert.Equal(t, eventsync.Result{Received: 2}, got)
+
+		want := []vocab.Entry{{Word: "hail", Language: "de"}, {Word: "rain", Language: "en"}}
+		assert.Equal(t, want, list(t, laptop))
+		assert.Equal(t, want, list(t, phone))
+	})
+
+	t.Run("only new events are read", func(t *testing.T) {
+		dir := t.TempDir()
+		laptop, phone := newStore(t), newStore(t)
+
+		_, err := laptop.AddWordsToList(t.Context(), []string{"snow"}, "en")
+		require.NoError(t, err)
+		_, err = eventsync.SyncDir(t.Context(), laptop, dir)
+		require.NoError(t, err)
+
+		got, err := eventsync.SyncDir(t.Context(), phone, dir)
+		require.NoError(t, err)
+		assert.Equal(t, eventsync.Result{Received: 1}, got)
+		got, err = eventsync.SyncDir(t.Context(), phone, dir)
+		require.NoError(t, err)
+		assert.Equal(t, eventsync.Result{}, got)
+	})
+
+	t.Run("other devices' files are never written", func(t *testing.T) {
+		dir := t.TempDir()
+		laptop, phone := newStore(t), newStore(t)
+
+		_, err := laptop.AddWordsToList(t.Context(), []string{"snow"}, "en")
+		require.NoError(t, err)
+		_, err = eventsync.SyncDir(t.Context(), laptop, dir)
+		require.NoError(t, err)
+		laptopID, err := laptop.DeviceID(t.Context())
+		require.NoError(t, err)
+		laptopFile := filepath.Join(dir, laptopID+".jsonl")
+		before, err := os.ReadFile(laptopFile)
+		require.NoError(t, err)
+
+		_, err = phone.RemoveWordsFromList(t.Context(), []string{"snow"})
+		require.NoError(t, err)
+		_, err = eventsync.SyncDir(t.Context(), phone, dir)
+		require.NoError(t, err)
+		_, err = phone.RemoveWordsFromList(t.Context(), []string{"snow"})
+		require.NoError(t, err)
+		_, err = eventsync.SyncDir(t.Context(), phone, dir)
+		require.NoError(t, err)
+
+		after, err := os.ReadFile(laptopFile)
+		require.NoError(t, err)
+		assert.Equal(t, before, after)
+		files, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, files, 2, "each device has a file of its own")
+	})
+
+	t.Run("incomplete lines are left for later", func(t *testing.T) {
+		dir := t.TempDir()
+		phone := newStore(t)
+		other := filepath.Join(dir, "laptop.jsonl")
+		complete := `{"id":"01J3XYZ1","type":"add","word":"snow","language":"en","timestamp":100}` + "\n"
+		partial := `{"id":"01J3XYZ2","type":"add","word":"ra`
+		require.NoError(t, os.WriteFile(other, []byte(complete+partial), 0o644))
+
+		got, err := eventsync.SyncDir(t.Context(), phone, dir)
+		require.NoError(t, err)
+		assert.Equal(t, 1, got.Received)
+		assert.Equal(t, []vocab.Entry{{Word: "snow", Language: "en"}}, list(t, phone))
+
+		f, err := os.OpenFile(other, os.O_WRONLY|os.O_APPEND, 0)
+		require.NoError(t, err)
+		_, err = f.WriteString(`in","language":"en","timestamp":200}` + "\n")
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		got, err = eventsync.SyncDir(t.Context(), phone, dir)
+		require.NoError(t, err)
+		assert.Equal(t, 1, got.Received)
+		assert.Equal(t, []vocab.Entry{{Word: "rain", Language: "en"}, {Word: "snow", Language: "en"}}, list(t, phone))
+	})
+
+	t.Run("missing file is written again", func(t *testing.T) {
+		dir := t.TempDir()
+		laptop := newStore(t)
+		_, err := laptop.AddWordsToList(t.Context(), []string{"snow", "rain"}, "en")
+		require.NoError(t, err)
+		_, err = eventsync.SyncDir(t.Context(), laptop, dir)
+		require.NoError(t, err)
+
+		id, err := laptop.DeviceID(t.Context())
+		require.NoError(t, err)
+		require.NoError(t, os.Remove(filepath.Join(dir, id+".jsonl")))
+
+		got, err := eventsync.SyncDir(t.Context(), laptop, dir)
+		require.NoError(t, err)
+		assert.Equal(t, eventsync.Result{Sent: 2}, got)
+	})
+
+	t.Run("malformed event", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "laptop.jsonl"), []byte("not json\n"), 0o644))
+
+		_, err := eventsync.SyncDir(t.Context(), newStore(t), dir)
+		assert.Error(t, err)
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		_, err := eventsync.SyncDir(t.Context(), newStore(t), filepath.Join(t.TempDir(), "missing"))
+		assert.Error(t, err)
+	})
+}
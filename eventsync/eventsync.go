@@ -0,0 +1,32 @@
+// Package eventsync syncs the vocab list between devices by exchanging the vocab events each has stored. Events are
+// only ever added, and stores replay them in the same order whatever order they arrive in, so devices which have
+// exchanged all their events end up with the same list.
+package eventsync
+
+import (
+	"context"
+
+	"github.com/caproven/termdict/vocab"
+)
+
+// Store holds the vocab events of this device, and how far syncs have got
+type Store interface {
+	// DeviceID returns the ID this device syncs under
+	DeviceID(ctx context.Context) (string, error)
+	// EventsSince returns the events stored after seq, leaving out those imported from exclude, along with the seq of
+	// the last event stored
+	EventsSince(ctx context.Context, seq int64, exclude string) ([]vocab.Event, int64, error)
+	// AddEvents adds events received from source, ignoring those already stored, and returns how many were added
+	AddEvents(ctx context.Context, source string, events []vocab.Event) (int, error)
+	// SyncCursor returns the cursor saved under name, or an empty string if there's none
+	SyncCursor(ctx context.Context, name string) (string, error)
+	SetSyncCursor(ctx context.Context, name, cursor string) error
+}
+
+// Result counts the events exchanged by a sync
+type Result struct {
+	// Received is how many events were read from other devices. Events the store already had aren't counted.
+	Received int
+	// Sent is how many events of this device were passed on to others
+	Sent int
+}
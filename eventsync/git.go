@@ -0,0 +1,422 @@
+package eventsync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/caproven/termdict/vocab"
+)
+
+// GitEventFile is where the event log is kept in a git repository, relative to its root
+const GitEventFile = "termdict/events.jsonl"
+
+// gitPushAttempts is how many times a sync pulls and pushes again when another device pushed first
+const gitPushAttempts = 3
+
+// SyncGit syncs through a git working copy, such as a dotfiles repository. The event log is kept in GitEventFile. Sync
+// pulls from the branch's upstream, adds the events other devices pushed, appends this device's new events and commits
+// them, then pushes. A repository without a remote is only committed to.
+//
+// Conflicts in the event log are resolved by keeping the events of both sides, since events are only ever added and
+// each has a ULID of its own. Conflicts in other files stop the merge.
+//
+// Events received are added to the store as an import from repo.
+func SyncGit(ctx context.Context, store Store, repo string) (Result, error) {
+	repo, err := filepath.Abs(repo)
+	if err != nil {
+		return Result{}, fmt.Errorf("resolve repository: %w", err)
+	}
+	g := gitRepo{dir: repo}
+	if _, err := g.run(ctx, "rev-parse", "--is-inside-work-tree"); err != nil {
+		return Result{}, fmt.Errorf("%s isn't a git working copy: %w", repo, err)
+	}
+	device, err := store.DeviceID(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var result Result
+	for attempt := 1; ; attempt++ {
+		upstream, err := g.pull(ctx)
+		if err != nil {
+			return result, err
+		}
+		received, err := receiveGit(ctx, store, g)
+		if err != nil {
+			return result, err
+		}
+		result.Received += received
+		sent, err := commitGit(ctx, store, g, device)
+		if err != nil {
+			return result, err
+		}
+		result.Sent += sent
+
+		// Every event of the log is in the store now, so a pull before pushing again only receives what's new
+		head, err := g.run(ctx, "rev-parse", "HEAD")
+		if err != nil {
+			return result, err
+		}
+		if err := store.SetSyncCursor(ctx, gitCursor(repo), head); err != nil {
+			return result, err
+		}
+
+		if upstream.remote == "" {
+			return result, nil
+		}
+		err = g.push(ctx, upstream)
+		if err == nil {
+			return result, nil
+		}
+		// Another device pushed since the pull, so its commits are merged before pushing again
+		if !errors.Is(err, errPushRejected) || attempt == gitPushAttempts {
+			return result, err
+		}
+	}
+}
+
+// gitCursor names the cursor of a repository, which is the commit it was last synced at
+func gitCursor(repo string) string {
+	return "git:" + repo
+}
+
+// gitCommitCursor names the cursor of the events committed to a repository, which is the seq of the last
+func gitCommitCursor(repo string) string {
+	return "git-commit:" + repo
+}
+
+// receiveGit adds the events of the event log which weren't in it when last synced, returning how many were new
+func receiveGit(ctx context.Context, store Store, g gitRepo) (int, error) {
+	events, err := readEventLog(filepath.Join(g.dir, GitEventFile))
+	if err != nil {
+		return 0, err
+	}
+
+	cursor, err := store.SyncCursor(ctx, gitCursor(g.dir))
+	if err != nil {
+		return 0, err
+	}
+	if cursor != "" {
+		// A commit which can't be read, such as one lost to a rewritten history, leaves every event to be added
+		if data, err := g.run(ctx, "show", cursor+":"+GitEventFile); err == nil {
+			seen, err := parseEvents([]byte(data))
+			if err != nil {
+				return 0, err
+			}
+			ids := eventIDs(seen)
+			events = slices.DeleteFunc(events, func(e vocab.Event) bool { return ids[e.ID] })
+		}
+	}
+
+	if len(events) == 0 {
+		return 0, nil
+	}
+	added, err := store.AddEvents(ctx, g.dir, events)
+	if err != nil {
+		return 0, fmt.Errorf("add events: %w", err)
+	}
+	return added, nil
+}
+
+// commitGit appends the store's events stored since the last commit to the event log and commits them, returning how
+// many there were. Events received from the repository are already in the log, so aren't appended again.
+func commitGit(ctx context.Context, store Store, g gitRepo, device string) (int, error) {
+	path := filepath.Join(g.dir, GitEventFile)
+	cursor, err := store.SyncCursor(ctx, gitCommitCursor(g.dir))
+	if err != nil {
+		return 0, err
+	}
+	seq, err := parseCursor(cursor)
+	if err != nil {
+		return 0, err
+	}
+	// A log which was deleted is given every event of this device again
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		seq = 0
+	}
+	events, last, err := store.EventsSince(ctx, seq, g.dir)
+	if err != nil {
+		return 0, fmt.Errorf("get events: %w", err)
+	}
+	if seq == 0 {
+		// Without a cursor the log is the only record of what was committed before
+		logged, err := readEventLog(path)
+		if err != nil {
+			return 0, err
+		}
+		ids := eventIDs(logged)
+		events = slices.DeleteFunc(events, func(e vocab.Event) bool { return ids[e.ID] })
+	}
+
+	if len(events) > 0 {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return 0, fmt.Errorf("create event log directory: %w", err)
+		}
+		if err := appendEvents(path, events); err != nil {
+			return 0, err
+		}
+		if _, err := g.run(ctx, "add", GitEventFile); err != nil {
+			return 0, err
+		}
+		if _, err := g.run(ctx, "commit", "--quiet", "-m", commitMessage(device, events), "--", GitEventFile); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := store.SetSyncCursor(ctx, gitCommitCursor(g.dir), strconv.FormatInt(last, 10)); err != nil {
+		return 0, err
+	}
+	return len(events), nil
+}
+
+// commitMessageWords is how many words a commit message's subject names before summarizing the rest
+const commitMessageWords = 5
+
+// commitMessage describes the events of a commit, with a subject naming the words added and removed and a body
+// listing each event
+func commitMessage(device string, events []vocab.Event) string {
+	var added, removed []string
+	var body strings.Builder
+	for _, event := range events {
+		if event.Type == vocab.EventTypeRemove {
+			removed = append(removed, event.Word)
+		} else {
+			added = append(added, event.Word)
+		}
+		fmt.Fprintf(&body, "%s %s [%s]\n", event.Type, event.Word, event.Language)
+	}
+
+	var changes []string
+	if len(added) > 0 {
+		changes = append(changes, "add "+summarizeWords(added))
+	}
+	if len(removed) > 0 {
+		changes = append(changes, "remove "+summarizeWords(removed))
+	}
+	return fmt.Sprintf("termdict: %s\n\nVocab events from device %s:\n\n%s", strings.Join(changes, "; "), device, body.String())
+}
+
+// summarizeWords lists words, naming at most commitMessageWords of them
+func summarizeWords(words []string) string {
+	if len(words) <= commitMessageWords {
+		return strings.Join(words, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(words[:commitMessageWords], ", "), len(words)-commitMessageWords)
+}
+
+// readEventLog reads every event of an event log, which has none if it doesn't exist yet
+func readEventLog(path string) ([]vocab.Event, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read event log: %w", err)
+	}
+	events, err := parseEvents(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return events, nil
+}
+
+// parseEvents parses events written as a line of JSON each
+func parseEvents(data []byte) ([]vocab.Event, error) {
+	var events []vocab.Event
+	for line := range bytes.Lines(data) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var event vocab.Event
+		if err := json.Unmarshal(line, &event); err != nil {
+			return nil, fmt.Errorf("parse event: %w", err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func eventIDs(events []vocab.Event) map[string]bool {
+	ids := make(map[string]bool, len(events))
+	for _, event := range events {
+		ids[event.ID] = true
+	}
+	return ids
+}
+
+// gitRepo runs git in a working copy
+type gitRepo struct {
+	dir string
+}
+
+// run runs a git command, returning its output without surrounding whitespace. Errors include what git printed.
+func (g gitRepo) run(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", g.dir}, args...)...)
+	// A sync shouldn't hang waiting for credentials nobody will type. Messages aren't translated, so push rejections
+	// can be recognized.
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "LC_ALL=C")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = strings.TrimSpace(stdout.String())
+		}
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, msg)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// gitUpstream is the remote branch a working copy syncs with. Its remote is empty for repositories without one.
+type gitUpstream struct {
+	remote string
+	branch string
+	// tracked is whether the local branch already tracks the remote one, rather than it being set on first push
+	tracked bool
+}
+
+// upstream returns the remote branch to sync with: the current branch's upstream if it has one, or else the branch of
+// the same name on origin, or on the only remote
+func (g gitRepo) upstream(ctx context.Context) (gitUpstream, error) {
+	branch, err := g.run(ctx, "symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return gitUpstream{}, fmt.Errorf("find current branch: %w", err)
+	}
+	if remote, err := g.run(ctx, "config", "branch."+branch+".remote"); err == nil {
+		if merge, err := g.run(ctx, "config", "branch."+branch+".merge"); err == nil {
+			return gitUpstream{remote: remote, branch: strings.TrimPrefix(merge, "refs/heads/"), tracked: true}, nil
+		}
+	}
+
+	out, err := g.run(ctx, "remote")
+	if err != nil {
+		return gitUpstream{}, err
+	}
+	remotes := strings.Fields(out)
+	switch {
+	case slices.Contains(remotes, "origin"):
+		return gitUpstream{remote: "origin", branch: branch}, nil
+	case len(remotes) == 1:
+		return gitUpstream{remote: remotes[0], branch: branch}, nil
+	case len(remotes) == 0:
+		return gitUpstream{}, nil
+	default:
+		return gitUpstream{}, fmt.Errorf("%s has no upstream to choose between remotes %s", branch, strings.Join(remotes, ", "))
+	}
+}
+
+// pull fetches the upstream branch and merges it, returning the upstream. A remote branch which doesn't exist yet is
+// created by the push.
+func (g gitRepo) pull(ctx context.Context) (gitUpstream, error) {
+	upstream, err := g.upstream(ctx)
+	if err != nil || upstream.remote == "" {
+		return upstream, err
+	}
+	if _, err := g.run(ctx, "fetch", "--quiet", upstream.remote); err != nil {
+		return upstream, err
+	}
+	ref := upstream.remote + "/" + upstream.branch
+	if _, err := g.run(ctx, "rev-parse", "--verify", "--quiet", ref); err != nil {
+		return upstream, nil
+	}
+	// A working copy without commits yet takes the remote branch. Without --force, git refuses to check it out over
+	// files of the working copy which would be lost.
+	if _, err := g.run(ctx, "rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		branch, err := g.run(ctx, "symbolic-ref", "--short", "HEAD")
+		if err != nil {
+			return upstream, fmt.Errorf("find current branch: %w", err)
+		}
+		if _, err := g.run(ctx, "checkout", "--quiet", "-B", branch, ref); err != nil {
+			return upstream, fmt.Errorf("check out %s: %w", ref, err)
+		}
+		return upstream, nil
+	}
+
+	if _, err := g.run(ctx, "merge", "--quiet", "--no-edit", ref); err != nil {
+		if err := g.resolveConflicts(ctx); err != nil {
+			return upstream, errors.Join(err, g.abortMerge(ctx))
+		}
+	}
+	return upstream, nil
+}
+
+// resolveConflicts completes a merge whose only conflicts are in the event log, keeping the events of both sides
+func (g gitRepo) resolveConflicts(ctx context.Context) error {
+	out, err := g.run(ctx, "diff", "--name-only", "--diff-filter=U")
+	if err != nil {
+		return err
+	}
+	conflicts := strings.Fields(out)
+	if len(conflicts) == 0 {
+		return errors.New("merge failed without conflicts")
+	}
+	if len(conflicts) > 1 || conflicts[0] != GitEventFile {
+		return fmt.Errorf("merge conflicts in %s must be resolved by hand", strings.Join(conflicts, ", "))
+	}
+
+	// Stage 2 is this side of the merge and stage 3 the other. Either is missing if that side deleted the log.
+	var events []vocab.Event
+	for _, stage := range []string{":2:", ":3:"} {
+		data, err := g.run(ctx, "show", stage+GitEventFile)
+		if err != nil {
+			continue
+		}
+		side, err := parseEvents([]byte(data))
+		if err != nil {
+			return fmt.Errorf("parse merged %s: %w", GitEventFile, err)
+		}
+		events = append(events, side...)
+	}
+	slices.SortFunc(events, vocab.Compare)
+	events = slices.CompactFunc(events, func(a, b vocab.Event) bool { return a.ID == b.ID })
+
+	path := filepath.Join(g.dir, GitEventFile)
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("replace event log: %w", err)
+	}
+	if err := appendEvents(path, events); err != nil {
+		return err
+	}
+	if _, err := g.run(ctx, "add", GitEventFile); err != nil {
+		return err
+	}
+	_, err = g.run(ctx, "commit", "--quiet", "--no-edit")
+	return err
+}
+
+func (g gitRepo) abortMerge(ctx context.Context) error {
+	_, err := g.run(ctx, "merge", "--abort")
+	return err
+}
+
+// errPushRejected means a push was rejected for not being a fast-forward, as the remote has commits which weren't pulled
+var errPushRejected = errors.New("push rejected")
+
+// push pushes the current branch to its upstream, making it the branch's upstream if it wasn't already
+func (g gitRepo) push(ctx context.Context, upstream gitUpstream) error {
+	args := []string{"push", "--quiet"}
+	if !upstream.tracked {
+		args = append(args, "--set-upstream")
+	}
+	_, err := g.run(ctx, append(args, upstream.remote, "HEAD:"+upstream.branch)...)
+	if err != nil && pushRaced(err.Error()) {
+		return fmt.Errorf("%w: %w", errPushRejected, err)
+	}
+	return err
+}
+
+// pushRaced reports whether a push failed because another was pushed first: either before the remote's branch was
+// fetched, which git rejects as not being a fast-forward, or while pushing, which leaves the remote unable to update
+// the branch. Other rejections by the remote, such as by a hook, fail the same way every time so aren't retried.
+func pushRaced(msg string) bool {
+	return strings.Contains(msg, "[rejected]") || strings.Contains(msg, "cannot lock ref")
+}
@@ -0,0 +1,258 @@
+package eventsync_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/caproven/termdict/eventsync"
+	"github.com/caproven/termdict/vocab"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// git runs a git command in dir, failing the test if it fails
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := exec.CommandContext(t.Context(), "git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	require.NoError(t, err, "git %s: %s", strings.Join(args, " "), out)
+	return strings.TrimSpace(string(out))
+}
+
+// newRemote creates a bare repository for working copies to sync through, isolating git from the user's config
+func newRemote(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	git(t, t.TempDir(), "init", "--quiet", "--bare", "--initial-branch=main", remote)
+	return remote
+}
+
+// clone creates a working copy of remote for a device
+func clone(t *testing.T, remote string) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "dotfiles")
+	git(t, t.TempDir(), "clone", "--quiet", remote, dir)
+	git(t, dir, "config", "user.name", "Test")
+	git(t, dir, "config", "user.email", "test@example.com")
+	return dir
+}
+
+func syncGit(t *testing.T, store eventsync.Store, repo string) eventsync.Result {
+	t.Helper()
+	result, err := eventsync.SyncGit(t.Context(), store, repo)
+	require.NoError(t, err)
+	return result
+}
+
+func TestSyncGit(t *testing.T) {
+	t.Run("devices converge", func(t *testing.T) {
+		remote := newRemote(t)
+		laptopRepo, phoneRepo := clone(t, remote), clone(t, remote)
+		laptop, phone := newStore(t), newStore(t)
+
+		_, err := laptop.AddWordsToList(t.Context(), []string{"snow", "rain"}, "en")
+		require.NoError(t, err)
+		assert.Equal(t, eventsync.Result{Sent: 2}, syncGit(t, laptop, laptopRepo))
+		assert.Equal(t, "termdict: add snow, rain", git(t, laptopRepo, "log", "-1", "--format=%s"))
+
+		assert.Equal(t, eventsync.Result{Received: 2}, syncGit(t, phone, phoneRepo))
+		_, err = phone.RemoveWordsFromList(t.Context(), []string{"snow"})
+		require.NoError(t, err)
+		assert.Equal(t, eventsync.Result{Sent: 1}, syncGit(t, phone, phoneRepo), "events received aren't committed again")
+		assert.Equal(t, "termdict: remove snow", git(t, phoneRepo, "log", "-1", "--format=%s"))
+
+		assert.Equal(t, eventsync.Result{Received: 1}, syncGit(t, laptop, laptopRepo))
+		assert.Equal(t, eventsync.Result{}, syncGit(t, laptop, laptopRepo))
+
+		want := []vocab.Entry{{Word: "rain", Language: "en"}}
+		assert.Equal(t, want, list(t, laptop))
+		assert.Equal(t, want, list(t, phone))
+		assert.Equal(t, git(t, laptopRepo, "rev-parse", "HEAD"), git(t, remote, "rev-parse", "main"), "everything is pushed")
+	})
+
+	t.Run("conflicts in the event log keep both sides", func(t *testing.T) {
+		remote := newRemote(t)
+		laptopRepo, phoneRepo := clone(t, remote), clone(t, remote)
+		laptop, phone := newStore(t), newStore(t)
+
+		_, err := laptop.AddWordsToList(t.Context(), []string{"snow"}, "en")
+		require.NoError(t, err)
+		syncGit(t, laptop, laptopRepo)
+		syncGit(t, phone, phoneRepo)
+
+		_, err = laptop.AddWordsToList(t.Context(), []string{"rain"}, "en")
+		require.NoError(t, err)
+		syncGit(t, laptop, laptopRepo)
+
+		// The phone committed an event before being able to push it, so both sides appended to the log
+		hail := `{"id":"01HQWY5CGP0000000000000000","type":"add","word":"hail","language":"en","timestamp":1709294400}`
+		f, err := os.OpenFile(filepath.Join(phoneRepo, eventsync.GitEventFile), os.O_WRONLY|os.O_APPEND, 0)
+		require.NoError(t, err)
+		_, err = f.WriteString(hail + "\n")
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		git(t, phoneRepo, "commit", "--quiet", "--all", "-m", "add hail")
+
+		assert.Equal(t, eventsync.Result{Received: 2}, syncGit(t, phone, phoneRepo))
+		assert.Equal(t, eventsync.Result{Received: 1}, syncGit(t, laptop, laptopRepo))
+
+		log, err := os.ReadFile(filepath.Join(laptopRepo, eventsync.GitEventFile))
+		require.NoError(t, err)
+		assert.NotContains(t, string(log), "<<<<<<<")
+		assert.Len(t, strings.Split(strings.TrimSpace(string(log)), "\n"), 3)
+
+		want := []vocab.Entry{{Word: "hail", Language: "en"}, {Word: "rain", Language: "en"}, {Word: "snow", Language: "en"}}
+		assert.Equal(t, want, list(t, laptop))
+		assert.Equal(t, want, list(t, phone))
+	})
+
+	t.Run("conflicts in other files stop the merge", func(t *testing.T) {
+		remote := newRemote(t)
+		laptopRepo, phoneRepo := clone(t, remote), clone(t, remote)
+		laptop, phone := newStore(t), newStore(t)
+
+		_, err := laptop.AddWordsToList(t.Context(), []string{"snow"}, "en")
+		require.NoError(t, err)
+		syncGit(t, laptop, laptopRepo)
+		syncGit(t, phone, phoneRepo)
+
+		for i, repo := range []string{laptopRepo, phoneRepo} {
+			require.NoError(t, os.WriteFile(filepath.Join(repo, ".vimrc"), []byte{byte('a' + i)}, 0o644))
+			git(t, repo, "add", ".vimrc")
+			git(t, repo, "commit", "--quiet", "-m", "vimrc")
+		}
+		git(t, laptopRepo, "push", "--quiet")
+
+		_, err = eventsync.SyncGit(t.Context(), phone, phoneRepo)
+		require.ErrorContains(t, err, ".vimrc")
+		_, err = os.Stat(filepath.Join(phoneRepo, ".git", "MERGE_HEAD"))
+		assert.ErrorIs(t, err, os.ErrNotExist, "the merge is aborted")
+	})
+
+	t.Run("files of a working copy without commits are kept", func(t *testing.T) {
+		remote := newRemote(t)
+		laptopRepo, phoneRepo := clone(t, remote), clone(t, remote)
+		laptop, phone := newStore(t), newStore(t)
+
+		require.NoError(t, os.WriteFile(filepath.Join(laptopRepo, ".vimrc"), []byte("laptop"), 0o644))
+		git(t, laptopRepo, "add", ".vimrc")
+		git(t, laptopRepo, "commit", "--quiet", "-m", "vimrc")
+		syncGit(t, laptop, laptopRepo)
+
+		vimrc := filepath.Join(phoneRepo, ".vimrc")
+		require.NoError(t, os.WriteFile(vimrc, []byte("phone"), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(phoneRepo, ".bashrc"), []byte("phone"), 0o644))
+		_, err := eventsync.SyncGit(t.Context(), phone, phoneRepo)
+		require.ErrorContains(t, err, ".vimrc")
+		data, err := os.ReadFile(vimrc)
+		require.NoError(t, err)
+		assert.Equal(t, "phone", string(data))
+
+		// Once out of the way, the remote branch is checked out alongside the other files
+		require.NoError(t, os.Remove(vimrc))
+		syncGit(t, phone, phoneRepo)
+		data, err = os.ReadFile(vimrc)
+		require.NoError(t, err)
+		assert.Equal(t, "laptop", string(data))
+		assert.FileExists(t, filepath.Join(phoneRepo, ".bashrc"))
+	})
+
+	t.Run("pushes rejected for another device's push are retried", func(t *testing.T) {
+		remote := newRemote(t)
+		laptopRepo, phoneRepo := clone(t, remote), clone(t, remote)
+		laptop, phone := newStore(t), newStore(t)
+
+		_, err := laptop.AddWordsToList(t.Context(), []string{"snow"}, "en")
+		require.NoError(t, err)
+		syncGit(t, laptop, laptopRepo)
+		syncGit(t, phone, phoneRepo)
+
+		// The laptop pushes in between the phone's pull and push, the first time the phone pushes
+		require.NoError(t, os.WriteFile(filepath.Join(laptopRepo, ".vimrc"), []byte("set number\n"), 0o644))
+		git(t, laptopRepo, "add", ".vimrc")
+		git(t, laptopRepo, "commit", "--quiet", "-m", "vimrc")
+		raced := filepath.Join(t.TempDir(), "raced")
+		hook := "#!/bin/sh\n" +
+			"[ -e '" + raced + "' ] && exit 0\n" +
+			"touch '" + raced + "'\n" +
+			"env -u GIT_DIR -u GIT_INDEX_FILE -u GIT_WORK_TREE git -C '" + laptopRepo + "' push --quiet\n"
+		require.NoError(t, os.WriteFile(filepath.Join(phoneRepo, ".git", "hooks", "pre-push"), []byte(hook), 0o755))
+
+		_, err = phone.AddWordsToList(t.Context(), []string{"rain"}, "en")
+		require.NoError(t, err)
+		assert.Equal(t, eventsync.Result{Sent: 1}, syncGit(t, phone, phoneRepo))
+		assert.Equal(t, git(t, phoneRepo, "rev-parse", "HEAD"), git(t, remote, "rev-parse", "main"), "everything is pushed")
+		assert.FileExists(t, raced)
+		assert.FileExists(t, filepath.Join(phoneRepo, ".vimrc"), "the laptop's push is merged")
+	})
+
+	t.Run("other push failures aren't retried", func(t *testing.T) {
+		remote := newRemote(t)
+		repo := clone(t, remote)
+		store := newStore(t)
+
+		// The remote declines every push, counting them
+		hook := "#!/bin/sh\necho push >> '" + filepath.Join(remote, "pushes") + "'\necho denied >&2\nexit 1\n"
+		require.NoError(t, os.WriteFile(filepath.Join(remote, "hooks", "pre-receive"), []byte(hook), 0o755))
+
+		_, err := store.AddWordsToList(t.Context(), []string{"snow"}, "en")
+		require.NoError(t, err)
+		_, err = eventsync.SyncGit(t.Context(), store, repo)
+		require.ErrorContains(t, err, "denied")
+
+		pushes, err := os.ReadFile(filepath.Join(remote, "pushes"))
+		require.NoError(t, err)
+		assert.Equal(t, "push\n", string(pushes))
+	})
+
+	t.Run("only new events are committed", func(t *testing.T) {
+		remote := newRemote(t)
+		repo := clone(t, remote)
+		store := newStore(t)
+
+		_, err := store.AddWordsToList(t.Context(), []string{"snow"}, "en")
+		require.NoError(t, err)
+		syncGit(t, store, repo)
+
+		// Events already committed aren't committed again, even if the log no longer has them
+		log := filepath.Join(repo, eventsync.GitEventFile)
+		require.NoError(t, os.WriteFile(log, []byte("\n"), 0o644))
+		git(t, repo, "commit", "--quiet", "--all", "-m", "truncate")
+		_, err = store.AddWordsToList(t.Context(), []string{"rain"}, "en")
+		require.NoError(t, err)
+		assert.Equal(t, eventsync.Result{Sent: 1}, syncGit(t, store, repo))
+
+		data, err := os.ReadFile(log)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "snow")
+		assert.Contains(t, string(data), "rain")
+	})
+
+	t.Run("repository without a remote", func(t *testing.T) {
+		newRemote(t)
+		repo := t.TempDir()
+		git(t, repo, "init", "--quiet")
+		git(t, repo, "config", "user.name", "Test")
+		git(t, repo, "config", "user.email", "test@example.com")
+		store := newStore(t)
+
+		_, err := store.AddWordsToList(t.Context(), []string{"snow"}, "en")
+		require.NoError(t, err)
+		assert.Equal(t, eventsync.Result{Sent: 1}, syncGit(t, store, repo))
+		assert.Equal(t, "termdict: add snow", git(t, repo, "log", "-1", "--format=%s"))
+	})
+
+	t.Run("not a working copy", func(t *testing.T) {
+		newRemote(t)
+		_, err := eventsync.SyncGit(t.Context(), newStore(t), t.TempDir())
+		assert.Error(t, err)
+	})
+}
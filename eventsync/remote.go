@@ -0,0 +1,175 @@
+package eventsync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnauthorized means a sync server rejected the token it was given
+var ErrUnauthorized = errors.New("sync server rejected the token")
+
+// Remote is a sync server, see NewServer
+type Remote struct {
+	// URL is the server's base URL
+	URL   string
+	Token string
+	// Client sends requests to the server. A client which gives up on requests after a minute is used if it's nil.
+	Client *http.Client
+}
+
+// defaultClient sends requests to sync servers. Requests aren't retried, since a push which timed out may yet have been
+// stored, and the next sync sends it again anyway.
+var defaultClient = &http.Client{Timeout: time.Minute}
+
+// userAgent identifies sync requests to servers
+const userAgent = "termdict-sync"
+
+// Pull adds the events stored on the remote since the last pull, other than those pushed by this device. Events received
+// are added to the store as an import from the remote's URL.
+func Pull(ctx context.Context, store Store, remote Remote) (Result, error) {
+	base, err := remote.baseURL()
+	if err != nil {
+		return Result{}, err
+	}
+	name := "remote-pull:" + base
+	cursor, err := store.SyncCursor(ctx, name)
+	if err != nil {
+		return Result{}, err
+	}
+
+	device, err := store.DeviceID(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+	endpoint := base + EventsPath
+	if cursor != "" {
+		endpoint += "?" + url.Values{"cursor": {cursor}}.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return Result{}, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set(deviceHeader, device)
+	var resp eventsResponse
+	if err := remote.do(req, &resp); err != nil {
+		return Result{}, err
+	}
+
+	var added int
+	if len(resp.Events) > 0 {
+		if added, err = store.AddEvents(ctx, base, resp.Events); err != nil {
+			return Result{}, fmt.Errorf("add events: %w", err)
+		}
+	}
+	if err := store.SetSyncCursor(ctx, name, resp.Cursor); err != nil {
+		return Result{}, err
+	}
+	return Result{Received: added}, nil
+}
+
+// Push sends the remote the events stored since the last push. Events pulled from the remote are left out, since it
+// already has them.
+func Push(ctx context.Context, store Store, remote Remote) (Result, error) {
+	base, err := remote.baseURL()
+	if err != nil {
+		return Result{}, err
+	}
+	name := "remote-push:" + base
+	cursor, err := store.SyncCursor(ctx, name)
+	if err != nil {
+		return Result{}, err
+	}
+	seq, err := parseCursor(cursor)
+	if err != nil {
+		return Result{}, err
+	}
+	events, last, err := store.EventsSince(ctx, seq, base)
+	if err != nil {
+		return Result{}, fmt.Errorf("get events: %w", err)
+	}
+
+	if len(events) > 0 {
+		device, err := store.DeviceID(ctx)
+		if err != nil {
+			return Result{}, err
+		}
+		body, err := json.Marshal(pushRequest{Events: events})
+		if err != nil {
+			return Result{}, fmt.Errorf("encode events: %w", err)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, base+EventsPath, bytes.NewReader(body))
+		if err != nil {
+			return Result{}, fmt.Errorf("create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(deviceHeader, device)
+		if err := remote.do(req, nil); err != nil {
+			return Result{}, err
+		}
+	}
+
+	if err := store.SetSyncCursor(ctx, name, strconv.FormatInt(last, 10)); err != nil {
+		return Result{}, err
+	}
+	return Result{Sent: len(events)}, nil
+}
+
+// baseURL returns the remote's URL spelled the same way however it was given, so cursors and imports are kept per
+// server rather than per spelling of its URL
+func (r Remote) baseURL() (string, error) {
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return "", fmt.Errorf("invalid sync server URL %q", r.URL)
+	}
+	u.Scheme, u.Host = strings.ToLower(u.Scheme), strings.ToLower(u.Host)
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid sync server URL %q", r.URL)
+	}
+	u.Path, u.RawPath = strings.TrimRight(u.Path, "/"), ""
+	return u.String(), nil
+}
+
+// do sends an authenticated request to the remote, decoding the response into v unless it's nil
+func (r Remote) do(req *http.Request, v any) error {
+	client := r.Client
+	if client == nil {
+		client = defaultClient
+	}
+	req.Header.Set("Authorization", "Bearer "+r.Token)
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("reach sync server: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return ErrUnauthorized
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var body errorResponse
+		if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&body); err != nil || body.Error == "" {
+			return fmt.Errorf("sync server responded %s", resp.Status)
+		}
+		return fmt.Errorf("sync server responded %s: %s", resp.Status, body.Error)
+	}
+	if v == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
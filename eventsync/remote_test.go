@@ -0,0 +1,179 @@
+package eventsync_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/caproven/termdict/eventsync"
+	"github.com/caproven/termdict/vocab"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newServer hosts a sync server accepting token, backed by a store of its own
+func newServer(t *testing.T, tokens ...string) string {
+	t.Helper()
+	handler, err := eventsync.NewServer(newStore(t), tokens)
+	require.NoError(t, err)
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func push(t *testing.T, store eventsync.Store, remote eventsync.Remote) eventsync.Result {
+	t.Helper()
+	got, err := eventsync.Push(t.Context(), store, remote)
+	require.NoError(t, err)
+	return got
+}
+
+func pull(t *testing.T, store eventsync.Store, remote eventsync.Remote) eventsync.Result {
+	t.Helper()
+	got, err := eventsync.Pull(t.Context(), store, remote)
+	require.NoError(t, err)
+	return got
+}
+
+func TestRemote(t *testing.T) {
+	t.Run("devices converge", func(t *testing.T) {
+		remote := eventsync.Remote{URL: newServer(t, "secret"), Token: "secret"}
+		laptop, phone := newStore(t), newStore(t)
+
+		_, err := laptop.AddWordsToList(t.Context(), []string{"snow", "rain"}, "en")
+		require.NoError(t, err)
+		assert.Equal(t, eventsync.Result{Sent: 2}, push(t, laptop, remote))
+		assert.Equal(t, eventsync.Result{}, pull(t, laptop, remote), "events pushed aren't pulled back")
+
+		assert.Equal(t, eventsync.Result{Received: 2}, pull(t, phone, remote))
+		_, err = phone.RemoveWordsFromList(t.Context(), []string{"snow"})
+		require.NoError(t, err)
+		_, err = phone.AddWordsToList(t.Context(), []string{"hail"}, "de")
+		require.NoError(t, err)
+		assert.Equal(t, eventsync.Result{Sent: 2}, push(t, phone, remote), "events pulled from the server aren't pushed back")
+
+		assert.Equal(t, eventsync.Result{Received: 2}, pull(t, laptop, remote))
+		assert.Equal(t, eventsync.Result{}, push(t, laptop, remote))
+
+		want := []vocab.Entry{{Word: "hail", Language: "de"}, {Word: "rain", Language: "en"}}
+		assert.Equal(t, want, list(t, laptop))
+		assert.Equal(t, want, list(t, phone))
+	})
+
+	t.Run("only new events are exchanged", func(t *testing.T) {
+		remote := eventsync.Remote{URL: newServer(t, "secret"), Token: "secret"}
+		laptop, phone := newStore(t), newStore(t)
+
+		_, err := laptop.AddWordsToList(t.Context(), []string{"snow"}, "en")
+		require.NoError(t, err)
+		assert.Equal(t, eventsync.Result{Sent: 1}, push(t, laptop, remote))
+		assert.Equal(t, eventsync.Result{Received: 1}, pull(t, phone, remote))
+		assert.Equal(t, eventsync.Result{}, pull(t, phone, remote))
+
+		_, err = laptop.AddWordsToList(t.Context(), []string{"rain"}, "en")
+		require.NoError(t, err)
+		assert.Equal(t, eventsync.Result{Sent: 1}, push(t, laptop, remote))
+		assert.Equal(t, eventsync.Result{Received: 1}, pull(t, phone, remote))
+
+		assert.Equal(t, []vocab.Entry{{Word: "rain", Language: "en"}, {Word: "snow", Language: "en"}}, list(t, phone))
+	})
+
+	t.Run("events already stored aren't counted as received", func(t *testing.T) {
+		first := eventsync.Remote{URL: newServer(t, "secret"), Token: "secret"}
+		second := eventsync.Remote{URL: newServer(t, "secret"), Token: "secret"}
+		laptop, phone := newStore(t), newStore(t)
+
+		_, err := laptop.AddWordsToList(t.Context(), []string{"snow"}, "en")
+		require.NoError(t, err)
+		push(t, laptop, first)
+		push(t, laptop, second)
+		assert.Equal(t, eventsync.Result{Received: 1}, pull(t, phone, first))
+		assert.Equal(t, eventsync.Result{}, pull(t, phone, second))
+	})
+
+	t.Run("spellings of the same URL share cursors", func(t *testing.T) {
+		url := newServer(t, "secret")
+		laptop, phone := newStore(t), newStore(t)
+
+		_, err := laptop.AddWordsToList(t.Context(), []string{"snow"}, "en")
+		require.NoError(t, err)
+		assert.Equal(t, eventsync.Result{Sent: 1}, push(t, laptop, eventsync.Remote{URL: url, Token: "secret"}))
+		assert.Equal(t, eventsync.Result{}, push(t, laptop, eventsync.Remote{URL: url + "/", Token: "secret"}))
+
+		assert.Equal(t, eventsync.Result{Received: 1}, pull(t, phone, eventsync.Remote{URL: url + "/", Token: "secret"}))
+		shouted := strings.ToUpper(strings.TrimPrefix(url, "http://"))
+		assert.Equal(t, eventsync.Result{}, pull(t, phone, eventsync.Remote{URL: "HTTP://" + shouted, Token: "secret"}))
+		assert.Equal(t, eventsync.Result{}, push(t, phone, eventsync.Remote{URL: url, Token: "secret"}), "events pulled aren't pushed back")
+	})
+
+	t.Run("any of the server's tokens is accepted", func(t *testing.T) {
+		url := newServer(t, "laptop-token", "phone-token")
+		laptop, phone := newStore(t), newStore(t)
+
+		_, err := laptop.AddWordsToList(t.Context(), []string{"snow"}, "en")
+		require.NoError(t, err)
+		push(t, laptop, eventsync.Remote{URL: url, Token: "laptop-token"})
+		assert.Equal(t, eventsync.Result{Received: 1}, pull(t, phone, eventsync.Remote{URL: url, Token: "phone-token"}))
+	})
+
+	t.Run("wrong token", func(t *testing.T) {
+		remote := eventsync.Remote{URL: newServer(t, "secret"), Token: "guess"}
+		laptop := newStore(t)
+		_, err := laptop.AddWordsToList(t.Context(), []string{"snow"}, "en")
+		require.NoError(t, err)
+
+		_, err = eventsync.Push(t.Context(), laptop, remote)
+		assert.ErrorIs(t, err, eventsync.ErrUnauthorized)
+		_, err = eventsync.Pull(t.Context(), laptop, remote)
+		assert.ErrorIs(t, err, eventsync.ErrUnauthorized)
+
+		remote.Token = "secret"
+		assert.Equal(t, eventsync.Result{Sent: 1}, push(t, laptop, remote), "a rejected push is retried")
+	})
+
+	t.Run("invalid URL", func(t *testing.T) {
+		_, err := eventsync.Pull(t.Context(), newStore(t), eventsync.Remote{URL: "example.com", Token: "secret"})
+		assert.ErrorContains(t, err, `invalid sync server URL "example.com"`)
+	})
+}
+
+func TestNewServer(t *testing.T) {
+	t.Run("no tokens", func(t *testing.T) {
+		_, err := eventsync.NewServer(newStore(t), nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("blank token", func(t *testing.T) {
+		_, err := eventsync.NewServer(newStore(t), []string{"secret", ""})
+		assert.Error(t, err)
+	})
+
+	t.Run("bad requests", func(t *testing.T) {
+		url := newServer(t, "secret")
+		tests := map[string]struct {
+			method string
+			target string
+			body   string
+			want   int
+		}{
+			"invalid cursor": {method: http.MethodGet, target: "?cursor=nope", want: http.StatusBadRequest},
+			"invalid body":   {method: http.MethodPost, body: "{", want: http.StatusBadRequest},
+			"invalid event":  {method: http.MethodPost, body: `{"events":[{"id":"1","type":"rename","word":"snow"}]}`, want: http.StatusBadRequest},
+			"unknown method": {method: http.MethodDelete, want: http.StatusMethodNotAllowed},
+			"no events":      {method: http.MethodPost, body: `{"events":[]}`, want: http.StatusNoContent},
+			"first cursor":   {method: http.MethodGet, want: http.StatusOK},
+		}
+		for name, tc := range tests {
+			t.Run(name, func(t *testing.T) {
+				req, err := http.NewRequestWithContext(t.Context(), tc.method, url+eventsync.EventsPath+tc.target, strings.NewReader(tc.body))
+				require.NoError(t, err)
+				req.Header.Set("Authorization", "Bearer secret")
+				resp, err := http.DefaultClient.Do(req)
+				require.NoError(t, err)
+				require.NoError(t, resp.Body.Close())
+				assert.Equal(t, tc.want, resp.StatusCode)
+			})
+		}
+	})
+}
@@ -0,0 +1,177 @@
+package eventsync
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/caproven/termdict/vocab"
+)
+
+// EventsPath is the path of a sync server's events, relative to its URL
+const EventsPath = "/v1/events"
+
+// deviceHeader names the device pushing events to a sync server, so the server's history shows where they came from
+const deviceHeader = "Termdict-Device"
+
+// maxPushBytes is the largest batch of events a sync server accepts
+const maxPushBytes = 32 << 20
+
+// eventsResponse is the body of a sync server's response to a pull
+type eventsResponse struct {
+	Events []vocab.Event `json:"events"`
+	// Cursor is passed to the next pull to get only the events stored since
+	Cursor string `json:"cursor"`
+}
+
+// pushRequest is the body of a push to a sync server
+type pushRequest struct {
+	Events []vocab.Event `json:"events"`
+}
+
+// NewServer creates a sync server storing the events pushed to it in store. Requests must carry one of tokens as a
+// bearer token.
+//
+// GET EventsPath returns the events stored since the cursor given by the cursor query parameter, along with the cursor
+// to pass next time. POST EventsPath adds the events of its body.
+func NewServer(store Store, tokens []string) (http.Handler, error) {
+	if len(tokens) == 0 {
+		return nil, errors.New("a sync server needs at least one token")
+	}
+	for _, token := range tokens {
+		if token == "" {
+			return nil, errors.New("sync server tokens can't be blank")
+		}
+	}
+
+	s := &server{store: store, tokens: tokens}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+EventsPath, s.pull)
+	mux.HandleFunc("POST "+EventsPath, s.push)
+	return s.authenticate(mux), nil
+}
+
+type server struct {
+	store  Store
+	tokens []string
+}
+
+// authenticate rejects requests without one of the server's tokens
+func (s *server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || !s.validToken(token) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="termdict"`)
+			writeError(w, http.StatusUnauthorized, "missing or invalid token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validToken reports whether token is one of the server's, taking as long to say so whichever it is
+func (s *server) validToken(token string) bool {
+	valid := 0
+	for _, t := range s.tokens {
+		valid |= subtle.ConstantTimeCompare([]byte(token), []byte(t))
+	}
+	return valid == 1
+}
+
+func (s *server) pull(w http.ResponseWriter, r *http.Request) {
+	seq, err := decodeCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	// Events the device pushed itself aren't sent back to it
+	events, last, err := s.store.EventsSince(r.Context(), seq, pushSource(r))
+	if err != nil {
+		slog.Error("Failed to get events", "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to get events")
+		return
+	}
+	if events == nil {
+		events = []vocab.Event{}
+	}
+	writeResponse(w, http.StatusOK, eventsResponse{Events: events, Cursor: encodeCursor(last)})
+}
+
+func (s *server) push(w http.ResponseWriter, r *http.Request) {
+	var req pushRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxPushBytes)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid events: %v", err))
+		return
+	}
+	for _, event := range req.Events {
+		if event.ID == "" || event.Word == "" || (event.Type != vocab.EventTypeAdd && event.Type != vocab.EventTypeRemove) {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid event %q", event.ID))
+			return
+		}
+	}
+
+	if _, err := s.store.AddEvents(r.Context(), pushSource(r), req.Events); err != nil {
+		slog.Error("Failed to add events", "error", err)
+		writeError(w, http.StatusInternalServerError, "failed to add events")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// pushSource returns the import source of the events pushed by the device sending r
+func pushSource(r *http.Request) string {
+	if device := r.Header.Get(deviceHeader); device != "" {
+		return "sync push from " + device
+	}
+	return "sync push"
+}
+
+// errorResponse is the body of a sync server's error responses
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeResponse(w, status, errorResponse{Error: msg})
+}
+
+func writeResponse(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		slog.Warn("Failed to write response", "error", err)
+	}
+}
+
+// cursorPrefix versions the cursors of a sync server, which clients treat as opaque
+const cursorPrefix = "v1:"
+
+func encodeCursor(seq int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.FormatInt(seq, 10)))
+}
+
+// decodeCursor returns the seq of a cursor given by encodeCursor. No cursor starts from the first event.
+func decodeCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, errors.New("invalid cursor")
+	}
+	s, ok := strings.CutPrefix(string(data), cursorPrefix)
+	if !ok {
+		return 0, errors.New("invalid cursor")
+	}
+	seq, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || seq < 0 {
+		return 0, errors.New("invalid cursor")
+	}
+	return seq, nil
+}
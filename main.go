@@ -7,9 +7,11 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/adrg/xdg"
 	"github.com/caproven/termdict/cmd"
+	"github.com/caproven/termdict/config"
 	"github.com/caproven/termdict/dictionary"
 	"github.com/caproven/termdict/storage/sqlite"
 	_ "modernc.org/sqlite"
@@ -21,13 +23,20 @@ const (
 )
 
 func main() {
+	conf, err := config.Load(config.DefaultConfigPath())
+	if err != nil {
+		fmt.Printf("Could not load config: %v\n", err)
+		os.Exit(1)
+	}
+
 	dataDir := filepath.Join(xdg.DataHome, appName)
 	if err := os.MkdirAll(dataDir, os.ModePerm); err != nil {
 		fmt.Printf("Could not create data directory: %v\n", err)
 		os.Exit(1)
 	}
 
-	db, err := sql.Open("sqlite", filepath.Join(dataDir, dbFilename))
+	// Words are defined concurrently, so writers wait for each other rather than failing when the database is locked
+	db, err := sql.Open("sqlite", "file:"+filepath.Join(dataDir, dbFilename)+"?_pragma=busy_timeout(5000)")
 	if err != nil {
 		fmt.Println("Failed to open database")
 		os.Exit(1)
@@ -42,15 +51,93 @@ func main() {
 		fmt.Println("Failed to instantiate cache")
 		os.Exit(1)
 	}
-	api := dictionary.NewDefaultWebAPI()
-	dict := dictionary.NewCachedDefiner(store, api)
+	offline := store.WithProvider(dictionary.ProviderOffline)
+	defaultProviders := []string{dictionary.DefaultProvider}
+	if conf.Provider != "" {
+		defaultProviders = cmd.ProviderNames(strings.Split(conf.Provider, ","))
+	}
+
+	ttl := conf.CacheTTLOrDefault()
+	customMode := dictionary.CustomMerge
+	if conf.CustomDefinitions != "" {
+		customMode = dictionary.CustomMode(conf.CustomDefinitions)
+	}
+	if customMode != dictionary.CustomMerge && customMode != dictionary.CustomOverride {
+		fmt.Printf("Unknown custom_definitions setting %q; use merge or override\n", conf.CustomDefinitions)
+		os.Exit(1)
+	}
+
+	userAgent := dictionary.DefaultUserAgent
+	if conf.HTTP.UserAgent != "" {
+		userAgent = conf.HTTP.UserAgent
+	}
 
 	cfg := &cmd.Config{
-		Out:   os.Stdout,
-		Vocab: store,
-		Dict:  dict,
+		Out:       os.Stdout,
+		Vocab:     store,
+		Language:  conf.Language,
+		Providers: defaultProviders,
+		Merge:     conf.Merge,
+		HTTP: dictionary.HTTPOptions{
+			Timeout:   conf.HTTP.TimeoutOrDefault(),
+			Retries:   conf.HTTP.RetriesOrDefault(),
+			UserAgent: userAgent,
+			Proxy:     conf.HTTP.Proxy,
+			CABundle:  conf.HTTP.CABundle,
+		},
+		NewDefiner: func(opts cmd.DefinerOptions) (cmd.Definer, error) {
+			client, err := dictionary.NewHTTPClient(opts.HTTP)
+			if err != nil {
+				return nil, fmt.Errorf("configure HTTP client: %w", err)
+			}
+			providers := dictionary.NewDefaultRegistry(client)
+			if err := providers.Register(dictionary.ProviderOffline, func(dictionary.Settings) (dictionary.Definer, error) {
+				return dictionary.NewOfflineDefiner(offline), nil
+			}); err != nil {
+				return nil, fmt.Errorf("register offline dictionary: %w", err)
+			}
+			links := make([]dictionary.Link, 0, len(opts.Providers))
+			for _, name := range opts.Providers {
+				d, err := providers.Provider(name, conf.Providers[name])
+				if err != nil {
+					return nil, err
+				}
+				policy := dictionary.CachePolicy{
+					TTL:         ttl,
+					NotFoundTTL: conf.NotFoundTTLOrDefault(),
+					Refresh:     opts.Refresh,
+					NoCache:     opts.NoCache,
+					// Expired entries beat no entries at all when the provider can't be reached
+					StaleIfError: true,
+					Offline:      opts.Offline,
+				}
+				// The offline dictionary is already stored locally, so it isn't cached
+				if name != dictionary.ProviderOffline {
+					d = dictionary.NewCachedDefiner(store.WithProvider(name), d).WithPolicy(policy)
+				}
+				links = append(links, dictionary.Link{Name: name, Definer: d})
+			}
+			mode := dictionary.ChainFirstSuccess
+			if opts.Merge {
+				mode = dictionary.ChainMerge
+			}
+			chain, err := dictionary.NewChain(mode, links...)
+			if err != nil {
+				return nil, err
+			}
+			// The user's definitions are added once to what the chain defines, rather than by each provider
+			return dictionary.NewCachedDefiner(store, chain).WithPolicy(dictionary.CachePolicy{NoCache: true, Custom: customMode}), nil
+		},
+		Dumps:    offline,
+		Cache:    store,
+		CacheTTL: ttl,
+		Search:   store,
+		Custom:   store,
+		Sync:     store,
+		SyncDir:  conf.SyncDir,
 	}
 	if err := cmd.NewRootCmd(cfg).Execute(); err != nil {
-		os.Exit(1)
+		_, _ = fmt.Fprintf(os.Stderr, "Error: %s\n", cmd.ErrorMessage(err))
+		os.Exit(cmd.ExitCode(err))
 	}
 }
@@ -0,0 +1,139 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationNoTxContext(upWordLanguage, downWordLanguage)
+}
+
+// upWordLanguage tags cached words and vocab with a language. Existing rows are assumed to be English. The words table
+// must be rebuilt since its unique constraint now spans the word and language.
+func upWordLanguage(ctx context.Context, db *sql.DB) error {
+	return withoutForeignKeys(ctx, db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `CREATE TABLE words_new (
+			id       INTEGER PRIMARY KEY,
+			word     TEXT    NOT NULL COLLATE nocase,
+			language TEXT    NOT NULL DEFAULT 'en',
+			UNIQUE (word, language)
+		)`); err != nil {
+			return fmt.Errorf("create words table: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO words_new (id, word) SELECT id, word FROM words`); err != nil {
+			return fmt.Errorf("copy words: %w", err)
+		}
+		if err := replaceTable(ctx, tx, "words", "words_new"); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `ALTER TABLE vocab ADD COLUMN language TEXT NOT NULL DEFAULT 'en'`); err != nil {
+			return fmt.Errorf("add vocab language: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `ALTER TABLE vocab_events ADD COLUMN language TEXT NOT NULL DEFAULT 'en'`); err != nil {
+			return fmt.Errorf("add vocab event language: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// downWordLanguage drops everything cached for languages other than English before removing the language columns.
+func downWordLanguage(ctx context.Context, db *sql.DB) error {
+	return withoutForeignKeys(ctx, db, func(tx *sql.Tx) error {
+		for _, table := range []string{"definitions", "phonetics", "related_words", "entries", "entry_sources"} {
+			query := fmt.Sprintf(`DELETE FROM %s WHERE word_id IN (SELECT id FROM words WHERE language != 'en')`, table)
+			if _, err := tx.ExecContext(ctx, query); err != nil {
+				return fmt.Errorf("delete non-english rows from %s: %w", table, err)
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, `CREATE TABLE words_new (
+			id   INTEGER PRIMARY KEY,
+			word TEXT NOT NULL UNIQUE COLLATE nocase
+		)`); err != nil {
+			return fmt.Errorf("create words table: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO words_new (id, word) SELECT id, word FROM words WHERE language = 'en'`); err != nil {
+			return fmt.Errorf("copy words: %w", err)
+		}
+		if err := replaceTable(ctx, tx, "words", "words_new"); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `ALTER TABLE vocab DROP COLUMN language`); err != nil {
+			return fmt.Errorf("drop vocab language: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `ALTER TABLE vocab_events DROP COLUMN language`); err != nil {
+			return fmt.Errorf("drop vocab event language: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// withoutForeignKeys runs fn in a transaction with foreign key enforcement disabled, so parent tables can be rebuilt
+// without cascading deletes to their children. Foreign keys are checked before the transaction commits.
+func withoutForeignKeys(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) (err error) {
+	// The pragma applies per connection and is a no-op inside a transaction
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("get connection: %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, conn.Close())
+	}()
+
+	if _, err := conn.ExecContext(ctx, `PRAGMA foreign_keys = OFF`); err != nil {
+		return fmt.Errorf("disable foreign keys: %w", err)
+	}
+	defer func() {
+		if _, fkErr := conn.ExecContext(ctx, `PRAGMA foreign_keys = ON`); fkErr != nil {
+			err = errors.Join(err, fmt.Errorf("enable foreign keys: %w", fkErr))
+		}
+	}()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, tx.Rollback())
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	var violations int
+	if err := tx.QueryRowContext(ctx, `SELECT count(*) FROM pragma_foreign_key_check`).Scan(&violations); err != nil {
+		return fmt.Errorf("check foreign keys: %w", err)
+	}
+	if violations > 0 {
+		return fmt.Errorf("found %d foreign key violations", violations)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+// replaceTable drops a table and renames another to take its place.
+func replaceTable(ctx context.Context, tx *sql.Tx, table, replacement string) error {
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DROP TABLE %s`, table)); err != nil {
+		return fmt.Errorf("drop table %s: %w", table, err)
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE %s RENAME TO %s`, replacement, table)); err != nil {
+		return fmt.Errorf("rename table %s: %w", replacement, err)
+	}
+	return nil
+}
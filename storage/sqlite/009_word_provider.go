@@ -0,0 +1,58 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationNoTxContext(upWordProvider, downWordProvider)
+}
+
+// upWordProvider namespaces cached words by the provider which defined them. Existing rows came from the Free
+// Dictionary API, the only provider before this migration.
+func upWordProvider(ctx context.Context, db *sql.DB) error {
+	return withoutForeignKeys(ctx, db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `CREATE TABLE words_new (
+			id       INTEGER PRIMARY KEY,
+			word     TEXT    NOT NULL COLLATE nocase,
+			language TEXT    NOT NULL DEFAULT 'en',
+			provider TEXT    NOT NULL DEFAULT 'freedictionary',
+			UNIQUE (word, language, provider)
+		)`); err != nil {
+			return fmt.Errorf("create words table: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO words_new (id, word, language) SELECT id, word, language FROM words`); err != nil {
+			return fmt.Errorf("copy words: %w", err)
+		}
+		return replaceTable(ctx, tx, "words", "words_new")
+	})
+}
+
+// downWordProvider drops everything cached by providers other than the Free Dictionary API.
+func downWordProvider(ctx context.Context, db *sql.DB) error {
+	return withoutForeignKeys(ctx, db, func(tx *sql.Tx) error {
+		for _, table := range []string{"definitions", "phonetics", "related_words", "entries", "entry_sources"} {
+			query := fmt.Sprintf(`DELETE FROM %s WHERE word_id IN (SELECT id FROM words WHERE provider != 'freedictionary')`, table)
+			if _, err := tx.ExecContext(ctx, query); err != nil {
+				return fmt.Errorf("delete rows of other providers from %s: %w", table, err)
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, `CREATE TABLE words_new (
+			id       INTEGER PRIMARY KEY,
+			word     TEXT    NOT NULL COLLATE nocase,
+			language TEXT    NOT NULL DEFAULT 'en',
+			UNIQUE (word, language)
+		)`); err != nil {
+			return fmt.Errorf("create words table: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO words_new (id, word, language) SELECT id, word, language FROM words WHERE provider = 'freedictionary'`); err != nil {
+			return fmt.Errorf("copy words: %w", err)
+		}
+		return replaceTable(ctx, tx, "words", "words_new")
+	})
+}
@@ -0,0 +1,99 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/caproven/termdict/vocab"
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upVocabEventClock, downVocabEventClock)
+}
+
+// upVocabEventClock records a hybrid logical clock with each vocab event, so events are replayed in the same causal
+// order on every device. Existing events are given the clock they'd be ordered by if imported, see
+// vocab.Event.OrderClock, and replayed again in that order.
+func upVocabEventClock(ctx context.Context, tx *sql.Tx) (err error) {
+	for _, stmt := range []string{
+		`ALTER TABLE vocab_events ADD COLUMN clock_wall INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE vocab_events ADD COLUMN clock_logical INTEGER NOT NULL DEFAULT 0`,
+		`CREATE INDEX IF NOT EXISTS idx_vocab_events_order ON vocab_events (clock_wall, clock_logical, id)`,
+	} {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("add vocab event clock: %w", err)
+		}
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT id, type, word, language, timestamp FROM vocab_events`)
+	if err != nil {
+		return fmt.Errorf("query vocab events: %w", err)
+	}
+	var events []vocab.Event
+	for rows.Next() {
+		var event vocab.Event
+		if err := rows.Scan(&event.ID, &event.Type, &event.Word, &event.Language, &event.Timestamp); err != nil {
+			return errors.Join(fmt.Errorf("scan vocab event: %w", err), rows.Close())
+		}
+		events = append(events, event)
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return fmt.Errorf("iterate over vocab events: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `UPDATE vocab_events SET clock_wall = ?, clock_logical = ? WHERE id = ?`)
+	if err != nil {
+		return fmt.Errorf("prepare statement: %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, stmt.Close())
+	}()
+	for i, event := range events {
+		events[i].Clock = event.OrderClock()
+		if _, err := stmt.ExecContext(ctx, events[i].Clock.Wall, events[i].Clock.Logical, event.ID); err != nil {
+			return fmt.Errorf("set clock of vocab event %q: %w", event.ID, err)
+		}
+	}
+
+	return replayVocabEvents(ctx, tx, events)
+}
+
+// replayVocabEvents rebuilds the vocab list from its events in clock order, as the list was built by timestamp before
+func replayVocabEvents(ctx context.Context, tx *sql.Tx, events []vocab.Event) error {
+	slices.SortFunc(events, vocab.Compare)
+	lastAction := make(map[string]vocab.Event)
+	for _, event := range events {
+		lastAction[strings.ToLower(event.Word)] = event
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM vocab`); err != nil {
+		return fmt.Errorf("clear vocab: %w", err)
+	}
+	for word, event := range lastAction {
+		if event.Type != vocab.EventTypeAdd {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO vocab (word, language) VALUES (?, ?)`, word, event.Language); err != nil {
+			return fmt.Errorf("insert word %q: %w", word, err)
+		}
+	}
+	return nil
+}
+
+func downVocabEventClock(ctx context.Context, tx *sql.Tx) error {
+	for _, stmt := range []string{
+		`DROP INDEX IF EXISTS idx_vocab_events_order`,
+		`ALTER TABLE vocab_events DROP COLUMN clock_logical`,
+		`ALTER TABLE vocab_events DROP COLUMN clock_wall`,
+	} {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("drop vocab event clock: %w", err)
+		}
+	}
+	return nil
+}
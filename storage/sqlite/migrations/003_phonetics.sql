@@ -0,0 +1,20 @@
+-- +goose Up
+ALTER TABLE words ADD COLUMN phonetic TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS phonetics
+(
+    id      INTEGER PRIMARY KEY,
+    word_id INTEGER NOT NULL,
+    text    TEXT    NOT NULL,
+    audio   TEXT    NOT NULL,
+    FOREIGN KEY (word_id) REFERENCES words (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_phonetics_word_id ON phonetics (word_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_phonetics_word_id;
+
+DROP TABLE IF EXISTS phonetics;
+
+ALTER TABLE words DROP COLUMN phonetic;
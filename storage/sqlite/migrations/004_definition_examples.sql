@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE definitions ADD COLUMN example TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE definitions DROP COLUMN example;
@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS related_words
+(
+    id             INTEGER PRIMARY KEY,
+    word_id        INTEGER NOT NULL,
+    -- NULL for words related to the entry as a whole rather than a single definition
+    definition_id  INTEGER,
+    part_of_speech TEXT    NOT NULL,
+    relation       TEXT    NOT NULL CHECK (relation IN ('synonym', 'antonym')),
+    related        TEXT    NOT NULL,
+    FOREIGN KEY (word_id) REFERENCES words (id) ON DELETE CASCADE,
+    FOREIGN KEY (definition_id) REFERENCES definitions (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_related_words_word_id ON related_words (word_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_related_words_word_id;
+
+DROP TABLE IF EXISTS related_words;
@@ -0,0 +1,44 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS entries
+(
+    id       INTEGER PRIMARY KEY,
+    word_id  INTEGER NOT NULL,
+    -- Order of the entry among the word's homographs, starting from 0
+    position INTEGER NOT NULL,
+    phonetic TEXT    NOT NULL DEFAULT '',
+    UNIQUE (word_id, position),
+    FOREIGN KEY (word_id) REFERENCES words (id) ON DELETE CASCADE
+);
+
+INSERT INTO entries (word_id, position, phonetic)
+SELECT id, 0, phonetic
+FROM words;
+
+ALTER TABLE words DROP COLUMN phonetic;
+
+ALTER TABLE definitions ADD COLUMN entry INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE phonetics ADD COLUMN entry INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE related_words ADD COLUMN entry INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+DELETE FROM definitions WHERE entry > 0;
+
+DELETE FROM phonetics WHERE entry > 0;
+
+DELETE FROM related_words WHERE entry > 0;
+
+ALTER TABLE related_words DROP COLUMN entry;
+
+ALTER TABLE phonetics DROP COLUMN entry;
+
+ALTER TABLE definitions DROP COLUMN entry;
+
+ALTER TABLE words ADD COLUMN phonetic TEXT NOT NULL DEFAULT '';
+
+UPDATE words
+SET phonetic = (SELECT e.phonetic FROM entries AS e WHERE e.word_id = words.id AND e.position = 0)
+WHERE EXISTS (SELECT 1 FROM entries AS e WHERE e.word_id = words.id AND e.position = 0);
+
+DROP TABLE IF EXISTS entries;
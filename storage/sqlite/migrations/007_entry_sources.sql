@@ -0,0 +1,24 @@
+-- +goose Up
+ALTER TABLE entries ADD COLUMN license_name TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE entries ADD COLUMN license_url TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS entry_sources
+(
+    id      INTEGER PRIMARY KEY,
+    word_id INTEGER NOT NULL,
+    entry   INTEGER NOT NULL,
+    url     TEXT    NOT NULL,
+    FOREIGN KEY (word_id) REFERENCES words (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_entry_sources_word_id ON entry_sources (word_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_entry_sources_word_id;
+
+DROP TABLE IF EXISTS entry_sources;
+
+ALTER TABLE entries DROP COLUMN license_url;
+
+ALTER TABLE entries DROP COLUMN license_name;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- Words cached before fetch times were recorded have an unknown fetch time of 0
+ALTER TABLE words ADD COLUMN fetched_at INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE words DROP COLUMN fetched_at;
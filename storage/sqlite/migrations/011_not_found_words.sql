@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS not_found_words
+(
+    id         INTEGER PRIMARY KEY,
+    word       TEXT    NOT NULL COLLATE nocase,
+    language   TEXT    NOT NULL,
+    provider   TEXT    NOT NULL,
+    checked_at INTEGER NOT NULL,
+    UNIQUE (word, language, provider)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS not_found_words;
@@ -0,0 +1,44 @@
+-- +goose Up
+-- Full-text index of the meanings of cached definitions, for finding words by what they mean. The index holds no
+-- copy of the text, and is kept in step with the definitions table by triggers.
+CREATE VIRTUAL TABLE IF NOT EXISTS definitions_fts USING fts5
+(
+    definition,
+    content = 'definitions',
+    content_rowid = 'id',
+    tokenize = 'porter unicode61'
+);
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS definitions_fts_insert AFTER INSERT ON definitions
+BEGIN
+    INSERT INTO definitions_fts (rowid, definition) VALUES (new.id, new.definition);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS definitions_fts_delete AFTER DELETE ON definitions
+BEGIN
+    INSERT INTO definitions_fts (definitions_fts, rowid, definition) VALUES ('delete', old.id, old.definition);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER IF NOT EXISTS definitions_fts_update AFTER UPDATE OF definition ON definitions
+BEGIN
+    INSERT INTO definitions_fts (definitions_fts, rowid, definition) VALUES ('delete', old.id, old.definition);
+    INSERT INTO definitions_fts (rowid, definition) VALUES (new.id, new.definition);
+END;
+-- +goose StatementEnd
+
+-- Index the definitions cached before the index existed
+INSERT INTO definitions_fts (definitions_fts) VALUES ('rebuild');
+
+-- +goose Down
+DROP TRIGGER IF EXISTS definitions_fts_update;
+
+DROP TRIGGER IF EXISTS definitions_fts_delete;
+
+DROP TRIGGER IF EXISTS definitions_fts_insert;
+
+DROP TABLE IF EXISTS definitions_fts;
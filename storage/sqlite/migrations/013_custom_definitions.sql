@@ -0,0 +1,17 @@
+-- +goose Up
+-- Definitions written by the user. They're shared by all providers and aren't part of the cache.
+CREATE TABLE IF NOT EXISTS custom_definitions
+(
+    id             INTEGER PRIMARY KEY,
+    word           TEXT    NOT NULL COLLATE nocase,
+    language       TEXT    NOT NULL,
+    part_of_speech TEXT    NOT NULL,
+    definition     TEXT    NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_custom_definitions_word ON custom_definitions (word, language);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_custom_definitions_word;
+
+DROP TABLE IF EXISTS custom_definitions;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- Batches of vocab events added from elsewhere, such as files given to list import. Events written locally have none.
+CREATE TABLE IF NOT EXISTS vocab_imports
+(
+    id          INTEGER PRIMARY KEY,
+    source      TEXT    NOT NULL,
+    imported_at INTEGER NOT NULL
+);
+
+ALTER TABLE vocab_events ADD COLUMN import_id INTEGER;
+
+-- +goose Down
+ALTER TABLE vocab_events DROP COLUMN import_id;
+
+DROP TABLE IF EXISTS vocab_imports;
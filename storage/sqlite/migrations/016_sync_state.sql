@@ -0,0 +1,21 @@
+-- +goose Up
+-- State kept between syncs, such as this device's ID and how far the events of other devices have been read.
+CREATE TABLE IF NOT EXISTS sync_state
+(
+    key   TEXT PRIMARY KEY,
+    value TEXT NOT NULL
+);
+
+-- The order events were stored in, so syncs can pick up where they left off. Unlike rowid, it's kept by VACUUM.
+ALTER TABLE vocab_events ADD COLUMN seq INTEGER;
+
+UPDATE vocab_events SET seq = rowid;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_vocab_events_seq ON vocab_events (seq);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_vocab_events_seq;
+
+ALTER TABLE vocab_events DROP COLUMN seq;
+
+DROP TABLE IF EXISTS sync_state;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- Digest of an entry imported from a dictionary dump, so importing the dump again skips the entries it already added.
+-- Entries saved any other way don't have one.
+ALTER TABLE entries ADD COLUMN digest TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_entries_digest ON entries (word_id, digest);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_entries_digest;
+
+ALTER TABLE entries DROP COLUMN digest;
@@ -0,0 +1,40 @@
+-- +goose Up
+-- The same word can be listed once per language. The list is replayed from its events, keeping the last event of each
+-- word and language in the order they're replayed in.
+CREATE TABLE vocab_new
+(
+    word     TEXT NOT NULL COLLATE nocase,
+    language TEXT NOT NULL DEFAULT 'en',
+    PRIMARY KEY (word, language)
+);
+
+INSERT INTO vocab_new (word, language)
+SELECT word, language
+FROM (SELECT lower(word) AS word,
+             language,
+             type,
+             row_number() OVER (PARTITION BY lower(word), language
+                 ORDER BY clock_wall DESC, clock_logical DESC, id DESC) AS n
+      FROM vocab_events)
+WHERE n = 1
+  AND type = 'add';
+
+DROP TABLE vocab;
+
+ALTER TABLE vocab_new RENAME TO vocab;
+
+-- +goose Down
+CREATE TABLE vocab_new
+(
+    word     TEXT NOT NULL PRIMARY KEY COLLATE nocase,
+    language TEXT NOT NULL DEFAULT 'en'
+);
+
+INSERT OR IGNORE INTO vocab_new (word, language)
+SELECT word, language
+FROM vocab
+ORDER BY word, language;
+
+DROP TABLE vocab;
+
+ALTER TABLE vocab_new RENAME TO vocab;
@@ -2,15 +2,22 @@ package sqlite
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
 	"embed"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/caproven/termdict/dictionary"
+	"github.com/caproven/termdict/dictionary/bundle"
+	"github.com/caproven/termdict/dictionary/dump"
 	"github.com/caproven/termdict/vocab"
 	"github.com/oklog/ulid/v2"
 	"github.com/pressly/goose/v3"
@@ -19,8 +26,18 @@ import (
 //go:embed migrations/*.sql
 var embedMigrations embed.FS
 
+// Kinds of relation stored in the related_words table
+const (
+	relationSynonym = "synonym"
+	relationAntonym = "antonym"
+)
+
 type Store struct {
 	db *sql.DB
+	// provider namespaces the cached words, since providers define words differently
+	provider string
+	// now reads the wall clock, which cached words and vocab events are timed by
+	now func() time.Time
 }
 
 // NewStore constructs a store and performs db initialization.
@@ -29,23 +46,683 @@ func NewStore(ctx context.Context, db *sql.DB) (*Store, error) {
 		return nil, fmt.Errorf("enable foreign key constrains: %w", err)
 	}
 
-	goose.SetLogger(goose.NopLogger())
-	goose.SetBaseFS(embedMigrations)
-	if err := goose.SetDialect("sqlite3"); err != nil {
-		return nil, fmt.Errorf("set db migration dialect: %w", err)
+	goose.SetLogger(goose.NopLogger())
+	goose.SetBaseFS(embedMigrations)
+	if err := goose.SetDialect("sqlite3"); err != nil {
+		return nil, fmt.Errorf("set db migration dialect: %w", err)
+	}
+	if err := goose.UpContext(ctx, db, "migrations"); err != nil {
+		return nil, fmt.Errorf("apply db migrations: %w", err)
+	}
+
+	return &Store{db: db, provider: dictionary.DefaultProvider, now: time.Now}, nil
+}
+
+// WithProvider returns a store whose cached words are kept apart from those of other providers. The vocab list is
+// shared between all providers.
+func (s *Store) WithProvider(provider string) *Store {
+	return &Store{db: s.db, provider: provider, now: s.now}
+}
+
+// LookupWord returns the cached entries for a word in a language, in the order they were saved.
+func (s *Store) LookupWord(ctx context.Context, word, lang string) ([]dictionary.Entry, error) {
+	word = strings.ToLower(word)
+	var wordID int64
+	err := s.db.QueryRowContext(ctx, `SELECT id FROM words WHERE word IS ? AND language = ? AND provider = ?`, word, lang, s.provider).Scan(&wordID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("word %q not found for language %q", word, lang)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query word %q: %w", word, err)
+	}
+
+	return s.loadEntries(ctx, wordID, word)
+}
+
+// loadEntries reads the entries of the cached word with the given id
+func (s *Store) loadEntries(ctx context.Context, wordID int64, word string) ([]dictionary.Entry, error) {
+	l := entryLoader{positions: make(map[int]int), defs: make(map[int64]defRef)}
+	if err := s.lookupDefinitions(ctx, wordID, &l); err != nil {
+		return nil, fmt.Errorf("query definitions for word %q: %w", word, err)
+	}
+	if len(l.entries) == 0 {
+		return nil, fmt.Errorf("no definitions found for word %q", word)
+	}
+	if err := s.lookupEntries(ctx, wordID, &l); err != nil {
+		return nil, fmt.Errorf("query entries for word %q: %w", word, err)
+	}
+	if err := s.lookupPhonetics(ctx, wordID, &l); err != nil {
+		return nil, fmt.Errorf("query phonetics for word %q: %w", word, err)
+	}
+	if err := s.lookupRelatedWords(ctx, wordID, &l); err != nil {
+		return nil, fmt.Errorf("query related words for word %q: %w", word, err)
+	}
+	if err := s.lookupSources(ctx, wordID, &l); err != nil {
+		return nil, fmt.Errorf("query sources for word %q: %w", word, err)
+	}
+
+	return l.entries, nil
+}
+
+// entryLoader accumulates a word's entries across the tables they're stored in
+type entryLoader struct {
+	entries []dictionary.Entry
+	// positions maps an entry's stored position to its index in entries
+	positions map[int]int
+	// defs maps a definition id to its location in entries
+	defs map[int64]defRef
+}
+
+type defRef struct {
+	entry, def int
+}
+
+func (s *Store) lookupDefinitions(ctx context.Context, wordID int64, l *entryLoader) error {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, entry, definition, part_of_speech, example FROM definitions WHERE word_id = ? ORDER BY entry, id`, wordID)
+	if err != nil {
+		return err
+	}
+	defer func(rows *sql.Rows) {
+		if err := rows.Close(); err != nil {
+			slog.Warn("Failed to close rows", "error", err)
+		}
+	}(rows)
+
+	for rows.Next() {
+		var id int64
+		var position int
+		var def dictionary.Definition
+		if err := rows.Scan(&id, &position, &def.Meaning, &def.PartOfSpeech, &def.Example); err != nil {
+			return fmt.Errorf("scan definition: %w", err)
+		}
+		i, ok := l.positions[position]
+		if !ok {
+			i = len(l.entries)
+			l.positions[position] = i
+			l.entries = append(l.entries, dictionary.Entry{})
+		}
+		l.defs[id] = defRef{entry: i, def: len(l.entries[i].Definitions)}
+		l.entries[i].Definitions = append(l.entries[i].Definitions, def)
+	}
+
+	return rows.Err()
+}
+
+func (s *Store) lookupEntries(ctx context.Context, wordID int64, l *entryLoader) error {
+	rows, err := s.db.QueryContext(ctx, `SELECT position, phonetic, license_name, license_url FROM entries WHERE word_id = ?`, wordID)
+	if err != nil {
+		return err
+	}
+	defer func(rows *sql.Rows) {
+		if err := rows.Close(); err != nil {
+			slog.Warn("Failed to close rows", "error", err)
+		}
+	}(rows)
+
+	for rows.Next() {
+		var position int
+		var phonetic string
+		var license dictionary.License
+		if err := rows.Scan(&position, &phonetic, &license.Name, &license.URL); err != nil {
+			return fmt.Errorf("scan entry: %w", err)
+		}
+		if i, ok := l.positions[position]; ok {
+			l.entries[i].Phonetic = phonetic
+			l.entries[i].License = license
+		}
+	}
+
+	return rows.Err()
+}
+
+func (s *Store) lookupPhonetics(ctx context.Context, wordID int64, l *entryLoader) error {
+	rows, err := s.db.QueryContext(ctx, `SELECT entry, text, audio FROM phonetics WHERE word_id = ? ORDER BY entry, id`, wordID)
+	if err != nil {
+		return err
+	}
+	defer func(rows *sql.Rows) {
+		if err := rows.Close(); err != nil {
+			slog.Warn("Failed to close rows", "error", err)
+		}
+	}(rows)
+
+	for rows.Next() {
+		var position int
+		var p dictionary.Phonetic
+		if err := rows.Scan(&position, &p.Text, &p.Audio); err != nil {
+			return fmt.Errorf("scan phonetic: %w", err)
+		}
+		if i, ok := l.positions[position]; ok {
+			l.entries[i].Phonetics = append(l.entries[i].Phonetics, p)
+		}
+	}
+
+	return rows.Err()
+}
+
+// lookupRelatedWords attaches a word's synonyms and antonyms to its entries and their definitions.
+func (s *Store) lookupRelatedWords(ctx context.Context, wordID int64, l *entryLoader) error {
+	rows, err := s.db.QueryContext(ctx, `SELECT entry, definition_id, part_of_speech, relation, related FROM related_words WHERE word_id = ? ORDER BY entry, id`, wordID)
+	if err != nil {
+		return err
+	}
+	defer func(rows *sql.Rows) {
+		if err := rows.Close(); err != nil {
+			slog.Warn("Failed to close rows", "error", err)
+		}
+	}(rows)
+
+	type relationsKey struct {
+		entry int
+		pos   string
+	}
+	relationsIndex := make(map[relationsKey]int)
+
+	for rows.Next() {
+		var position int
+		var defID sql.NullInt64
+		var pos, relation, related string
+		if err := rows.Scan(&position, &defID, &pos, &relation, &related); err != nil {
+			return fmt.Errorf("scan related word: %w", err)
+		}
+
+		var synonyms, antonyms *[]string
+		if defID.Valid {
+			ref, ok := l.defs[defID.Int64]
+			if !ok {
+				continue
+			}
+			def := &l.entries[ref.entry].Definitions[ref.def]
+			synonyms, antonyms = &def.Synonyms, &def.Antonyms
+		} else {
+			ei, ok := l.positions[position]
+			if !ok {
+				continue
+			}
+			entry := &l.entries[ei]
+			key := relationsKey{entry: ei, pos: pos}
+			i, ok := relationsIndex[key]
+			if !ok {
+				i = len(entry.Relations)
+				relationsIndex[key] = i
+				entry.Relations = append(entry.Relations, dictionary.Relations{PartOfSpeech: pos})
+			}
+			synonyms, antonyms = &entry.Relations[i].Synonyms, &entry.Relations[i].Antonyms
+		}
+
+		switch relation {
+		case relationSynonym:
+			*synonyms = append(*synonyms, related)
+		case relationAntonym:
+			*antonyms = append(*antonyms, related)
+		}
+	}
+
+	return rows.Err()
+}
+
+func (s *Store) lookupSources(ctx context.Context, wordID int64, l *entryLoader) error {
+	rows, err := s.db.QueryContext(ctx, `SELECT entry, url FROM entry_sources WHERE word_id = ? ORDER BY entry, id`, wordID)
+	if err != nil {
+		return err
+	}
+	defer func(rows *sql.Rows) {
+		if err := rows.Close(); err != nil {
+			slog.Warn("Failed to close rows", "error", err)
+		}
+	}(rows)
+
+	for rows.Next() {
+		var position int
+		var url string
+		if err := rows.Scan(&position, &url); err != nil {
+			return fmt.Errorf("scan source: %w", err)
+		}
+		if i, ok := l.positions[position]; ok {
+			l.entries[i].SourceURLs = append(l.entries[i].SourceURLs, url)
+		}
+	}
+
+	return rows.Err()
+}
+
+func (s *Store) ContainsWord(ctx context.Context, word, lang string) (bool, error) {
+	word = strings.ToLower(word)
+	var exists int
+	query := `SELECT EXISTS(SELECT 1 FROM words WHERE word = ? AND language = ? AND provider = ?)`
+	if err := s.db.QueryRowContext(ctx, query, word, lang, s.provider).Scan(&exists); err != nil {
+		return false, fmt.Errorf("query word %q: %w", word, err)
+	}
+
+	return exists == 1, nil
+}
+
+// FetchedAt returns when a cached word in a language was saved. The zero time is returned for words cached before
+// fetch times were recorded.
+func (s *Store) FetchedAt(ctx context.Context, word, lang string) (time.Time, error) {
+	word = strings.ToLower(word)
+	var fetchedAt int64
+	err := s.db.QueryRowContext(ctx, `SELECT fetched_at FROM words WHERE word = ? AND language = ? AND provider = ?`, word, lang, s.provider).Scan(&fetchedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, fmt.Errorf("word %q not found for language %q", word, lang)
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("query word %q: %w", word, err)
+	}
+	return unixTime(fetchedAt), nil
+}
+
+// CachedWords returns the words cached by every provider, ordered by provider, word, and language. Words of the
+// offline dictionary aren't part of the cache.
+func (s *Store) CachedWords(ctx context.Context) ([]dictionary.CachedWord, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT word, language, provider, fetched_at FROM words WHERE provider != ? ORDER BY provider, word, language`, dictionary.ProviderOffline)
+	if err != nil {
+		return nil, fmt.Errorf("query words: %w", err)
+	}
+	defer func(rows *sql.Rows) {
+		if err := rows.Close(); err != nil {
+			slog.Warn("Failed to close rows", "error", err)
+		}
+	}(rows)
+
+	var words []dictionary.CachedWord
+	for rows.Next() {
+		var w dictionary.CachedWord
+		var fetchedAt int64
+		if err := rows.Scan(&w.Word, &w.Language, &w.Provider, &fetchedAt); err != nil {
+			return nil, fmt.Errorf("scan word: %w", err)
+		}
+		w.FetchedAt = unixTime(fetchedAt)
+		words = append(words, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate words: %w", err)
+	}
+
+	return words, nil
+}
+
+// SaveNotFound records that the store's provider has no definitions for a word in a language, as of now.
+func (s *Store) SaveNotFound(ctx context.Context, word, lang string) error {
+	word = strings.ToLower(word)
+	query := `INSERT INTO not_found_words (word, language, provider, checked_at) VALUES (?, ?, ?, ?)
+ON CONFLICT (word, language, provider) DO UPDATE SET checked_at = excluded.checked_at`
+	if _, err := s.db.ExecContext(ctx, query, word, lang, s.provider, s.now().Unix()); err != nil {
+		return fmt.Errorf("insert not found word %q: %w", word, err)
+	}
+	return nil
+}
+
+// NotFoundAt returns when the store's provider was last found to have no definitions for a word in a language. The
+// zero time is returned if it never was.
+func (s *Store) NotFoundAt(ctx context.Context, word, lang string) (time.Time, error) {
+	word = strings.ToLower(word)
+	var checkedAt int64
+	err := s.db.QueryRowContext(ctx, `SELECT checked_at FROM not_found_words WHERE word = ? AND language = ? AND provider = ?`, word, lang, s.provider).Scan(&checkedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("query not found word %q: %w", word, err)
+	}
+	return unixTime(checkedAt), nil
+}
+
+// CustomDefinitions returns the user's definitions of a word in a language, in the order they were written. They're
+// shared by all providers.
+func (s *Store) CustomDefinitions(ctx context.Context, word, lang string) ([]dictionary.Definition, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT part_of_speech, definition FROM custom_definitions WHERE word = ? AND language = ? ORDER BY id`, word, lang)
+	if err != nil {
+		return nil, fmt.Errorf("query custom definitions of %q: %w", word, err)
+	}
+	defer func(rows *sql.Rows) {
+		if err := rows.Close(); err != nil {
+			slog.Warn("Failed to close rows", "error", err)
+		}
+	}(rows)
+
+	var defs []dictionary.Definition
+	for rows.Next() {
+		var def dictionary.Definition
+		if err := rows.Scan(&def.PartOfSpeech, &def.Meaning); err != nil {
+			return nil, fmt.Errorf("scan custom definition: %w", err)
+		}
+		defs = append(defs, def)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate custom definitions: %w", err)
+	}
+
+	return defs, nil
+}
+
+// SetCustomDefinitions replaces the user's definitions of a word in a language. Giving none removes them.
+func (s *Store) SetCustomDefinitions(ctx context.Context, word, lang string, defs []dictionary.Definition) (err error) {
+	word = strings.ToLower(word)
+	if len(strings.TrimSpace(word)) == 0 {
+		return errors.New("word is blank")
+	}
+	if len(strings.TrimSpace(lang)) == 0 {
+		return errors.New("language is blank")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, tx.Rollback())
+		}
+	}()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM custom_definitions WHERE word = ? AND language = ?`, word, lang); err != nil {
+		return fmt.Errorf("delete custom definitions of %q: %w", word, err)
+	}
+	for _, def := range defs {
+		if len(strings.TrimSpace(def.Meaning)) == 0 {
+			return fmt.Errorf("definition of %q is blank", word)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO custom_definitions (word, language, part_of_speech, definition) VALUES (?, ?, ?, ?)`,
+			word, lang, def.PartOfSpeech, def.Meaning); err != nil {
+			return fmt.Errorf("insert custom definition of %q: %w", word, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+// CachedEntries returns the entries cached for a word in a language by every provider, tagged with the provider
+// which defined them. Words of the offline dictionary aren't part of the cache.
+func (s *Store) CachedEntries(ctx context.Context, word, lang string) ([]dictionary.Entry, error) {
+	word = strings.ToLower(word)
+	rows, err := s.db.QueryContext(ctx, `SELECT id, provider FROM words WHERE word = ? AND language = ? AND provider != ? ORDER BY provider`, word, lang, dictionary.ProviderOffline)
+	if err != nil {
+		return nil, fmt.Errorf("query word %q: %w", word, err)
+	}
+	type cachedWord struct {
+		id       int64
+		provider string
+	}
+	var found []cachedWord
+	for rows.Next() {
+		var w cachedWord
+		if err := rows.Scan(&w.id, &w.provider); err != nil {
+			return nil, errors.Join(fmt.Errorf("scan word: %w", err), rows.Close())
+		}
+		found = append(found, w)
+	}
+	// Entries are read with further queries, so the rows are closed first
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return nil, fmt.Errorf("iterate words: %w", err)
+	}
+
+	var entries []dictionary.Entry
+	for _, w := range found {
+		wordEntries, err := s.loadEntries(ctx, w.id, word)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range wordEntries {
+			entry.Provider = w.provider
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// CacheStats returns statistics about the cache of every provider. Words of the offline dictionary aren't part of
+// the cache, though they do take up space in the database.
+func (s *Store) CacheStats(ctx context.Context) (dictionary.CacheStats, error) {
+	var stats dictionary.CacheStats
+	query := `SELECT
+	(SELECT count() FROM words WHERE provider != ?1),
+	(SELECT count() FROM definitions WHERE word_id IN (SELECT id FROM words WHERE provider != ?1)),
+	(SELECT count() FROM not_found_words),
+	(SELECT page_count * page_size FROM pragma_page_count(), pragma_page_size())`
+	if err := s.db.QueryRowContext(ctx, query, dictionary.ProviderOffline).Scan(&stats.Words, &stats.Definitions, &stats.NotFound, &stats.SizeBytes); err != nil {
+		return stats, fmt.Errorf("query cache counts: %w", err)
+	}
+
+	var err error
+	if stats.Oldest, err = s.cachedWordBy(ctx, `fetched_at`); err != nil {
+		return stats, fmt.Errorf("query oldest word: %w", err)
+	}
+	if stats.Newest, err = s.cachedWordBy(ctx, `fetched_at DESC`); err != nil {
+		return stats, fmt.Errorf("query newest word: %w", err)
+	}
+	return stats, nil
+}
+
+// cachedWordBy returns the first cached word with a known fetch time in the given order, or the zero value if there
+// are none.
+func (s *Store) cachedWordBy(ctx context.Context, order string) (dictionary.CachedWord, error) {
+	var w dictionary.CachedWord
+	var fetchedAt int64
+	query := fmt.Sprintf(`SELECT word, language, provider, fetched_at FROM words WHERE provider != ? AND fetched_at != 0 ORDER BY %s, id LIMIT 1`, order)
+	err := s.db.QueryRowContext(ctx, query, dictionary.ProviderOffline).Scan(&w.Word, &w.Language, &w.Provider, &fetchedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return w, nil
+	}
+	if err != nil {
+		return w, err
+	}
+	w.FetchedAt = unixTime(fetchedAt)
+	return w, nil
+}
+
+// RemoveCachedWords deletes the cached entries of words in a language from every provider's cache, along with any
+// record of them not being found. The number of words deleted is returned. Words of the offline dictionary aren't
+// part of the cache and are kept.
+func (s *Store) RemoveCachedWords(ctx context.Context, words []string, lang string) (_ int, err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, tx.Rollback())
+		}
+	}()
+
+	removed := 0
+	for _, word := range words {
+		word = strings.ToLower(word)
+		var count int
+		if err := tx.QueryRowContext(ctx, `SELECT count() FROM words WHERE word = ? AND language = ? AND provider != ?`, word, lang, dictionary.ProviderOffline).Scan(&count); err != nil {
+			return 0, fmt.Errorf("query word %q: %w", word, err)
+		}
+		if err := deleteWords(ctx, tx, `word = ? AND language = ? AND provider != ?`, word, lang, dictionary.ProviderOffline); err != nil {
+			return 0, fmt.Errorf("delete word %q: %w", word, err)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM not_found_words WHERE word = ? AND language = ?`, word, lang); err != nil {
+			return 0, fmt.Errorf("delete not found word %q: %w", word, err)
+		}
+		removed += count
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit tx: %w", err)
+	}
+	return removed, nil
+}
+
+// ExportCache streams the words cached by every provider to fn, along with their entries, ordered by provider, word,
+// and language. Words of the offline dictionary aren't part of the cache.
+func (s *Store) ExportCache(ctx context.Context, fn func(bundle.Word) error) error {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, word, language, provider, fetched_at FROM words WHERE provider != ? ORDER BY provider, word, language`, dictionary.ProviderOffline)
+	if err != nil {
+		return fmt.Errorf("query words: %w", err)
+	}
+	type cachedWord struct {
+		id int64
+		bundle.Word
+	}
+	var found []cachedWord
+	for rows.Next() {
+		var w cachedWord
+		var fetchedAt int64
+		if err := rows.Scan(&w.id, &w.Word.Word, &w.Language, &w.Provider, &fetchedAt); err != nil {
+			return errors.Join(fmt.Errorf("scan word: %w", err), rows.Close())
+		}
+		w.FetchedAt = unixTime(fetchedAt)
+		found = append(found, w)
+	}
+	// Entries are read with further queries, so the rows are closed first
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return fmt.Errorf("iterate words: %w", err)
+	}
+
+	for _, w := range found {
+		w.Entries, err = s.loadEntries(ctx, w.id, w.Word.Word)
+		if err != nil {
+			return err
+		}
+		if err := fn(w.Word); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ImportCache caches words read from a bundle, in a single transaction. A word already cached by the same provider is
+// replaced or kept according to the policy, and the number of words imported is returned. Imported words keep their
+// fetch times.
+func (s *Store) ImportCache(ctx context.Context, words []bundle.Word, policy bundle.ConflictPolicy) (_ int, err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, tx.Rollback())
+		}
+	}()
+
+	selectWord, err := tx.PrepareContext(ctx, `SELECT fetched_at FROM words WHERE word = ? AND language = ? AND provider = ?`)
+	if err != nil {
+		return 0, fmt.Errorf("prepare word query: %w", err)
+	}
+
+	imported := 0
+	for _, w := range words {
+		word := strings.ToLower(w.Word)
+		if w.Provider == dictionary.ProviderOffline {
+			return 0, fmt.Errorf("word %q is of the offline dictionary, which isn't part of the cache", word)
+		}
+
+		var fetchedAt int64
+		err := selectWord.QueryRowContext(ctx, word, w.Language, w.Provider).Scan(&fetchedAt)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+		case err != nil:
+			return 0, fmt.Errorf("query word %q: %w", word, err)
+		case !policy.Replaces(w.FetchedAt, unixTime(fetchedAt)):
+			continue
+		}
+
+		if err := deleteWords(ctx, tx, `word = ? AND language = ? AND provider = ?`, word, w.Language, w.Provider); err != nil {
+			return 0, fmt.Errorf("delete cached word %q: %w", word, err)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM not_found_words WHERE word = ? AND language = ? AND provider = ?`, word, w.Language, w.Provider); err != nil {
+			return 0, fmt.Errorf("delete not found word %q: %w", word, err)
+		}
+		var fetched int64
+		if !w.FetchedAt.IsZero() {
+			fetched = w.FetchedAt.Unix()
+		}
+		res, err := tx.ExecContext(ctx, `INSERT INTO words (word, language, provider, fetched_at) VALUES (?, ?, ?, ?)`, word, w.Language, w.Provider, fetched)
+		if err != nil {
+			return 0, fmt.Errorf("insert word %q: %w", word, err)
+		}
+		wordID, err := res.LastInsertId()
+		if err != nil {
+			return 0, fmt.Errorf("get last word id: %w", err)
+		}
+		for position, entry := range w.Entries {
+			if err := s.saveEntry(ctx, tx, wordID, position, entry); err != nil {
+				return 0, fmt.Errorf("save entry %d for word %q: %w", position+1, word, err)
+			}
+		}
+		imported++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return imported, nil
+}
+
+// PurgeCache deletes the cached words of every provider, along with all records of words not being found. The
+// offline dictionary is kept.
+func (s *Store) PurgeCache(ctx context.Context) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, tx.Rollback())
+		}
+	}()
+
+	if err := deleteWords(ctx, tx, `provider != ?`, dictionary.ProviderOffline); err != nil {
+		return err
 	}
-	if err := goose.UpContext(ctx, db, "migrations"); err != nil {
-		return nil, fmt.Errorf("apply db migrations: %w", err)
+	if _, err := tx.ExecContext(ctx, `DELETE FROM not_found_words`); err != nil {
+		return fmt.Errorf("delete not found words: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
 	}
+	return nil
+}
 
-	return &Store{db: db}, nil
+// Vacuum compacts the database file, returning the space freed by deleted rows to the filesystem.
+func (s *Store) Vacuum(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, `VACUUM`); err != nil {
+		return fmt.Errorf("vacuum: %w", err)
+	}
+	return nil
 }
 
-func (s *Store) LookupWord(ctx context.Context, word string) ([]dictionary.Definition, error) {
-	word = strings.ToLower(word)
-	rows, err := s.db.QueryContext(ctx, `SELECT d.definition, d.part_of_speech FROM definitions AS d INNER JOIN words AS w ON d.word_id = w.id WHERE w.word IS ?`, word)
+// Search finds cached words whose definitions match a query, best match first. Words are ranked by their best
+// matching definition, and the same sense cached by several providers is only given once. The offline dictionary is
+// searched too.
+func (s *Store) Search(ctx context.Context, q dictionary.SearchQuery) ([]dictionary.SearchResult, error) {
+	match := ftsQuery(q.Phrase)
+	if match == "" {
+		return nil, errors.New("nothing to search for")
+	}
+
+	query := `SELECT w.word, w.language, d.part_of_speech, d.definition, -bm25(definitions_fts) AS score
+FROM definitions_fts
+	JOIN definitions AS d ON d.id = definitions_fts.rowid
+	JOIN words AS w ON w.id = d.word_id
+WHERE definitions_fts MATCH ?`
+	args := []any{match}
+	if q.PartOfSpeech != "" {
+		query += ` AND d.part_of_speech = ? COLLATE nocase`
+		args = append(args, q.PartOfSpeech)
+	}
+	if q.Language != "" {
+		query += ` AND w.language = ?`
+		args = append(args, q.Language)
+	}
+	if q.ListOnly {
+		query += ` AND EXISTS (SELECT 1 FROM vocab AS v WHERE v.word = w.word AND v.language = w.language)`
+	}
+	query += ` ORDER BY score DESC, w.word, d.id`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("query definitions for word %q: %w", word, err)
+		return nil, fmt.Errorf("query definitions: %w", err)
 	}
 	defer func(rows *sql.Rows) {
 		if err := rows.Close(); err != nil {
@@ -53,44 +730,88 @@ func (s *Store) LookupWord(ctx context.Context, word string) ([]dictionary.Defin
 		}
 	}(rows)
 
-	var defs []dictionary.Definition
+	type wordKey struct {
+		word, lang string
+	}
+	type senseKey struct {
+		wordKey
+		pos, meaning string
+	}
+	var results []dictionary.SearchResult
+	found := make(map[wordKey]int)
+	seen := make(map[senseKey]bool)
 	for rows.Next() {
+		var word, lang string
 		var def dictionary.Definition
-		if err := rows.Scan(&def.Meaning, &def.PartOfSpeech); err != nil {
-			return nil, fmt.Errorf("scan definition for word %q: %w", word, err)
+		var score float64
+		if err := rows.Scan(&word, &lang, &def.PartOfSpeech, &def.Meaning, &score); err != nil {
+			return nil, fmt.Errorf("scan definition: %w", err)
 		}
-		defs = append(defs, def)
-	}
-	if rows.Err() != nil {
-		return nil, fmt.Errorf("query definitions for word %q: %w", word, rows.Err())
-	}
 
-	if len(defs) == 0 {
-		return nil, fmt.Errorf("no definitions found for word %q", word)
+		key := wordKey{word: strings.ToLower(word), lang: lang}
+		sense := senseKey{wordKey: key, pos: strings.ToLower(def.PartOfSpeech), meaning: strings.ToLower(def.Meaning)}
+		if seen[sense] {
+			continue
+		}
+		seen[sense] = true
+
+		i, ok := found[key]
+		if !ok {
+			// Rows come best match first, so words found once the limit is reached rank below all kept so far
+			if q.Limit > 0 && len(results) == q.Limit {
+				continue
+			}
+			i = len(results)
+			found[key] = i
+			results = append(results, dictionary.SearchResult{Word: key.word, Language: lang, Score: score})
+		}
+		results[i].Definitions = append(results[i].Definitions, def)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate definitions: %w", err)
 	}
 
-	return defs, nil
+	return results, nil
 }
 
-func (s *Store) ContainsWord(ctx context.Context, word string) (bool, error) {
-	word = strings.ToLower(word)
-	var exists int
-	query := `SELECT EXISTS(SELECT 1 FROM words WHERE word = ?)`
-	if err := s.db.QueryRowContext(ctx, query, word).Scan(&exists); err != nil {
-		return false, fmt.Errorf("query word %q: %w", word, err)
+// ftsQuery turns a phrase into a full-text query matching definitions containing any of its words, so that meanings
+// sharing more of them rank higher. Words are quoted, so punctuation in the phrase can't be mistaken for query syntax.
+func ftsQuery(phrase string) string {
+	words := strings.FieldsFunc(phrase, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, w := range words {
+		words[i] = `"` + w + `"`
 	}
+	return strings.Join(words, " OR ")
+}
 
-	return exists == 1, nil
+// unixTime converts a stored unix timestamp to a time, with 0 meaning an unknown time
+func unixTime(ts int64) time.Time {
+	if ts == 0 {
+		return time.Time{}
+	}
+	return time.Unix(ts, 0)
 }
 
-func (s *Store) SaveWord(ctx context.Context, word string, defs []dictionary.Definition) (err error) {
+// SaveWord caches the entries for a word in a language, replacing any cached before. Entries are kept in the order
+// given, and the word's fetch time is set to now.
+func (s *Store) SaveWord(ctx context.Context, word, lang string, entries []dictionary.Entry) (err error) {
 	word = strings.ToLower(word)
 	if len(strings.TrimSpace(word)) == 0 {
 		return errors.New("word is blank")
 	}
-	if len(defs) == 0 {
+	if len(strings.TrimSpace(lang)) == 0 {
+		return errors.New("language is blank")
+	}
+	if len(entries) == 0 {
 		return errors.New("no definitions to save")
 	}
+	for i, entry := range entries {
+		if len(entry.Definitions) == 0 {
+			return fmt.Errorf("no definitions to save for entry %d", i+1)
+		}
+	}
 
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -102,7 +823,13 @@ func (s *Store) SaveWord(ctx context.Context, word string, defs []dictionary.Def
 		}
 	}()
 
-	res, err := tx.ExecContext(ctx, `INSERT INTO words (word) VALUES (?)`, word)
+	if err := deleteWords(ctx, tx, `word = ? AND language = ? AND provider = ?`, word, lang, s.provider); err != nil {
+		return fmt.Errorf("delete cached word %q: %w", word, err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM not_found_words WHERE word = ? AND language = ? AND provider = ?`, word, lang, s.provider); err != nil {
+		return fmt.Errorf("delete not found word %q: %w", word, err)
+	}
+	res, err := tx.ExecContext(ctx, `INSERT INTO words (word, language, provider, fetched_at) VALUES (?, ?, ?, ?)`, word, lang, s.provider, s.now().Unix())
 	if err != nil {
 		return fmt.Errorf("insert word %q: %w", word, err)
 	}
@@ -111,25 +838,201 @@ func (s *Store) SaveWord(ctx context.Context, word string, defs []dictionary.Def
 		return fmt.Errorf("get last word id: %w", err)
 	}
 
-	defStatement, err := tx.PrepareContext(ctx, `INSERT INTO definitions (word_id, definition, part_of_speech) VALUES (?, ?, ?)`)
+	for position, entry := range entries {
+		if err := s.saveEntry(ctx, tx, wordID, position, entry); err != nil {
+			return fmt.Errorf("save entry %d for word %q: %w", position+1, word, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) saveEntry(ctx context.Context, tx *sql.Tx, wordID int64, position int, entry dictionary.Entry) error {
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO entries (word_id, position, phonetic, license_name, license_url) VALUES (?, ?, ?, ?, ?)`,
+		wordID, position, entry.Phonetic, entry.License.Name, entry.License.URL); err != nil {
+		return fmt.Errorf("insert entry: %w", err)
+	}
+
+	defStatement, err := tx.PrepareContext(ctx, `INSERT INTO definitions (word_id, entry, definition, part_of_speech, example) VALUES (?, ?, ?, ?, ?)`)
 	if err != nil {
 		return fmt.Errorf("prepare definition statement: %w", err)
 	}
-	for _, def := range defs {
-		if _, err := defStatement.ExecContext(ctx, wordID, def.Meaning, def.PartOfSpeech); err != nil {
-			return fmt.Errorf("insert definition for word %q: %w", word, err)
+	relatedStatement, err := tx.PrepareContext(ctx, `INSERT INTO related_words (word_id, entry, definition_id, part_of_speech, relation, related) VALUES (?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("prepare related word statement: %w", err)
+	}
+	insertRelated := func(defID sql.NullInt64, pos string, synonyms, antonyms []string) error {
+		for _, w := range synonyms {
+			if _, err := relatedStatement.ExecContext(ctx, wordID, position, defID, pos, relationSynonym, w); err != nil {
+				return err
+			}
+		}
+		for _, w := range antonyms {
+			if _, err := relatedStatement.ExecContext(ctx, wordID, position, defID, pos, relationAntonym, w); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, def := range entry.Definitions {
+		res, err := defStatement.ExecContext(ctx, wordID, position, def.Meaning, def.PartOfSpeech, def.Example)
+		if err != nil {
+			return fmt.Errorf("insert definition: %w", err)
+		}
+		defID, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("get last definition id: %w", err)
+		}
+		if err := insertRelated(sql.NullInt64{Int64: defID, Valid: true}, def.PartOfSpeech, def.Synonyms, def.Antonyms); err != nil {
+			return fmt.Errorf("insert related words: %w", err)
+		}
+	}
+	for _, r := range entry.Relations {
+		if err := insertRelated(sql.NullInt64{}, r.PartOfSpeech, r.Synonyms, r.Antonyms); err != nil {
+			return fmt.Errorf("insert related words: %w", err)
+		}
+	}
+
+	phoneticStatement, err := tx.PrepareContext(ctx, `INSERT INTO phonetics (word_id, entry, text, audio) VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("prepare phonetic statement: %w", err)
+	}
+	for _, p := range entry.Phonetics {
+		if _, err := phoneticStatement.ExecContext(ctx, wordID, position, p.Text, p.Audio); err != nil {
+			return fmt.Errorf("insert phonetic: %w", err)
+		}
+	}
+
+	sourceStatement, err := tx.PrepareContext(ctx, `INSERT INTO entry_sources (word_id, entry, url) VALUES (?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("prepare source statement: %w", err)
+	}
+	for _, url := range entry.SourceURLs {
+		if _, err := sourceStatement.ExecContext(ctx, wordID, position, url); err != nil {
+			return fmt.Errorf("insert source: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ImportRecords appends the entries of records read from a dictionary dump to the cached words, in a single
+// transaction, returning how many entries were added. Entries are added after any already saved for a word, unless the
+// same entry was imported before, so importing a dump again doesn't duplicate them. If replace is set, every word of
+// the store's provider is removed first, in the same transaction.
+func (s *Store) ImportRecords(ctx context.Context, records []dump.Record, replace bool) (_ int, err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, tx.Rollback())
+		}
+	}()
+
+	if replace {
+		if err := deleteWords(ctx, tx, `provider = ?`, s.provider); err != nil {
+			return 0, err
+		}
+	}
+
+	insertWord, err := tx.PrepareContext(ctx, `INSERT INTO words (word, language, provider, fetched_at) VALUES (?, ?, ?, ?) ON CONFLICT DO NOTHING`)
+	if err != nil {
+		return 0, fmt.Errorf("prepare word statement: %w", err)
+	}
+	selectWord, err := tx.PrepareContext(ctx, `SELECT id, (SELECT coalesce(max(position) + 1, 0) FROM entries WHERE word_id = words.id) FROM words WHERE word = ? AND language = ? AND provider = ?`)
+	if err != nil {
+		return 0, fmt.Errorf("prepare word query: %w", err)
+	}
+
+	selectDigest, err := tx.PrepareContext(ctx, `SELECT EXISTS (SELECT 1 FROM entries WHERE word_id = ? AND digest = ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("prepare digest query: %w", err)
+	}
+	updateDigest, err := tx.PrepareContext(ctx, `UPDATE entries SET digest = ? WHERE word_id = ? AND position = ?`)
+	if err != nil {
+		return 0, fmt.Errorf("prepare digest statement: %w", err)
+	}
+
+	added := 0
+	for _, record := range records {
+		word := strings.ToLower(record.Word)
+		if len(strings.TrimSpace(word)) == 0 || len(record.Entry.Definitions) == 0 {
+			continue
+		}
+		digest, err := entryDigest(record.Entry)
+		if err != nil {
+			return 0, fmt.Errorf("digest entry for word %q: %w", word, err)
+		}
+		if _, err := insertWord.ExecContext(ctx, word, record.Language, s.provider, s.now().Unix()); err != nil {
+			return 0, fmt.Errorf("insert word %q: %w", word, err)
+		}
+		var wordID int64
+		var position int
+		if err := selectWord.QueryRowContext(ctx, word, record.Language, s.provider).Scan(&wordID, &position); err != nil {
+			return 0, fmt.Errorf("query word %q: %w", word, err)
+		}
+		var imported bool
+		if err := selectDigest.QueryRowContext(ctx, wordID, digest).Scan(&imported); err != nil {
+			return 0, fmt.Errorf("query digest for word %q: %w", word, err)
 		}
+		if imported {
+			continue
+		}
+		if err := s.saveEntry(ctx, tx, wordID, position, record.Entry); err != nil {
+			return 0, fmt.Errorf("save entry %d for word %q: %w", position+1, word, err)
+		}
+		if _, err := updateDigest.ExecContext(ctx, digest, wordID, position); err != nil {
+			return 0, fmt.Errorf("set digest for word %q: %w", word, err)
+		}
+		added++
 	}
 
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("commit tx: %w", err)
+		return 0, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return added, nil
+}
+
+// entryDigest identifies an entry by its content
+func entryDigest(entry dictionary.Entry) (string, error) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
 	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// wordTables are the tables holding the parts of a cached word's entries
+var wordTables = []string{"definitions", "phonetics", "related_words", "entries", "entry_sources"}
 
+// deleteWords deletes the cached words matching the where clause along with their entries. Rows are deleted from
+// each table rather than relying on cascades, as foreign keys are only enforced on connections which enabled them.
+func deleteWords(ctx context.Context, tx *sql.Tx, where string, args ...any) error {
+	for _, table := range wordTables {
+		query := fmt.Sprintf(`DELETE FROM %s WHERE word_id IN (SELECT id FROM words WHERE %s)`, table, where)
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("delete from %s: %w", table, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM words WHERE %s`, where), args...); err != nil {
+		return fmt.Errorf("delete words: %w", err)
+	}
 	return nil
 }
 
-// AddWordsToList adds words to the list. Words that already exist are ignored, and newly inserted words are returned.
-func (s *Store) AddWordsToList(ctx context.Context, words []string) ([]string, error) {
+// AddWordsToList adds words in a language to the list. Words already in the list in that language are ignored, and
+// newly inserted words are returned. A word can be listed once in each language.
+func (s *Store) AddWordsToList(ctx context.Context, words []string, lang string) ([]string, error) {
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("begin tx: %w", err)
@@ -142,13 +1045,18 @@ func (s *Store) AddWordsToList(ctx context.Context, words []string) ([]string, e
 
 	var inserted []string
 
-	insertStatement, err := tx.PrepareContext(ctx, `INSERT INTO vocab (word) VALUES (?) ON CONFLICT DO NOTHING`)
+	clock, err := lastClock(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	insertStatement, err := tx.PrepareContext(ctx, `INSERT INTO vocab (word, language) VALUES (?, ?) ON CONFLICT DO NOTHING`)
 	if err != nil {
 		return nil, fmt.Errorf("prepare statement: %w", err)
 	}
 	for _, word := range words {
 		word = strings.ToLower(word)
-		res, err := insertStatement.ExecContext(ctx, word)
+		res, err := insertStatement.ExecContext(ctx, word, lang)
 		if err != nil {
 			return nil, fmt.Errorf("insert word %q: %w", word, err)
 		}
@@ -157,7 +1065,8 @@ func (s *Store) AddWordsToList(ctx context.Context, words []string) ([]string, e
 			return nil, fmt.Errorf("get rows affected: %w", err)
 		}
 		if affected == 1 {
-			event := newVocabEvent(vocab.EventTypeAdd, word)
+			clock = clock.Tick(s.now())
+			event := s.newVocabEvent(vocab.EventTypeAdd, word, lang, clock)
 			if err := s.appendEvent(ctx, tx, event); err != nil {
 				return nil, fmt.Errorf("write vocab event: %w", err)
 			}
@@ -173,8 +1082,8 @@ func (s *Store) AddWordsToList(ctx context.Context, words []string) ([]string, e
 	return inserted, nil
 }
 
-// RemoveWordsFromList removes words from the list. Words that don't exist are ignored, and words which are removed
-// by this operation are returned.
+// RemoveWordsFromList removes words from the list, in every language they're listed in. Words that don't exist are
+// ignored, and words which are removed by this operation are returned.
 func (s *Store) RemoveWordsFromList(ctx context.Context, words []string) ([]string, error) {
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -188,28 +1097,33 @@ func (s *Store) RemoveWordsFromList(ctx context.Context, words []string) ([]stri
 
 	var removed []string
 
-	deleteStatement, err := tx.PrepareContext(ctx, `DELETE FROM vocab WHERE word = ?`)
+	clock, err := lastClock(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	deleteStatement, err := tx.PrepareContext(ctx, `DELETE FROM vocab WHERE word = ? RETURNING language`)
 	if err != nil {
 		return nil, fmt.Errorf("prepare statement: %w", err)
 	}
 	for _, word := range words {
 		word = strings.ToLower(word)
-		res, err := deleteStatement.ExecContext(ctx, word)
+		langs, err := deletedLanguages(ctx, deleteStatement, word)
 		if err != nil {
 			return nil, fmt.Errorf("remove word %q: %w", word, err)
 		}
-		affected, err := res.RowsAffected()
-		if err != nil {
-			return nil, fmt.Errorf("get rows affected: %w", err)
+		if len(langs) == 0 {
+			continue
 		}
-		if affected == 1 {
-			event := newVocabEvent(vocab.EventTypeRemove, word)
+		// Each language the word was listed in is removed by an event of its own
+		for _, lang := range langs {
+			clock = clock.Tick(s.now())
+			event := s.newVocabEvent(vocab.EventTypeRemove, word, lang, clock)
 			if err := s.appendEvent(ctx, tx, event); err != nil {
 				return nil, fmt.Errorf("write vocab event: %w", err)
 			}
-			removed = append(removed, word)
-			continue
 		}
+		removed = append(removed, word)
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -219,8 +1133,35 @@ func (s *Store) RemoveWordsFromList(ctx context.Context, words []string) ([]stri
 	return removed, nil
 }
 
-func (s *Store) GetWordsInList(ctx context.Context) ([]string, error) {
-	rows, err := s.db.QueryContext(ctx, `SELECT word FROM vocab ORDER BY word`)
+// deletedLanguages runs a statement deleting a word from the list, returning the languages it was deleted from
+func deletedLanguages(ctx context.Context, stmt *sql.Stmt, word string) ([]string, error) {
+	rows, err := stmt.QueryContext(ctx, word)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Warn("Failed to close rows", "error", err)
+		}
+	}()
+
+	var langs []string
+	for rows.Next() {
+		var lang string
+		if err := rows.Scan(&lang); err != nil {
+			return nil, fmt.Errorf("scan language: %w", err)
+		}
+		langs = append(langs, lang)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	slices.Sort(langs)
+	return langs, nil
+}
+
+func (s *Store) GetWordsInList(ctx context.Context) ([]vocab.Entry, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT word, language FROM vocab ORDER BY word, language`)
 	if err != nil {
 		return nil, fmt.Errorf("query words in list: %w", err)
 	}
@@ -230,34 +1171,54 @@ func (s *Store) GetWordsInList(ctx context.Context) ([]string, error) {
 		}
 	}(rows)
 
-	var words []string
+	var entries []vocab.Entry
 	for rows.Next() {
-		var word string
-		if err := rows.Scan(&word); err != nil {
+		var entry vocab.Entry
+		if err := rows.Scan(&entry.Word, &entry.Language); err != nil {
 			return nil, fmt.Errorf("scan word in list: %w", err)
 		}
-		words = append(words, word)
+		entries = append(entries, entry)
 	}
 
-	return words, nil
+	return entries, nil
 }
 
 func (s *Store) appendEvent(ctx context.Context, tx *sql.Tx, event vocab.Event) error {
+	clock := event.OrderClock()
 	_, err := tx.ExecContext(ctx,
-		`INSERT INTO vocab_events (id, type, word, timestamp) VALUES (?, ?, ?, ?)`,
-		event.ID, string(event.Type), event.Word, event.Timestamp)
+		`INSERT INTO vocab_events (id, type, word, language, timestamp, clock_wall, clock_logical, seq)
+		VALUES (?, ?, ?, ?, ?, ?, ?, `+nextEventSeq+`)`,
+		event.ID, string(event.Type), event.Word, eventLanguage(event), event.Timestamp, clock.Wall, clock.Logical)
 	if err != nil {
 		return fmt.Errorf("append event %q: %w", event.ID, err)
 	}
 	return nil
 }
 
+// nextEventSeq is the seq of the next event stored, which orders events by when they were stored
+const nextEventSeq = `(SELECT coalesce(max(seq), 0) + 1 FROM vocab_events)`
+
+// lastClock returns the clock of the latest vocab event, which new events must come after. Taking it from every
+// stored event, imported ones included, orders new events after all those seen from other devices.
+func lastClock(ctx context.Context, tx *sql.Tx) (vocab.Clock, error) {
+	var clock vocab.Clock
+	err := tx.QueryRowContext(ctx,
+		`SELECT clock_wall, clock_logical FROM vocab_events ORDER BY clock_wall DESC, clock_logical DESC LIMIT 1`).
+		Scan(&clock.Wall, &clock.Logical)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return vocab.Clock{}, fmt.Errorf("query last vocab event clock: %w", err)
+	}
+	return clock, nil
+}
+
 func (s *Store) rebuildVocab(ctx context.Context, tx *sql.Tx) error {
 	if _, err := tx.ExecContext(ctx, `DELETE FROM vocab`); err != nil {
 		return fmt.Errorf("clear vocab: %w", err)
 	}
 
-	rows, err := tx.QueryContext(ctx, `SELECT type, word FROM vocab_events ORDER BY timestamp`)
+	// Replaying in the same total order on every device makes the list the same wherever the same events are known
+	rows, err := tx.QueryContext(ctx,
+		`SELECT type, word, language FROM vocab_events ORDER BY clock_wall, clock_logical, id`)
 	if err != nil {
 		return fmt.Errorf("query vocab events: %w", err)
 	}
@@ -267,27 +1228,27 @@ func (s *Store) rebuildVocab(ctx context.Context, tx *sql.Tx) error {
 		}
 	}()
 
-	lastAction := make(map[string]vocab.EventType)
+	lastAction := make(map[vocab.Entry]vocab.Event)
 	for rows.Next() {
-		var eventType string
-		var word string
-		if err := rows.Scan(&eventType, &word); err != nil {
+		var event vocab.Event
+		if err := rows.Scan(&event.Type, &event.Word, &event.Language); err != nil {
 			return fmt.Errorf("scan vocab event: %w", err)
 		}
-		lastAction[word] = vocab.EventType(eventType)
+		// Words are matched regardless of case, as they are in the list, and listed once per language
+		lastAction[vocab.Entry{Word: strings.ToLower(event.Word), Language: event.Language}] = event
 	}
 	if err := rows.Err(); err != nil {
 		return fmt.Errorf("iterate over vocab events: %w", err)
 	}
 
-	stmt, err := tx.PrepareContext(ctx, `INSERT INTO vocab (word) VALUES (?)`)
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO vocab (word, language) VALUES (?, ?)`)
 	if err != nil {
 		return fmt.Errorf("prepare vocab insert: %w", err)
 	}
-	for word, action := range lastAction {
-		if action == vocab.EventTypeAdd {
-			if _, err := stmt.ExecContext(ctx, word); err != nil {
-				return fmt.Errorf("insert word %q: %w", word, err)
+	for entry, event := range lastAction {
+		if event.Type == vocab.EventTypeAdd {
+			if _, err := stmt.ExecContext(ctx, entry.Word, entry.Language); err != nil {
+				return fmt.Errorf("insert word %q: %w", entry.Word, err)
 			}
 		}
 	}
@@ -295,10 +1256,11 @@ func (s *Store) rebuildVocab(ctx context.Context, tx *sql.Tx) error {
 	return nil
 }
 
-// GetEvents returns all vocab events ordered by timestamp.
+// GetEvents returns all vocab events in the order they're replayed, see vocab.Compare.
 func (s *Store) GetEvents(ctx context.Context) ([]vocab.Event, error) {
 	rows, err := s.db.QueryContext(ctx,
-		`SELECT id, type, word, timestamp FROM vocab_events ORDER BY timestamp`)
+		`SELECT id, type, word, language, timestamp, clock_wall, clock_logical FROM vocab_events
+		ORDER BY clock_wall, clock_logical, id`)
 	if err != nil {
 		return nil, fmt.Errorf("query vocab events: %w", err)
 	}
@@ -311,7 +1273,8 @@ func (s *Store) GetEvents(ctx context.Context) ([]vocab.Event, error) {
 	var events []vocab.Event
 	for rows.Next() {
 		var event vocab.Event
-		if err := rows.Scan(&event.ID, &event.Type, &event.Word, &event.Timestamp); err != nil {
+		if err := rows.Scan(&event.ID, &event.Type, &event.Word, &event.Language, &event.Timestamp,
+			&event.Clock.Wall, &event.Clock.Logical); err != nil {
 			return nil, fmt.Errorf("scan vocab event: %w", err)
 		}
 		events = append(events, event)
@@ -323,11 +1286,14 @@ func (s *Store) GetEvents(ctx context.Context) ([]vocab.Event, error) {
 	return events, nil
 }
 
-// AddEvents inserts events into the store and rebuilds the materialized vocab view.
-func (s *Store) AddEvents(ctx context.Context, events []vocab.Event) error {
+// AddEvents inserts events from source into the store and rebuilds the materialized vocab view. Events are replayed in
+// the order of vocab.Compare, so stores given the same events end up with the same list whatever order they're added
+// in. New events are recorded as an import from source, which GetHistory shows; events already stored are ignored.
+// The number of events added is returned.
+func (s *Store) AddEvents(ctx context.Context, source string, events []vocab.Event) (_ int, err error) {
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("begin tx: %w", err)
+		return 0, fmt.Errorf("begin tx: %w", err)
 	}
 	defer func() {
 		if err != nil {
@@ -335,35 +1301,220 @@ func (s *Store) AddEvents(ctx context.Context, events []vocab.Event) error {
 		}
 	}()
 
+	res, err := tx.ExecContext(ctx, `INSERT INTO vocab_imports (source, imported_at) VALUES (?, ?)`, source, s.now().Unix())
+	if err != nil {
+		return 0, fmt.Errorf("insert vocab import: %w", err)
+	}
+	importID, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("get vocab import id: %w", err)
+	}
+
 	stmt, err := tx.PrepareContext(ctx,
-		`INSERT OR IGNORE INTO vocab_events (id, type, word, timestamp) VALUES (?, ?, ?, ?)`)
+		`INSERT OR IGNORE INTO vocab_events (id, type, word, language, timestamp, clock_wall, clock_logical, import_id, seq)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, `+nextEventSeq+`)`)
 	if err != nil {
-		return fmt.Errorf("prepare statement: %w", err)
+		return 0, fmt.Errorf("prepare statement: %w", err)
 	}
 
+	var added int64
 	for _, event := range events {
-		if _, err := stmt.ExecContext(ctx, event.ID, string(event.Type), event.Word, event.Timestamp); err != nil {
-			return fmt.Errorf("insert vocab event %q: %w", event.ID, err)
+		clock := event.OrderClock()
+		res, err := stmt.ExecContext(ctx, event.ID, string(event.Type), event.Word, eventLanguage(event), event.Timestamp,
+			clock.Wall, clock.Logical, importID)
+		if err != nil {
+			return 0, fmt.Errorf("insert vocab event %q: %w", event.ID, err)
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("get rows affected: %w", err)
+		}
+		added += affected
+	}
+	// Imports without new events aren't part of the history
+	if added == 0 {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM vocab_imports WHERE id = ?`, importID); err != nil {
+			return 0, fmt.Errorf("delete vocab import: %w", err)
 		}
 	}
 
 	if err := s.rebuildVocab(ctx, tx); err != nil {
-		return fmt.Errorf("rebuild vocab: %w", err)
+		return 0, fmt.Errorf("rebuild vocab: %w", err)
 	}
 
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("commit tx: %w", err)
+		return 0, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return int(added), nil
+}
+
+// GetHistory returns the vocab events matching filter in the order they're replayed, along with the imports they came
+// from.
+func (s *Store) GetHistory(ctx context.Context, filter vocab.HistoryFilter) ([]vocab.HistoryEntry, error) {
+	var where []string
+	var args []any
+	if filter.Word != "" {
+		where = append(where, `e.word = ?`)
+		args = append(args, filter.Word)
+	}
+	if !filter.Since.IsZero() {
+		where = append(where, `e.timestamp >= ?`)
+		args = append(args, filter.Since.Unix())
+	}
+	if !filter.Until.IsZero() {
+		where = append(where, `e.timestamp < ?`)
+		args = append(args, filter.Until.Unix())
+	}
+	if filter.Type != "" {
+		where = append(where, `e.type = ?`)
+		args = append(args, string(filter.Type))
+	}
+	query := `SELECT e.id, e.type, e.word, e.language, e.timestamp, e.clock_wall, e.clock_logical, i.source, i.imported_at
+		FROM vocab_events e LEFT JOIN vocab_imports i ON i.id = e.import_id`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, ` AND `)
+	}
+	query += ` ORDER BY e.clock_wall, e.clock_logical, e.id`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query vocab history: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Warn("Failed to close rows", "error", err)
+		}
+	}()
+
+	var history []vocab.HistoryEntry
+	for rows.Next() {
+		var entry vocab.HistoryEntry
+		var source sql.NullString
+		var importedAt sql.NullInt64
+		if err := rows.Scan(&entry.ID, &entry.Type, &entry.Word, &entry.Language, &entry.Timestamp,
+			&entry.Clock.Wall, &entry.Clock.Logical, &source, &importedAt); err != nil {
+			return nil, fmt.Errorf("scan vocab event: %w", err)
+		}
+		if source.Valid {
+			entry.Import = &vocab.Import{Source: source.String, At: unixTime(importedAt.Int64)}
+		}
+		history = append(history, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iter vocab history: %w", err)
+	}
+
+	return history, nil
+}
+
+// EventsSince returns the events stored after seq, in the order they were stored, along with the seq of the last event
+// stored. Events imported from exclude are left out, so those received from a sync aren't sent back to it.
+func (s *Store) EventsSince(ctx context.Context, seq int64, exclude string) ([]vocab.Event, int64, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			slog.Warn("Failed to end read transaction", "error", err)
+		}
+	}()
+
+	var last int64
+	if err := tx.QueryRowContext(ctx, `SELECT coalesce(max(seq), 0) FROM vocab_events`).Scan(&last); err != nil {
+		return nil, 0, fmt.Errorf("query last vocab event: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx,
+		`SELECT e.id, e.type, e.word, e.language, e.timestamp, e.clock_wall, e.clock_logical
+		FROM vocab_events e LEFT JOIN vocab_imports i ON i.id = e.import_id
+		WHERE e.seq > ? AND (i.source IS NULL OR i.source != ?)
+		ORDER BY e.seq`, seq, exclude)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query vocab events: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Warn("Failed to close rows", "error", err)
+		}
+	}()
+
+	var events []vocab.Event
+	for rows.Next() {
+		var event vocab.Event
+		if err := rows.Scan(&event.ID, &event.Type, &event.Word, &event.Language, &event.Timestamp,
+			&event.Clock.Wall, &event.Clock.Logical); err != nil {
+			return nil, 0, fmt.Errorf("scan vocab event: %w", err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iter vocab events: %w", err)
 	}
 
+	return events, max(last, seq), nil
+}
+
+// syncStateDeviceID is the sync_state key of this device's ID. Cursors are kept apart from it by
+// syncStateCursorPrefix.
+const (
+	syncStateDeviceID     = "device_id"
+	syncStateCursorPrefix = "cursor:"
+)
+
+// DeviceID returns the ID this device syncs under, creating it on first use.
+func (s *Store) DeviceID(ctx context.Context) (string, error) {
+	// Concurrent first uses agree on whichever ID is stored first
+	if _, err := s.db.ExecContext(ctx, `INSERT INTO sync_state (key, value) VALUES (?, ?) ON CONFLICT DO NOTHING`,
+		syncStateDeviceID, ulid.Make().String()); err != nil {
+		return "", fmt.Errorf("create device id: %w", err)
+	}
+	return s.syncState(ctx, syncStateDeviceID)
+}
+
+// SyncCursor returns the cursor saved under name by SetSyncCursor, or an empty string if there's none.
+func (s *Store) SyncCursor(ctx context.Context, name string) (string, error) {
+	return s.syncState(ctx, syncStateCursorPrefix+name)
+}
+
+// SetSyncCursor saves how far a sync has got, under a name of the sync's choosing.
+func (s *Store) SetSyncCursor(ctx context.Context, name, cursor string) error {
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO sync_state (key, value) VALUES (?, ?) ON CONFLICT (key) DO UPDATE SET value = excluded.value`,
+		syncStateCursorPrefix+name, cursor); err != nil {
+		return fmt.Errorf("save sync cursor %q: %w", name, err)
+	}
 	return nil
 }
 
-func newVocabEvent(eventType vocab.EventType, word string) vocab.Event {
-	id := ulid.Make()
+// syncState returns the value of a sync_state key, or an empty string if it's unset
+func (s *Store) syncState(ctx context.Context, key string) (string, error) {
+	var value string
+	err := s.db.QueryRowContext(ctx, `SELECT value FROM sync_state WHERE key = ?`, key).Scan(&value)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("query sync state %q: %w", key, err)
+	}
+	return value, nil
+}
+
+func (s *Store) newVocabEvent(eventType vocab.EventType, word, lang string, clock vocab.Clock) vocab.Event {
+	now := s.now()
 	return vocab.Event{
-		ID:        id.String(),
+		ID:        ulid.MustNew(ulid.Timestamp(now), ulid.DefaultEntropy()).String(),
 		Type:      eventType,
 		Word:      word,
-		Timestamp: time.Now().Unix(),
+		Language:  lang,
+		Timestamp: now.Unix(),
+		Clock:     clock,
+	}
+}
+
+// eventLanguage returns the language of an event. Events written before languages were tracked have none, and are
+// assumed to be English.
+func eventLanguage(event vocab.Event) string {
+	if event.Language == "" {
+		return dictionary.DefaultLanguage
 	}
+	return event.Language
 }
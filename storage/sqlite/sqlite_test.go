@@ -1,12 +1,26 @@
 package sqlite
 
 import (
+	"cmp"
+	"context"
 	"database/sql"
+	"fmt"
 	"io"
+	mathrand "math/rand"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
+	"testing/quick"
+	"time"
 
 	"github.com/caproven/termdict/dictionary"
+	"github.com/caproven/termdict/dictionary/bundle"
+	"github.com/caproven/termdict/dictionary/dump"
 	"github.com/caproven/termdict/vocab"
+	"github.com/oklog/ulid/v2"
+	"github.com/pressly/goose/v3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	_ "modernc.org/sqlite"
@@ -71,18 +85,64 @@ INSERT INTO definitions (word_id, definition, part_of_speech) VALUES
 (last_insert_rowid(), 'def 2', 'adjective');`)
 		require.NoError(t, err)
 
-		defs, err := store.LookupWord(t.Context(), "foo")
+		entries, err := store.LookupWord(t.Context(), "foo", "en")
 		require.NoError(t, err)
-		expectedDefs := []dictionary.Definition{
+		expected := []dictionary.Entry{{Definitions: []dictionary.Definition{
 			{PartOfSpeech: "noun", Meaning: "def 1"},
 			{PartOfSpeech: "adjective", Meaning: "def 2"},
-		}
-		assert.Equal(t, defs, expectedDefs)
+		}}}
+		assert.Equal(t, entries, expected)
 
 		// check case ignored for lookup
-		defs, err = store.LookupWord(t.Context(), "FOO")
+		entries, err = store.LookupWord(t.Context(), "FOO", "en")
+		require.NoError(t, err)
+		assert.Equal(t, expected, entries)
+	})
+
+	t.Run("with phonetics", func(t *testing.T) {
+		db := newTestDB(t)
+		defer closeAndAssertError(t, db)
+		store, err := NewStore(t.Context(), db)
+		require.NoError(t, err)
+
+		_, err = db.ExecContext(t.Context(), `INSERT INTO words (word) VALUES ('foo');
+INSERT INTO entries (word_id, position, phonetic) VALUES (last_insert_rowid(), 0, '/fuː/');
+INSERT INTO definitions (word_id, definition, part_of_speech) VALUES ((SELECT id FROM words WHERE word = 'foo'), 'def 1', 'noun');
+INSERT INTO phonetics (word_id, text, audio) VALUES
+((SELECT id FROM words WHERE word = 'foo'), '/fuː/', 'https://example.com/foo-uk.mp3'),
+((SELECT id FROM words WHERE word = 'foo'), '', 'https://example.com/foo-us.mp3');`)
+		require.NoError(t, err)
+
+		entries, err := store.LookupWord(t.Context(), "foo", "en")
+		require.NoError(t, err)
+		assert.Equal(t, []dictionary.Entry{{
+			Phonetic: "/fuː/",
+			Phonetics: []dictionary.Phonetic{
+				{Text: "/fuː/", Audio: "https://example.com/foo-uk.mp3"},
+				{Audio: "https://example.com/foo-us.mp3"},
+			},
+			Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "def 1"}},
+		}}, entries)
+	})
+
+	t.Run("multiple entries in order", func(t *testing.T) {
+		db := newTestDB(t)
+		defer closeAndAssertError(t, db)
+		store, err := NewStore(t.Context(), db)
+		require.NoError(t, err)
+
+		_, err = db.ExecContext(t.Context(), `INSERT INTO words (word) VALUES ('snow');
+INSERT INTO definitions (word_id, entry, definition, part_of_speech) VALUES
+(last_insert_rowid(), 1, 'def 2', 'noun'),
+(last_insert_rowid(), 0, 'def 1', 'verb');`)
+		require.NoError(t, err)
+
+		entries, err := store.LookupWord(t.Context(), "snow", "en")
 		require.NoError(t, err)
-		assert.Equal(t, expectedDefs, defs)
+		assert.Equal(t, []dictionary.Entry{
+			{Definitions: []dictionary.Definition{{PartOfSpeech: "verb", Meaning: "def 1"}}},
+			{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "def 2"}}},
+		}, entries)
 	})
 
 	t.Run("word exists with no definitions", func(t *testing.T) {
@@ -94,9 +154,19 @@ INSERT INTO definitions (word_id, definition, part_of_speech) VALUES
 		_, err = db.ExecContext(t.Context(), `INSERT INTO words (word) VALUES ('foo')`)
 		require.NoError(t, err)
 
-		defs, err := store.LookupWord(t.Context(), "foo")
+		entries, err := store.LookupWord(t.Context(), "foo", "en")
+		assert.Error(t, err)
+		assert.Len(t, entries, 0)
+	})
+
+	t.Run("word doesn't exist", func(t *testing.T) {
+		db := newTestDB(t)
+		defer closeAndAssertError(t, db)
+		store, err := NewStore(t.Context(), db)
+		require.NoError(t, err)
+
+		_, err = store.LookupWord(t.Context(), "foo", "en")
 		assert.Error(t, err)
-		assert.Len(t, defs, 0)
 	})
 }
 
@@ -130,7 +200,7 @@ func TestStore_ContainsWord(t *testing.T) {
 
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			ok, err := store.ContainsWord(t.Context(), tt.word)
+			ok, err := store.ContainsWord(t.Context(), tt.word, "en")
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.False(t, ok)
@@ -142,6 +212,39 @@ func TestStore_ContainsWord(t *testing.T) {
 	}
 }
 
+func TestStore_WithProvider(t *testing.T) {
+	db := newTestDB(t)
+	defer closeAndAssertError(t, db)
+	store, err := NewStore(t.Context(), db)
+	require.NoError(t, err)
+	wiktionary := store.WithProvider("wiktionary")
+
+	fromDefault := []dictionary.Entry{{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "def 1"}}}}
+	require.NoError(t, store.SaveWord(t.Context(), "snow", "en", fromDefault))
+
+	ok, err := wiktionary.ContainsWord(t.Context(), "snow", "en")
+	require.NoError(t, err)
+	assert.False(t, ok, "words cached by another provider shouldn't be visible")
+
+	fromWiktionary := []dictionary.Entry{{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "def 2"}}}}
+	require.NoError(t, wiktionary.SaveWord(t.Context(), "snow", "en", fromWiktionary))
+
+	got, err := store.LookupWord(t.Context(), "snow", "en")
+	require.NoError(t, err)
+	assert.Equal(t, fromDefault, got)
+
+	got, err = wiktionary.LookupWord(t.Context(), "snow", "en")
+	require.NoError(t, err)
+	assert.Equal(t, fromWiktionary, got)
+
+	// The vocab list isn't namespaced
+	_, err = wiktionary.AddWordsToList(t.Context(), []string{"snow"}, "en")
+	require.NoError(t, err)
+	list, err := store.GetWordsInList(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []vocab.Entry{{Word: "snow", Language: "en"}}, list)
+}
+
 func TestStore_SaveWord(t *testing.T) {
 	t.Run("lowercase word", func(t *testing.T) {
 		db := newTestDB(t)
@@ -149,16 +252,18 @@ func TestStore_SaveWord(t *testing.T) {
 		store, err := NewStore(t.Context(), db)
 		require.NoError(t, err)
 
-		defs := []dictionary.Definition{
-			{PartOfSpeech: "verb", Meaning: "def 1"},
-			{PartOfSpeech: "adverb", Meaning: "def 2"},
-		}
-		err = store.SaveWord(t.Context(), "foo", defs)
+		entries := []dictionary.Entry{{
+			Definitions: []dictionary.Definition{
+				{PartOfSpeech: "verb", Meaning: "def 1"},
+				{PartOfSpeech: "adverb", Meaning: "def 2"},
+			},
+		}}
+		err = store.SaveWord(t.Context(), "foo", "en", entries)
 		require.NoError(t, err)
 
-		gotDefs, err := store.LookupWord(t.Context(), "foo")
+		got, err := store.LookupWord(t.Context(), "foo", "en")
 		require.NoError(t, err)
-		assert.Equal(t, defs, gotDefs)
+		assert.Equal(t, entries, got)
 
 		// Ensure only a single row written to words table
 		var wordCount int
@@ -171,43 +276,127 @@ func TestStore_SaveWord(t *testing.T) {
 		assert.Equal(t, 2, defCount)
 	})
 
+	t.Run("same word in different languages", func(t *testing.T) {
+		db := newTestDB(t)
+		defer closeAndAssertError(t, db)
+		store, err := NewStore(t.Context(), db)
+		require.NoError(t, err)
+
+		english := []dictionary.Entry{{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "a present"}}}}
+		german := []dictionary.Entry{{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "poison"}}}}
+		require.NoError(t, store.SaveWord(t.Context(), "gift", "en", english))
+		require.NoError(t, store.SaveWord(t.Context(), "gift", "de", german))
+
+		got, err := store.LookupWord(t.Context(), "gift", "en")
+		require.NoError(t, err)
+		assert.Equal(t, english, got)
+
+		got, err = store.LookupWord(t.Context(), "gift", "de")
+		require.NoError(t, err)
+		assert.Equal(t, german, got)
+
+		ok, err := store.ContainsWord(t.Context(), "gift", "es")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
 	t.Run("uppercase word transformed to lowercase", func(t *testing.T) {
 		db := newTestDB(t)
 		defer closeAndAssertError(t, db)
 		store, err := NewStore(t.Context(), db)
 		require.NoError(t, err)
 
-		defs := []dictionary.Definition{
-			{PartOfSpeech: "conjunction", Meaning: "def 1"},
-		}
-		err = store.SaveWord(t.Context(), "BAR", defs)
+		entries := []dictionary.Entry{{
+			Definitions: []dictionary.Definition{
+				{PartOfSpeech: "conjunction", Meaning: "def 1"},
+			},
+		}}
+		err = store.SaveWord(t.Context(), "BAR", "en", entries)
+		require.NoError(t, err)
+
+		got, err := store.LookupWord(t.Context(), "bar", "en")
+		require.NoError(t, err)
+		assert.Equal(t, entries, got)
+	})
+
+	t.Run("full entry", func(t *testing.T) {
+		db := newTestDB(t)
+		defer closeAndAssertError(t, db)
+		store, err := NewStore(t.Context(), db)
+		require.NoError(t, err)
+
+		entries := []dictionary.Entry{{
+			Phonetic: "/snəʊ/",
+			Phonetics: []dictionary.Phonetic{
+				{Text: "/snəʊ/", Audio: "https://example.com/snow-uk.mp3"},
+				{Text: "/snoʊ/", Audio: "https://example.com/snow-us.mp3"},
+			},
+			Definitions: []dictionary.Definition{
+				{PartOfSpeech: "noun", Meaning: "def 1", Synonyms: []string{"syn 1"}},
+				{PartOfSpeech: "verb", Meaning: "def 2", Example: "example 2", Antonyms: []string{"ant 2a", "ant 2b"}},
+			},
+			Relations: []dictionary.Relations{
+				{PartOfSpeech: "noun", Synonyms: []string{"blow", "shash"}, Antonyms: []string{"ant 3"}},
+				{PartOfSpeech: "verb", Synonyms: []string{"syn 4"}},
+			},
+			SourceURLs: []string{"https://en.wiktionary.org/wiki/snow"},
+			License:    dictionary.License{Name: "CC BY-SA 3.0", URL: "https://creativecommons.org/licenses/by-sa/3.0"},
+		}, {
+			Definitions: []dictionary.Definition{
+				{PartOfSpeech: "noun", Meaning: "def 3", Synonyms: []string{"syn 5"}},
+			},
+			SourceURLs: []string{"https://en.wiktionary.org/wiki/snow", "https://example.com/snow"},
+			Relations: []dictionary.Relations{
+				{PartOfSpeech: "noun", Antonyms: []string{"ant 6"}},
+			},
+		}}
+		err = store.SaveWord(t.Context(), "snow", "en", entries)
 		require.NoError(t, err)
 
-		gotDefs, err := store.LookupWord(t.Context(), "bar")
+		got, err := store.LookupWord(t.Context(), "snow", "en")
 		require.NoError(t, err)
-		assert.Equal(t, defs, gotDefs)
+		assert.Equal(t, entries, got)
+
+		var phoneticCount int
+		require.NoError(t, db.QueryRowContext(t.Context(), `SELECT count() FROM phonetics`).Scan(&phoneticCount))
+		assert.Equal(t, 2, phoneticCount)
+
+		var relatedCount int
+		require.NoError(t, db.QueryRowContext(t.Context(), `SELECT count() FROM related_words`).Scan(&relatedCount))
+		assert.Equal(t, 9, relatedCount)
+
+		var entryCount int
+		require.NoError(t, db.QueryRowContext(t.Context(), `SELECT count() FROM entries`).Scan(&entryCount))
+		assert.Equal(t, 2, entryCount)
 	})
 
 	t.Run("invalid inputs", func(t *testing.T) {
 		tests := map[string]struct {
-			word string
-			defs []dictionary.Definition
+			word    string
+			entries []dictionary.Entry
 		}{
 			"empty word": {
 				word: "",
-				defs: []dictionary.Definition{
+				entries: []dictionary.Entry{{Definitions: []dictionary.Definition{
 					{PartOfSpeech: "verb", Meaning: "def 1"},
-				},
+				}}},
 			},
 			"whitespace word": {
 				word: " ",
-				defs: []dictionary.Definition{
+				entries: []dictionary.Entry{{Definitions: []dictionary.Definition{
 					{PartOfSpeech: "verb", Meaning: "def 1"},
-				},
+				}}},
 			},
-			"no definitions": {
+			"no entries": {
+				word:    "foo",
+				entries: []dictionary.Entry{},
+			},
+			"entry without definitions": {
 				word: "foo",
-				defs: []dictionary.Definition{},
+				entries: []dictionary.Entry{
+					{Definitions: []dictionary.Definition{{PartOfSpeech: "verb", Meaning: "def 1"}}},
+					{Definitions: []dictionary.Definition{}},
+				},
 			},
 		}
 
@@ -218,7 +407,7 @@ func TestStore_SaveWord(t *testing.T) {
 				store, err := NewStore(t.Context(), db)
 				require.NoError(t, err)
 
-				err = store.SaveWord(t.Context(), tt.word, tt.defs)
+				err = store.SaveWord(t.Context(), tt.word, "en", tt.entries)
 				assert.Error(t, err)
 
 				// Ensure no entries written to words table
@@ -234,23 +423,223 @@ func TestStore_SaveWord(t *testing.T) {
 		}
 	})
 
-	t.Run("word already exists", func(t *testing.T) {
+	t.Run("word already cached is replaced", func(t *testing.T) {
 		db := newTestDB(t)
 		defer closeAndAssertError(t, db)
 		store, err := NewStore(t.Context(), db)
 		require.NoError(t, err)
 
-		_, err = db.ExecContext(t.Context(), `INSERT INTO words (word) VALUES ('foo')`)
+		old := []dictionary.Entry{{
+			Definitions: []dictionary.Definition{{PartOfSpeech: "verb", Meaning: "def 1", Synonyms: []string{"bar"}}},
+			SourceURLs:  []string{"https://example.com/foo"},
+		}}
+		require.NoError(t, store.SaveWord(t.Context(), "foo", "en", old))
+		_, err = db.ExecContext(t.Context(), `UPDATE words SET fetched_at = 100`)
 		require.NoError(t, err)
 
-		err = store.SaveWord(t.Context(), "foo", []dictionary.Definition{
+		refreshed := []dictionary.Entry{{Definitions: []dictionary.Definition{
 			{PartOfSpeech: "verb", Meaning: "def 1"},
-		})
-		assert.Error(t, err)
-		// TODO check ids or something
+			{PartOfSpeech: "noun", Meaning: "def 2"},
+		}}}
+		require.NoError(t, store.SaveWord(t.Context(), "foo", "en", refreshed))
+
+		got, err := store.LookupWord(t.Context(), "foo", "en")
+		require.NoError(t, err)
+		assert.Equal(t, refreshed, got)
+
+		fetchedAt, err := store.FetchedAt(t.Context(), "foo", "en")
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), fetchedAt, time.Minute)
+
+		for table, want := range map[string]int{"words": 1, "definitions": 2, "related_words": 0, "entry_sources": 0} {
+			var count int
+			require.NoError(t, db.QueryRowContext(t.Context(), `SELECT count() FROM `+table).Scan(&count))
+			assert.Equal(t, want, count, "rows in %s", table)
+		}
 	})
 }
 
+func TestStore_FetchedAt(t *testing.T) {
+	db := newTestDB(t)
+	defer closeAndAssertError(t, db)
+	store, err := NewStore(t.Context(), db)
+	require.NoError(t, err)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	entries := []dictionary.Entry{{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "def 1"}}}}
+	require.NoError(t, store.SaveWord(t.Context(), "snow", "en", entries))
+
+	got, err := store.FetchedAt(t.Context(), "Snow", "en")
+	require.NoError(t, err)
+	assert.True(t, now.Equal(got), "got %v", got)
+
+	_, err = store.FetchedAt(t.Context(), "snow", "de")
+	assert.Error(t, err)
+
+	_, err = store.WithProvider("wiktionary").FetchedAt(t.Context(), "snow", "en")
+	assert.Error(t, err)
+
+	// Words cached before fetch times were recorded
+	_, err = db.ExecContext(t.Context(), `UPDATE words SET fetched_at = 0`)
+	require.NoError(t, err)
+	got, err = store.FetchedAt(t.Context(), "snow", "en")
+	require.NoError(t, err)
+	assert.True(t, got.IsZero())
+}
+
+func TestStore_NotFound(t *testing.T) {
+	db := newTestDB(t)
+	defer closeAndAssertError(t, db)
+	store, err := NewStore(t.Context(), db)
+	require.NoError(t, err)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	got, err := store.NotFoundAt(t.Context(), "snoww", "en")
+	require.NoError(t, err)
+	assert.True(t, got.IsZero())
+
+	_, err = db.ExecContext(t.Context(), `INSERT INTO not_found_words (word, language, provider, checked_at) VALUES ('snoww', 'en', 'freedictionary', 100)`)
+	require.NoError(t, err)
+	require.NoError(t, store.SaveNotFound(t.Context(), "Snoww", "en"))
+
+	got, err = store.NotFoundAt(t.Context(), "snoww", "en")
+	require.NoError(t, err)
+	assert.True(t, now.Equal(got), "saving again should update the time, got %v", got)
+
+	got, err = store.NotFoundAt(t.Context(), "snoww", "de")
+	require.NoError(t, err)
+	assert.True(t, got.IsZero())
+	got, err = store.WithProvider("wiktionary").NotFoundAt(t.Context(), "snoww", "en")
+	require.NoError(t, err)
+	assert.True(t, got.IsZero(), "not found words should only be visible to their provider")
+
+	// Defining the word later forgets that it wasn't found
+	entries := []dictionary.Entry{{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "def 1"}}}}
+	require.NoError(t, store.SaveWord(t.Context(), "snoww", "en", entries))
+	got, err = store.NotFoundAt(t.Context(), "snoww", "en")
+	require.NoError(t, err)
+	assert.True(t, got.IsZero())
+}
+
+func TestStore_CachedWords(t *testing.T) {
+	db := newTestDB(t)
+	defer closeAndAssertError(t, db)
+	store, err := NewStore(t.Context(), db)
+	require.NoError(t, err)
+
+	entries := []dictionary.Entry{{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "def 1"}}}}
+	require.NoError(t, store.SaveWord(t.Context(), "snow", "en", entries))
+	require.NoError(t, store.SaveWord(t.Context(), "gift", "de", entries))
+	require.NoError(t, store.WithProvider("wiktionary").SaveWord(t.Context(), "gift", "en", entries))
+	require.NoError(t, store.WithProvider("offline").SaveWord(t.Context(), "snow", "en", entries))
+	_, err = db.ExecContext(t.Context(), `UPDATE words SET fetched_at = 100 WHERE word = 'snow'`)
+	require.NoError(t, err)
+
+	got, err := store.CachedWords(t.Context())
+	require.NoError(t, err)
+	require.Len(t, got, 3, "the offline dictionary isn't part of the cache")
+	assert.Equal(t, dictionary.CachedWord{Word: "gift", Language: "de", Provider: "freedictionary", FetchedAt: got[0].FetchedAt}, got[0])
+	assert.Equal(t, dictionary.CachedWord{Word: "snow", Language: "en", Provider: "freedictionary", FetchedAt: time.Unix(100, 0)}, got[1])
+	assert.Equal(t, dictionary.CachedWord{Word: "gift", Language: "en", Provider: "wiktionary", FetchedAt: got[2].FetchedAt}, got[2])
+	assert.False(t, got[0].FetchedAt.IsZero())
+}
+
+func TestStore_ImportRecords(t *testing.T) {
+	db := newTestDB(t)
+	defer closeAndAssertError(t, db)
+	store, err := NewStore(t.Context(), db)
+	require.NoError(t, err)
+	offline := store.WithProvider("offline")
+
+	first := dictionary.Entry{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "def 1"}}}
+	second := dictionary.Entry{Definitions: []dictionary.Definition{{PartOfSpeech: "verb", Meaning: "def 2"}}}
+	german := dictionary.Entry{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "Gift"}}}
+
+	added, err := offline.ImportRecords(t.Context(), []dump.Record{
+		{Word: "Gift", Language: "en", Entry: first},
+		{Word: "gift", Language: "de", Entry: german},
+		{Word: "blank", Language: "en"},
+	}, false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, added)
+	// Entries of a word can span batches
+	added, err = offline.ImportRecords(t.Context(), []dump.Record{
+		{Word: "gift", Language: "en", Entry: second},
+	}, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, added)
+
+	// Importing the dump again skips the entries it already added
+	added, err = offline.ImportRecords(t.Context(), []dump.Record{
+		{Word: "gift", Language: "en", Entry: first},
+		{Word: "gift", Language: "en", Entry: second},
+	}, false)
+	require.NoError(t, err)
+	assert.Zero(t, added)
+
+	got, err := offline.LookupWord(t.Context(), "gift", "en")
+	require.NoError(t, err)
+	assert.Equal(t, []dictionary.Entry{first, second}, got)
+
+	got, err = offline.LookupWord(t.Context(), "gift", "de")
+	require.NoError(t, err)
+	assert.Equal(t, []dictionary.Entry{german}, got)
+
+	ok, err := offline.ContainsWord(t.Context(), "blank", "en")
+	require.NoError(t, err)
+	assert.False(t, ok, "records without definitions shouldn't be imported")
+
+	ok, err = store.ContainsWord(t.Context(), "gift", "en")
+	require.NoError(t, err)
+	assert.False(t, ok, "imported words should only be visible to their provider")
+}
+
+func TestStore_ImportRecords_Replace(t *testing.T) {
+	db := newTestDB(t)
+	defer closeAndAssertError(t, db)
+	store, err := NewStore(t.Context(), db)
+	require.NoError(t, err)
+	offline := store.WithProvider("offline")
+
+	entries := []dictionary.Entry{{
+		Phonetics:   []dictionary.Phonetic{{Text: "/snəʊ/"}},
+		Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "def 1", Synonyms: []string{"blow"}}},
+		SourceURLs:  []string{"https://example.com/snow"},
+	}}
+	require.NoError(t, store.SaveWord(t.Context(), "snow", "en", entries))
+	require.NoError(t, offline.SaveWord(t.Context(), "snow", "en", entries))
+
+	added, err := offline.ImportRecords(t.Context(), nil, true)
+	require.NoError(t, err)
+	assert.Zero(t, added)
+
+	ok, err := offline.ContainsWord(t.Context(), "snow", "en")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	got, err := store.LookupWord(t.Context(), "snow", "en")
+	require.NoError(t, err)
+	assert.Equal(t, entries, got, "other providers' words should be kept")
+
+	for _, table := range wordTables {
+		var count int
+		require.NoError(t, db.QueryRowContext(t.Context(), `SELECT count() FROM `+table).Scan(&count))
+		assert.Equal(t, 1, count, "rows left in %s", table)
+	}
+
+	// The words are only removed if the import succeeds
+	require.NoError(t, offline.SaveWord(t.Context(), "snow", "en", entries))
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	_, err = offline.ImportRecords(ctx, []dump.Record{{Word: "rain", Language: "en", Entry: entries[0]}}, true)
+	require.Error(t, err)
+	ok, err = offline.ContainsWord(t.Context(), "snow", "en")
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
 func TestStore_AddWordsToList(t *testing.T) {
 	t.Run("all new words", func(t *testing.T) {
 		db := newTestDB(t)
@@ -259,7 +648,7 @@ func TestStore_AddWordsToList(t *testing.T) {
 		require.NoError(t, err)
 
 		list := []string{"cascade", "dour"}
-		added, err := store.AddWordsToList(t.Context(), list)
+		added, err := store.AddWordsToList(t.Context(), list, "en")
 		require.NoError(t, err)
 		assert.Equal(t, list, added)
 
@@ -276,7 +665,7 @@ func TestStore_AddWordsToList(t *testing.T) {
 		_, err = db.ExecContext(t.Context(), `INSERT INTO vocab (word) VALUES ('foo')`)
 		require.NoError(t, err)
 
-		added, err := store.AddWordsToList(t.Context(), []string{"foo", "bar", "baz"})
+		added, err := store.AddWordsToList(t.Context(), []string{"foo", "bar", "baz"}, "en")
 		require.NoError(t, err)
 		assert.Equal(t, []string{"bar", "baz"}, added)
 
@@ -293,7 +682,7 @@ func TestStore_AddWordsToList(t *testing.T) {
 		_, err = db.ExecContext(t.Context(), `INSERT INTO vocab (word) VALUES ('foo'), ('bar')`)
 		require.NoError(t, err)
 
-		added, err := store.AddWordsToList(t.Context(), []string{"foo", "bar"})
+		added, err := store.AddWordsToList(t.Context(), []string{"foo", "bar"}, "en")
 		require.NoError(t, err)
 		assert.Len(t, added, 0)
 
@@ -307,7 +696,7 @@ func TestStore_AddWordsToList(t *testing.T) {
 		store, err := NewStore(t.Context(), db)
 		require.NoError(t, err)
 
-		added, err := store.AddWordsToList(t.Context(), []string{"IRRESOLUTE"})
+		added, err := store.AddWordsToList(t.Context(), []string{"IRRESOLUTE"}, "en")
 		require.NoError(t, err)
 		assert.Equal(t, []string{"irresolute"}, added)
 
@@ -324,7 +713,7 @@ func TestStore_AddWordsToList(t *testing.T) {
 		_, err = db.ExecContext(t.Context(), `INSERT INTO vocab (word) VALUES ('cacophony')`)
 		require.NoError(t, err)
 
-		added, err := store.AddWordsToList(t.Context(), []string{"CACOPHONY"})
+		added, err := store.AddWordsToList(t.Context(), []string{"CACOPHONY"}, "en")
 		require.NoError(t, err)
 		assert.Len(t, added, 0)
 
@@ -333,6 +722,48 @@ func TestStore_AddWordsToList(t *testing.T) {
 	})
 }
 
+func TestStore_AddWordsToList_Language(t *testing.T) {
+	db := newTestDB(t)
+	defer closeAndAssertError(t, db)
+	store, err := NewStore(t.Context(), db)
+	require.NoError(t, err)
+
+	added, err := store.AddWordsToList(t.Context(), []string{"schnee"}, "de")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"schnee"}, added)
+
+	// Words are listed once per language
+	added, err = store.AddWordsToList(t.Context(), []string{"Schnee"}, "de")
+	require.NoError(t, err)
+	assert.Empty(t, added)
+	added, err = store.AddWordsToList(t.Context(), []string{"schnee"}, "en")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"schnee"}, added)
+
+	got, err := store.GetWordsInList(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []vocab.Entry{{Word: "schnee", Language: "de"}, {Word: "schnee", Language: "en"}}, got)
+
+	// Removing a word removes it in every language, and survives replaying the events
+	removed, err := store.RemoveWordsFromList(t.Context(), []string{"schnee"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"schnee"}, removed)
+	_, err = store.AddEvents(t.Context(), "events.jsonl", nil)
+	require.NoError(t, err)
+	got, err = store.GetWordsInList(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	events, err := store.GetEvents(t.Context())
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+	var langs []string
+	for _, event := range events {
+		langs = append(langs, string(event.Type)+" "+event.Language)
+	}
+	assert.Equal(t, []string{"add de", "add en", "remove de", "remove en"}, langs)
+}
+
 func TestStore_RemoveWordsFromList(t *testing.T) {
 	t.Run("all words to delete exist", func(t *testing.T) {
 		db := newTestDB(t)
@@ -444,7 +875,7 @@ func TestStore_GetWordsInList(t *testing.T) {
 
 		got, err := store.GetWordsInList(t.Context())
 		require.NoError(t, err)
-		assert.Equal(t, []string{"aardvark", "zebra"}, got)
+		assert.Equal(t, []vocab.Entry{{Word: "aardvark", Language: "en"}, {Word: "zebra", Language: "en"}}, got)
 	})
 
 	t.Run("words not alphabetically sorted at insertion", func(t *testing.T) {
@@ -458,7 +889,7 @@ func TestStore_GetWordsInList(t *testing.T) {
 
 		got, err := store.GetWordsInList(t.Context())
 		require.NoError(t, err)
-		assert.Equal(t, []string{"aardvark", "zebra"}, got)
+		assert.Equal(t, []vocab.Entry{{Word: "aardvark", Language: "en"}, {Word: "zebra", Language: "en"}}, got)
 	})
 }
 
@@ -474,13 +905,13 @@ func TestStore_GetEvents(t *testing.T) {
 		assert.Empty(t, events)
 	})
 
-	t.Run("returns events ordered by timestamp", func(t *testing.T) {
+	t.Run("returns events in replay order", func(t *testing.T) {
 		db := newTestDB(t)
 		defer closeAndAssertError(t, db)
 		store, err := NewStore(t.Context(), db)
 		require.NoError(t, err)
 
-		_, err = store.AddWordsToList(t.Context(), []string{"bar", "foo"})
+		_, err = store.AddWordsToList(t.Context(), []string{"bar", "foo"}, "en")
 		require.NoError(t, err)
 
 		events, err := store.GetEvents(t.Context())
@@ -492,7 +923,7 @@ func TestStore_GetEvents(t *testing.T) {
 		assert.Equal(t, "foo", events[1].Word)
 		assert.NotEmpty(t, events[0].ID)
 		assert.NotEmpty(t, events[1].ID)
-		assert.True(t, events[0].Timestamp <= events[1].Timestamp, "events should be ordered by timestamp")
+		assert.Negative(t, events[0].Clock.Compare(events[1].Clock), "events in the same transaction have increasing clocks")
 	})
 }
 
@@ -509,18 +940,37 @@ func TestStore_AddEvents(t *testing.T) {
 			{ID: "01J3XYZ3", Type: vocab.EventTypeRemove, Word: "foo", Timestamp: 300},
 		}
 
-		err = store.AddEvents(t.Context(), events)
+		added, err := store.AddEvents(t.Context(), "events.jsonl", events)
 		require.NoError(t, err)
+		assert.Equal(t, 3, added)
 
 		got, err := store.GetWordsInList(t.Context())
 		require.NoError(t, err)
-		assert.Equal(t, []string{"bar"}, got)
+		assert.Equal(t, []vocab.Entry{{Word: "bar", Language: "en"}}, got)
 
 		stored, err := store.GetEvents(t.Context())
 		require.NoError(t, err)
 		assert.Len(t, stored, 3)
 	})
 
+	t.Run("events without a language default to english", func(t *testing.T) {
+		db := newTestDB(t)
+		defer closeAndAssertError(t, db)
+		store, err := NewStore(t.Context(), db)
+		require.NoError(t, err)
+
+		events := []vocab.Event{
+			{ID: "01J3XYZ1", Type: vocab.EventTypeAdd, Word: "foo", Timestamp: 100},
+			{ID: "01J3XYZ2", Type: vocab.EventTypeAdd, Word: "bar", Language: "es", Timestamp: 200},
+		}
+		_, err = store.AddEvents(t.Context(), "events.jsonl", events)
+		require.NoError(t, err)
+
+		got, err := store.GetWordsInList(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, []vocab.Entry{{Word: "bar", Language: "es"}, {Word: "foo", Language: "en"}}, got)
+	})
+
 	t.Run("ignores duplicate event IDs", func(t *testing.T) {
 		db := newTestDB(t)
 		defer closeAndAssertError(t, db)
@@ -531,7 +981,7 @@ func TestStore_AddEvents(t *testing.T) {
 			{ID: "01J3XYZ1", Type: vocab.EventTypeAdd, Word: "foo", Timestamp: 100},
 		}
 
-		err = store.AddEvents(t.Context(), events)
+		_, err = store.AddEvents(t.Context(), "events.jsonl", events)
 		require.NoError(t, err)
 
 		// Same ID, should be ignored
@@ -539,8 +989,9 @@ func TestStore_AddEvents(t *testing.T) {
 			{ID: "01J3XYZ1", Type: vocab.EventTypeAdd, Word: "foo", Timestamp: 100},
 			{ID: "01J3XYZ2", Type: vocab.EventTypeAdd, Word: "bar", Timestamp: 200},
 		}
-		err = store.AddEvents(t.Context(), events2)
+		added, err := store.AddEvents(t.Context(), "events.jsonl", events2)
 		require.NoError(t, err)
+		assert.Equal(t, 1, added, "only the new event should be counted")
 
 		stored, err := store.GetEvents(t.Context())
 		require.NoError(t, err)
@@ -548,15 +999,889 @@ func TestStore_AddEvents(t *testing.T) {
 	})
 }
 
-func getVocabList(t testing.TB, db *sql.DB) []string {
-	t.Helper()
-	rows, err := db.QueryContext(t.Context(), `SELECT word FROM vocab ORDER BY word`)
+func TestStore_AddEvents_Causal(t *testing.T) {
+	// The laptop's clock is an hour ahead of the phone's
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	laptop := newClockedStore(t, start.Add(time.Hour))
+	phone := newClockedStore(t, start)
+
+	_, err := laptop.AddWordsToList(t.Context(), []string{"snow"}, "en")
 	require.NoError(t, err)
-	var list []string
-	for rows.Next() {
-		var word string
-		require.NoError(t, rows.Scan(&word))
-		list = append(list, word)
+	syncEvents(t, laptop, phone)
+
+	// Removing a word seen from the laptop comes after adding it, even though the phone's clock is behind
+	_, err = phone.RemoveWordsFromList(t.Context(), []string{"snow"})
+	require.NoError(t, err)
+	syncEvents(t, phone, laptop)
+
+	for name, store := range map[string]*Store{"laptop": laptop, "phone": phone} {
+		got, err := store.GetWordsInList(t.Context())
+		require.NoError(t, err)
+		assert.Empty(t, got, "snow should be removed on the %s", name)
 	}
-	return list
+}
+
+func TestStore_AddEvents_SameSecond(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	store := newClockedStore(t, now)
+
+	// Adding and removing within the same millisecond are still ordered
+	_, err := store.AddWordsToList(t.Context(), []string{"snow"}, "en")
+	require.NoError(t, err)
+	_, err = store.RemoveWordsFromList(t.Context(), []string{"snow"})
+	require.NoError(t, err)
+	_, err = store.AddWordsToList(t.Context(), []string{"snow"}, "de")
+	require.NoError(t, err)
+
+	other := newClockedStore(t, now)
+	syncEvents(t, store, other)
+
+	got, err := other.GetWordsInList(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []vocab.Entry{{Word: "snow", Language: "de"}}, got)
+}
+
+// TestStore_AddEvents_Converges checks that stores end up with the same list when given the same events, whatever
+// order and batches they're added in, and that the list is that of replaying the events in the order of vocab.Compare.
+func TestStore_AddEvents_Converges(t *testing.T) {
+	property := func(log eventLog, seed uint64) bool {
+		r := rand.New(rand.NewPCG(seed, seed))
+		want := replayEvents(log)
+
+		for range 2 {
+			store := newClockedStore(t, time.Now())
+			events := slices.Clone(log)
+			r.Shuffle(len(events), func(i, j int) { events[i], events[j] = events[j], events[i] })
+			for len(events) > 0 {
+				n := 1 + r.IntN(len(events))
+				// Some events are added twice, as when devices sync the same events from each other
+				if _, err := store.AddEvents(t.Context(), "events.jsonl", append(events[:n:n], events[r.IntN(n)])); err != nil {
+					t.Logf("add events: %v", err)
+					return false
+				}
+				events = events[n:]
+			}
+
+			got, err := store.GetWordsInList(t.Context())
+			if err != nil {
+				t.Logf("get words: %v", err)
+				return false
+			}
+			if !slices.Equal(got, want) {
+				t.Logf("got list %v, expected %v", got, want)
+				return false
+			}
+		}
+		return true
+	}
+
+	require.NoError(t, quick.Check(property, &quick.Config{MaxCount: 50}))
+}
+
+// TestStore_AddEvents_LocalConverges checks that a store's own changes, interleaved with events from other devices,
+// lead to the same list on a store given all of its events.
+func TestStore_AddEvents_LocalConverges(t *testing.T) {
+	property := func(log eventLog, seed uint64) bool {
+		r := rand.New(rand.NewPCG(seed, seed))
+		words := []string{"snow", "rain", "hail", "sleet"}
+
+		now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		local := newClockedStore(t, now)
+		for _, event := range log {
+			// The local clock stands still or jumps back, as skewed clocks do
+			local.now = func() time.Time { return now.Add(time.Duration(r.IntN(3)-1) * time.Second) }
+			var err error
+			switch r.IntN(3) {
+			case 0:
+				_, err = local.AddWordsToList(t.Context(), []string{words[r.IntN(len(words))]}, "en")
+			case 1:
+				_, err = local.RemoveWordsFromList(t.Context(), []string{words[r.IntN(len(words))]})
+			default:
+				_, err = local.AddEvents(t.Context(), "events.jsonl", []vocab.Event{event})
+			}
+			if err != nil {
+				t.Logf("change list: %v", err)
+				return false
+			}
+		}
+
+		remote := newClockedStore(t, now)
+		syncEvents(t, local, remote)
+
+		want, err := local.GetWordsInList(t.Context())
+		if err != nil {
+			return false
+		}
+		got, err := remote.GetWordsInList(t.Context())
+		if err != nil {
+			return false
+		}
+		if !slices.Equal(got, want) {
+			t.Logf("got list %v, expected %v", got, want)
+			return false
+		}
+		return true
+	}
+
+	require.NoError(t, quick.Check(property, &quick.Config{MaxCount: 30}))
+}
+
+// eventLog is a random log of vocab events, generated by testing/quick. Few words and times are used, so events often
+// change the same word at the same time, and some events have no clock like those written before clocks were recorded.
+type eventLog []vocab.Event
+
+func (eventLog) Generate(r *mathrand.Rand, size int) reflect.Value {
+	words := []string{"snow", "rain", "hail", "sleet"}
+	langs := []string{"en", "de"}
+	types := []vocab.EventType{vocab.EventTypeAdd, vocab.EventTypeRemove}
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	log := make(eventLog, 1+r.Intn(size+1))
+	for i := range log {
+		at := start.Add(time.Duration(r.Intn(3)) * time.Second)
+		event := vocab.Event{
+			ID:        ulid.MustNew(ulid.Timestamp(at), r).String(),
+			Type:      types[r.Intn(len(types))],
+			Word:      words[r.Intn(len(words))],
+			Language:  langs[r.Intn(len(langs))],
+			Timestamp: at.Unix(),
+		}
+		if r.Intn(4) > 0 {
+			event.Clock = vocab.Clock{Wall: at.UnixMilli(), Logical: int64(r.Intn(2))}
+		}
+		log[i] = event
+	}
+	return reflect.ValueOf(log)
+}
+
+// replayEvents returns the list made by replaying events in order, deduplicated by ID
+func replayEvents(events []vocab.Event) []vocab.Entry {
+	events = slices.Clone(events)
+	slices.SortFunc(events, vocab.Compare)
+	events = slices.CompactFunc(events, func(a, b vocab.Event) bool { return a.ID == b.ID })
+
+	last := make(map[vocab.Entry]vocab.Event)
+	for _, event := range events {
+		last[vocab.Entry{Word: event.Word, Language: event.Language}] = event
+	}
+	var list []vocab.Entry
+	for entry, event := range last {
+		if event.Type == vocab.EventTypeAdd {
+			list = append(list, entry)
+		}
+	}
+	slices.SortFunc(list, func(a, b vocab.Entry) int {
+		return cmp.Or(cmp.Compare(a.Word, b.Word), cmp.Compare(a.Language, b.Language))
+	})
+	return list
+}
+
+// newClockedStore creates a store in a new database whose clock reads now
+func newClockedStore(t *testing.T, now time.Time) *Store {
+	t.Helper()
+	db := newTestDB(t)
+	t.Cleanup(func() { closeAndAssertError(t, db) })
+	store, err := NewStore(t.Context(), db)
+	require.NoError(t, err)
+	store.now = func() time.Time { return now }
+	return store
+}
+
+// syncEvents adds the events of one store to another
+func syncEvents(t *testing.T, from, to *Store) {
+	t.Helper()
+	events, err := from.GetEvents(t.Context())
+	require.NoError(t, err)
+	_, err = to.AddEvents(t.Context(), "events.jsonl", events)
+	require.NoError(t, err)
+}
+
+func TestStore_GetHistory(t *testing.T) {
+	day := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	store := newClockedStore(t, day)
+	_, err := store.AddWordsToList(t.Context(), []string{"snow", "rain"}, "en")
+	require.NoError(t, err)
+
+	store.now = func() time.Time { return day.Add(48 * time.Hour) }
+	imported := []vocab.Event{
+		{ID: "01J3XYZ1", Type: vocab.EventTypeRemove, Word: "snow", Language: "en", Timestamp: day.Add(24 * time.Hour).Unix()},
+		{ID: "01J3XYZ2", Type: vocab.EventTypeAdd, Word: "hail", Language: "de", Timestamp: day.Add(24 * time.Hour).Unix()},
+	}
+	_, err = store.AddEvents(t.Context(), "laptop.jsonl", imported)
+	require.NoError(t, err)
+	// Importing events again adds nothing to the history
+	added, err := store.AddEvents(t.Context(), "laptop-again.jsonl", imported)
+	require.NoError(t, err)
+	assert.Zero(t, added)
+
+	laptop := &vocab.Import{Source: "laptop.jsonl", At: time.Unix(day.Add(48*time.Hour).Unix(), 0)}
+
+	tests := map[string]struct {
+		filter vocab.HistoryFilter
+		// want are the words and imports of the events expected, in order
+		want []string
+	}{
+		"everything": {
+			want: []string{"snow", "rain", "snow laptop.jsonl", "hail laptop.jsonl"},
+		},
+		"word": {
+			filter: vocab.HistoryFilter{Word: "SNOW"},
+			want:   []string{"snow", "snow laptop.jsonl"},
+		},
+		"type": {
+			filter: vocab.HistoryFilter{Type: vocab.EventTypeAdd},
+			want:   []string{"snow", "rain", "hail laptop.jsonl"},
+		},
+		"since": {
+			filter: vocab.HistoryFilter{Since: day.Add(time.Hour)},
+			want:   []string{"snow laptop.jsonl", "hail laptop.jsonl"},
+		},
+		"until": {
+			filter: vocab.HistoryFilter{Until: day.Add(time.Hour)},
+			want:   []string{"snow", "rain"},
+		},
+		"no match": {
+			filter: vocab.HistoryFilter{Word: "sleet"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			history, err := store.GetHistory(t.Context(), tt.filter)
+			require.NoError(t, err)
+			var got []string
+			for _, entry := range history {
+				if entry.Import != nil {
+					assert.Equal(t, laptop, entry.Import)
+					got = append(got, entry.Word+" "+entry.Import.Source)
+					continue
+				}
+				got = append(got, entry.Word)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestStore_EventsSince(t *testing.T) {
+	store := newClockedStore(t, time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	_, err := store.AddWordsToList(t.Context(), []string{"snow"}, "en")
+	require.NoError(t, err)
+	_, err = store.AddEvents(t.Context(), "/sync", []vocab.Event{
+		{ID: "01J3XYZ1", Type: vocab.EventTypeAdd, Word: "rain", Language: "en", Timestamp: 100},
+	})
+	require.NoError(t, err)
+	_, err = store.AddEvents(t.Context(), "laptop.jsonl", []vocab.Event{
+		{ID: "01J3XYZ2", Type: vocab.EventTypeAdd, Word: "hail", Language: "en", Timestamp: 100},
+	})
+	require.NoError(t, err)
+
+	events, last, err := store.EventsSince(t.Context(), 0, "/sync")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), last)
+	require.Len(t, events, 2, "events imported from the excluded source are left out")
+	assert.Equal(t, "snow", events[0].Word)
+	assert.Equal(t, "hail", events[1].Word)
+
+	events, last, err = store.EventsSince(t.Context(), 2, "/sync")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), last)
+	require.Len(t, events, 1)
+	assert.Equal(t, "hail", events[0].Word)
+
+	events, last, err = store.EventsSince(t.Context(), 3, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), last)
+	assert.Empty(t, events)
+
+	// Vacuuming keeps the order events were stored in
+	require.NoError(t, store.Vacuum(t.Context()))
+	events, _, err = store.EventsSince(t.Context(), 1, "")
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "rain", events[0].Word)
+}
+
+func TestStore_SyncCursor(t *testing.T) {
+	db := newTestDB(t)
+	defer closeAndAssertError(t, db)
+	store, err := NewStore(t.Context(), db)
+	require.NoError(t, err)
+
+	id, err := store.DeviceID(t.Context())
+	require.NoError(t, err)
+	assert.NotEmpty(t, id)
+	again, err := store.DeviceID(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, id, again, "the device keeps its ID")
+
+	cursor, err := store.SyncCursor(t.Context(), "dir:/sync")
+	require.NoError(t, err)
+	assert.Empty(t, cursor)
+
+	require.NoError(t, store.SetSyncCursor(t.Context(), "dir:/sync", "12"))
+	require.NoError(t, store.SetSyncCursor(t.Context(), "dir:/sync", "42"))
+	require.NoError(t, store.SetSyncCursor(t.Context(), "device_id", "7"))
+	cursor, err = store.SyncCursor(t.Context(), "dir:/sync")
+	require.NoError(t, err)
+	assert.Equal(t, "42", cursor)
+
+	again, err = store.DeviceID(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, id, again, "cursors are kept apart from the device ID")
+}
+
+func getVocabList(t testing.TB, db *sql.DB) []string {
+	t.Helper()
+	rows, err := db.QueryContext(t.Context(), `SELECT word FROM vocab ORDER BY word`)
+	require.NoError(t, err)
+	var list []string
+	for rows.Next() {
+		var word string
+		require.NoError(t, rows.Scan(&word))
+		list = append(list, word)
+	}
+	return list
+}
+
+// migrateTo applies db migrations up to and including the given version, allowing data to be seeded in an older schema.
+func migrateTo(t testing.TB, db *sql.DB, version int64) {
+	t.Helper()
+	goose.SetLogger(goose.NopLogger())
+	goose.SetBaseFS(embedMigrations)
+	require.NoError(t, goose.SetDialect("sqlite3"))
+	require.NoError(t, goose.UpToContext(t.Context(), db, "migrations", version))
+}
+
+func TestMigrateEntries(t *testing.T) {
+	db := newTestDB(t)
+	defer closeAndAssertError(t, db)
+	migrateTo(t, db, 5)
+
+	_, err := db.ExecContext(t.Context(), `INSERT INTO words (word, phonetic) VALUES ('snow', '/snəʊ/');
+INSERT INTO definitions (word_id, definition, part_of_speech) VALUES (last_insert_rowid(), 'def 1', 'noun');`)
+	require.NoError(t, err)
+
+	store, err := NewStore(t.Context(), db)
+	require.NoError(t, err)
+
+	entries, err := store.LookupWord(t.Context(), "snow", "en")
+	require.NoError(t, err)
+	assert.Equal(t, []dictionary.Entry{{
+		Phonetic:    "/snəʊ/",
+		Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "def 1"}},
+	}}, entries)
+}
+
+func TestStore_CachedEntries(t *testing.T) {
+	db := newTestDB(t)
+	defer closeAndAssertError(t, db)
+	store, err := NewStore(t.Context(), db)
+	require.NoError(t, err)
+
+	web := dictionary.Entry{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "a present"}}, SourceURLs: []string{"https://example.com/gift"}}
+	wiki := dictionary.Entry{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "something given"}}}
+	require.NoError(t, store.SaveWord(t.Context(), "gift", "en", []dictionary.Entry{web}))
+	require.NoError(t, store.WithProvider("wiktionary").SaveWord(t.Context(), "gift", "en", []dictionary.Entry{wiki}))
+	require.NoError(t, store.WithProvider("offline").SaveWord(t.Context(), "gift", "en", []dictionary.Entry{wiki}))
+	require.NoError(t, store.SaveWord(t.Context(), "gift", "de", []dictionary.Entry{wiki}))
+
+	got, err := store.CachedEntries(t.Context(), "Gift", "en")
+	require.NoError(t, err)
+	web.Provider, wiki.Provider = "freedictionary", "wiktionary"
+	assert.Equal(t, []dictionary.Entry{web, wiki}, got)
+
+	got, err = store.CachedEntries(t.Context(), "snow", "en")
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestStore_Search(t *testing.T) {
+	db := newTestDB(t)
+	defer closeAndAssertError(t, db)
+	store, err := NewStore(t.Context(), db)
+	require.NoError(t, err)
+
+	require.NoError(t, store.SaveWord(t.Context(), "sleet", "en", []dictionary.Entry{{Definitions: []dictionary.Definition{
+		{PartOfSpeech: "noun", Meaning: "Rain mixed with snow or hail."},
+		{PartOfSpeech: "verb", Meaning: "To fall as sleet."},
+	}}}))
+	require.NoError(t, store.WithProvider("wiktionary").SaveWord(t.Context(), "sleet", "en", []dictionary.Entry{{Definitions: []dictionary.Definition{
+		{PartOfSpeech: "noun", Meaning: "Rain mixed with snow or hail."},
+	}}}))
+	require.NoError(t, store.SaveWord(t.Context(), "snow", "en", []dictionary.Entry{{Definitions: []dictionary.Definition{
+		{PartOfSpeech: "noun", Meaning: "Frozen water falling as flakes."},
+		{PartOfSpeech: "verb", Meaning: "To have snow fall from the sky."},
+	}}}))
+	require.NoError(t, store.WithProvider("offline").SaveWord(t.Context(), "schnee", "de", []dictionary.Entry{{Definitions: []dictionary.Definition{
+		{PartOfSpeech: "noun", Meaning: "Snow; frozen water."},
+	}}}))
+	_, err = store.AddWordsToList(t.Context(), []string{"snow"}, "en")
+	require.NoError(t, err)
+
+	words := func(results []dictionary.SearchResult) []string {
+		var words []string
+		for _, r := range results {
+			words = append(words, r.Word)
+		}
+		return words
+	}
+
+	t.Run("ranked by best matching definition", func(t *testing.T) {
+		got, err := store.Search(t.Context(), dictionary.SearchQuery{Phrase: "frozen water flakes"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"snow", "schnee"}, words(got))
+		assert.Equal(t, []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "Frozen water falling as flakes."}}, got[0].Definitions)
+		assert.Greater(t, got[0].Score, got[1].Score)
+	})
+
+	t.Run("senses cached by several providers are given once", func(t *testing.T) {
+		got, err := store.Search(t.Context(), dictionary.SearchQuery{Phrase: "rain mixed with hail", Language: "en"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"sleet"}, words(got))
+		assert.Len(t, got[0].Definitions, 1)
+	})
+
+	t.Run("words are stemmed", func(t *testing.T) {
+		got, err := store.Search(t.Context(), dictionary.SearchQuery{Phrase: "falls", Language: "en"})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"sleet", "snow"}, words(got))
+	})
+
+	t.Run("part of speech", func(t *testing.T) {
+		got, err := store.Search(t.Context(), dictionary.SearchQuery{Phrase: "snow", PartOfSpeech: "Verb"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"snow"}, words(got))
+		assert.Equal(t, []dictionary.Definition{{PartOfSpeech: "verb", Meaning: "To have snow fall from the sky."}}, got[0].Definitions)
+	})
+
+	t.Run("list only", func(t *testing.T) {
+		got, err := store.Search(t.Context(), dictionary.SearchQuery{Phrase: "snow", ListOnly: true})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"snow"}, words(got))
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		got, err := store.Search(t.Context(), dictionary.SearchQuery{Phrase: "snow", Limit: 2})
+		require.NoError(t, err)
+		assert.Len(t, got, 2)
+	})
+
+	t.Run("query syntax is ignored", func(t *testing.T) {
+		got, err := store.Search(t.Context(), dictionary.SearchQuery{Phrase: `"frozen" AND (NEAR* -water`})
+		require.NoError(t, err)
+		assert.NotEmpty(t, got)
+	})
+
+	t.Run("nothing to search for", func(t *testing.T) {
+		_, err := store.Search(t.Context(), dictionary.SearchQuery{Phrase: " ?! "})
+		assert.Error(t, err)
+	})
+
+	t.Run("replaced definitions aren't found", func(t *testing.T) {
+		require.NoError(t, store.SaveWord(t.Context(), "snow", "en", []dictionary.Entry{{Definitions: []dictionary.Definition{
+			{PartOfSpeech: "noun", Meaning: "Television static."},
+		}}}))
+		got, err := store.Search(t.Context(), dictionary.SearchQuery{Phrase: "flakes"})
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+}
+
+func TestStore_CustomDefinitions(t *testing.T) {
+	db := newTestDB(t)
+	defer closeAndAssertError(t, db)
+	store, err := NewStore(t.Context(), db)
+	require.NoError(t, err)
+
+	got, err := store.CustomDefinitions(t.Context(), "k8s", "en")
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	defs := []dictionary.Definition{
+		{PartOfSpeech: "noun", Meaning: "Kubernetes."},
+		{Meaning: "A container orchestrator."},
+	}
+	require.NoError(t, store.SetCustomDefinitions(t.Context(), "K8s", "en", defs))
+
+	got, err = store.WithProvider("wiktionary").CustomDefinitions(t.Context(), "k8s", "en")
+	require.NoError(t, err)
+	assert.Equal(t, defs, got, "custom definitions are shared by all providers")
+
+	got, err = store.CustomDefinitions(t.Context(), "k8s", "de")
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	// Custom definitions aren't part of the cache
+	require.NoError(t, store.SaveWord(t.Context(), "k8s", "en", []dictionary.Entry{{Definitions: []dictionary.Definition{{Meaning: "def 1"}}}}))
+	require.NoError(t, store.PurgeCache(t.Context()))
+	got, err = store.CustomDefinitions(t.Context(), "k8s", "en")
+	require.NoError(t, err)
+	assert.Equal(t, defs, got)
+
+	require.NoError(t, store.SetCustomDefinitions(t.Context(), "k8s", "en", defs[1:]))
+	got, err = store.CustomDefinitions(t.Context(), "k8s", "en")
+	require.NoError(t, err)
+	assert.Equal(t, defs[1:], got)
+
+	require.NoError(t, store.SetCustomDefinitions(t.Context(), "k8s", "en", nil))
+	got, err = store.CustomDefinitions(t.Context(), "k8s", "en")
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	assert.Error(t, store.SetCustomDefinitions(t.Context(), "k8s", "en", []dictionary.Definition{{PartOfSpeech: "noun", Meaning: " "}}))
+	assert.Error(t, store.SetCustomDefinitions(t.Context(), " ", "en", defs))
+}
+
+func TestStore_ExportCache(t *testing.T) {
+	db := newTestDB(t)
+	defer closeAndAssertError(t, db)
+	store, err := NewStore(t.Context(), db)
+	require.NoError(t, err)
+
+	snow := []dictionary.Entry{{
+		Phonetic:    "/snəʊ/",
+		Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "Frozen rain.", Example: "Snow fell."}},
+		SourceURLs:  []string{"https://example.com/snow"},
+	}}
+	gift := []dictionary.Entry{{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "poison"}}}}
+	require.NoError(t, store.SaveWord(t.Context(), "snow", "en", snow))
+	require.NoError(t, store.WithProvider("wiktionary").SaveWord(t.Context(), "gift", "de", gift))
+	require.NoError(t, store.WithProvider("offline").SaveWord(t.Context(), "sleet", "en", gift))
+	_, err = db.ExecContext(t.Context(), `UPDATE words SET fetched_at = 100 WHERE word = 'snow';
+UPDATE words SET fetched_at = 0 WHERE word = 'gift';`)
+	require.NoError(t, err)
+
+	var got []bundle.Word
+	require.NoError(t, store.ExportCache(t.Context(), func(w bundle.Word) error {
+		got = append(got, w)
+		return nil
+	}))
+	assert.Equal(t, []bundle.Word{
+		{Word: "snow", Language: "en", Provider: "freedictionary", FetchedAt: time.Unix(100, 0), Entries: snow},
+		{Word: "gift", Language: "de", Provider: "wiktionary", Entries: gift},
+	}, got)
+}
+
+func TestStore_ImportCache(t *testing.T) {
+	cached := []dictionary.Entry{{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "cached"}}}}
+	imported := []dictionary.Entry{{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "imported"}}}}
+	older, newer := time.Unix(100, 0), time.Unix(200, 0)
+
+	tests := map[string]struct {
+		policy    bundle.ConflictPolicy
+		fetchedAt time.Time
+		want      []dictionary.Entry
+		wantCount int
+		wantFetch time.Time
+	}{
+		"keep newer with newer import": {policy: bundle.KeepNewer, fetchedAt: newer, want: imported, wantCount: 2, wantFetch: newer},
+		"keep newer with older import": {policy: bundle.KeepNewer, fetchedAt: older, want: cached, wantCount: 1, wantFetch: time.Unix(150, 0)},
+		"overwrite":                    {policy: bundle.Overwrite, fetchedAt: older, want: imported, wantCount: 2, wantFetch: older},
+		"skip":                         {policy: bundle.Skip, fetchedAt: newer, want: cached, wantCount: 1, wantFetch: time.Unix(150, 0)},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db := newTestDB(t)
+			defer closeAndAssertError(t, db)
+			store, err := NewStore(t.Context(), db)
+			require.NoError(t, err)
+
+			require.NoError(t, store.SaveWord(t.Context(), "snow", "en", cached))
+			_, err = db.ExecContext(t.Context(), `UPDATE words SET fetched_at = 150`)
+			require.NoError(t, err)
+			require.NoError(t, store.SaveNotFound(t.Context(), "rain", "en"))
+
+			n, err := store.ImportCache(t.Context(), []bundle.Word{
+				{Word: "Snow", Language: "en", Provider: "freedictionary", FetchedAt: tt.fetchedAt, Entries: imported},
+				{Word: "rain", Language: "en", Provider: "freedictionary", Entries: imported},
+			}, tt.policy)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCount, n)
+
+			entries, err := store.LookupWord(t.Context(), "snow", "en")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, entries)
+			fetchedAt, err := store.FetchedAt(t.Context(), "snow", "en")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFetch, fetchedAt)
+
+			// Words which weren't cached are always imported, keeping unknown fetch times
+			entries, err = store.LookupWord(t.Context(), "rain", "en")
+			require.NoError(t, err)
+			assert.Equal(t, imported, entries)
+			fetchedAt, err = store.FetchedAt(t.Context(), "rain", "en")
+			require.NoError(t, err)
+			assert.True(t, fetchedAt.IsZero())
+			notFoundAt, err := store.NotFoundAt(t.Context(), "rain", "en")
+			require.NoError(t, err)
+			assert.True(t, notFoundAt.IsZero(), "imported words are no longer remembered as not found")
+		})
+	}
+
+	t.Run("offline dictionary", func(t *testing.T) {
+		db := newTestDB(t)
+		defer closeAndAssertError(t, db)
+		store, err := NewStore(t.Context(), db)
+		require.NoError(t, err)
+
+		_, err = store.ImportCache(t.Context(), []bundle.Word{
+			{Word: "snow", Language: "en", Provider: "freedictionary", Entries: imported},
+			{Word: "sleet", Language: "en", Provider: "offline", Entries: imported},
+		}, bundle.Overwrite)
+		require.Error(t, err)
+
+		ok, err := store.ContainsWord(t.Context(), "snow", "en")
+		require.NoError(t, err)
+		assert.False(t, ok, "nothing is imported from an invalid bundle")
+	})
+}
+
+func TestStore_CacheStats(t *testing.T) {
+	db := newTestDB(t)
+	defer closeAndAssertError(t, db)
+	store, err := NewStore(t.Context(), db)
+	require.NoError(t, err)
+
+	stats, err := store.CacheStats(t.Context())
+	require.NoError(t, err)
+	assert.Zero(t, stats.Words)
+	assert.Zero(t, stats.Oldest)
+	assert.Zero(t, stats.Newest)
+
+	entries := []dictionary.Entry{{Definitions: []dictionary.Definition{
+		{PartOfSpeech: "noun", Meaning: "def 1"},
+		{PartOfSpeech: "verb", Meaning: "def 2"},
+	}}}
+	for _, word := range []string{"snow", "rain", "hail"} {
+		require.NoError(t, store.SaveWord(t.Context(), word, "en", entries))
+	}
+	require.NoError(t, store.WithProvider("offline").SaveWord(t.Context(), "sleet", "en", entries))
+	require.NoError(t, store.SaveNotFound(t.Context(), "snoww", "en"))
+	_, err = db.ExecContext(t.Context(), `UPDATE words SET fetched_at = 100 WHERE word = 'rain';
+UPDATE words SET fetched_at = 0 WHERE word = 'hail';
+UPDATE words SET fetched_at = 50 WHERE word = 'sleet';`)
+	require.NoError(t, err)
+
+	stats, err = store.CacheStats(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.Words)
+	assert.Equal(t, 6, stats.Definitions)
+	assert.Equal(t, 1, stats.NotFound)
+	assert.Positive(t, stats.SizeBytes)
+	assert.Equal(t, dictionary.CachedWord{Word: "rain", Language: "en", Provider: "freedictionary", FetchedAt: time.Unix(100, 0)}, stats.Oldest)
+	assert.Equal(t, "snow", stats.Newest.Word)
+}
+
+func TestStore_RemoveCachedWords(t *testing.T) {
+	db := newTestDB(t)
+	defer closeAndAssertError(t, db)
+	store, err := NewStore(t.Context(), db)
+	require.NoError(t, err)
+
+	entries := []dictionary.Entry{{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "def 1", Synonyms: []string{"blow"}}}}}
+	require.NoError(t, store.SaveWord(t.Context(), "snow", "en", entries))
+	require.NoError(t, store.WithProvider("wiktionary").SaveWord(t.Context(), "snow", "en", entries))
+	require.NoError(t, store.WithProvider("offline").SaveWord(t.Context(), "snow", "en", entries))
+	require.NoError(t, store.SaveWord(t.Context(), "snow", "de", entries))
+	require.NoError(t, store.SaveWord(t.Context(), "rain", "en", entries))
+	require.NoError(t, store.SaveNotFound(t.Context(), "snoww", "en"))
+
+	removed, err := store.RemoveCachedWords(t.Context(), []string{"Snow", "snoww", "hail"}, "en")
+	require.NoError(t, err)
+	assert.Equal(t, 2, removed)
+
+	words, err := store.CachedWords(t.Context())
+	require.NoError(t, err)
+	require.Len(t, words, 2)
+	assert.Equal(t, "rain", words[0].Word)
+	assert.Equal(t, "snow", words[1].Word)
+	assert.Equal(t, "de", words[1].Language)
+
+	ok, err := store.WithProvider("offline").ContainsWord(t.Context(), "snow", "en")
+	require.NoError(t, err)
+	assert.True(t, ok, "the offline dictionary should be kept")
+
+	notFoundAt, err := store.NotFoundAt(t.Context(), "snoww", "en")
+	require.NoError(t, err)
+	assert.True(t, notFoundAt.IsZero())
+}
+
+func TestStore_PurgeCache(t *testing.T) {
+	db := newTestDB(t)
+	defer closeAndAssertError(t, db)
+	store, err := NewStore(t.Context(), db)
+	require.NoError(t, err)
+
+	entries := []dictionary.Entry{{
+		Phonetics:   []dictionary.Phonetic{{Text: "/snəʊ/"}},
+		Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "def 1", Synonyms: []string{"blow"}}},
+		SourceURLs:  []string{"https://example.com/snow"},
+	}}
+	require.NoError(t, store.SaveWord(t.Context(), "snow", "en", entries))
+	require.NoError(t, store.WithProvider("wiktionary").SaveWord(t.Context(), "rain", "en", entries))
+	require.NoError(t, store.WithProvider("offline").SaveWord(t.Context(), "snow", "en", entries))
+	require.NoError(t, store.SaveNotFound(t.Context(), "snoww", "en"))
+	_, err = store.AddWordsToList(t.Context(), []string{"snow"}, "en")
+	require.NoError(t, err)
+
+	require.NoError(t, store.PurgeCache(t.Context()))
+
+	stats, err := store.CacheStats(t.Context())
+	require.NoError(t, err)
+	assert.Zero(t, stats.Words)
+	assert.Zero(t, stats.NotFound)
+	for _, table := range wordTables {
+		var count int
+		require.NoError(t, db.QueryRowContext(t.Context(), `SELECT count() FROM `+table).Scan(&count))
+		assert.Equal(t, 1, count, "only the offline dictionary's rows should be left in %s", table)
+	}
+	assert.Equal(t, []string{"snow"}, getVocabList(t, db), "the vocab list should be kept")
+}
+
+func TestStore_Vacuum(t *testing.T) {
+	db := newTestDB(t)
+	defer closeAndAssertError(t, db)
+	store, err := NewStore(t.Context(), db)
+	require.NoError(t, err)
+
+	entries := []dictionary.Entry{{Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: strings.Repeat("long definition ", 100)}}}}
+	for i := range 200 {
+		require.NoError(t, store.SaveWord(t.Context(), fmt.Sprintf("word%d", i), "en", entries))
+	}
+	require.NoError(t, store.PurgeCache(t.Context()))
+	before, err := store.CacheStats(t.Context())
+	require.NoError(t, err)
+
+	require.NoError(t, store.Vacuum(t.Context()))
+
+	after, err := store.CacheStats(t.Context())
+	require.NoError(t, err)
+	assert.Less(t, after.SizeBytes, before.SizeBytes)
+}
+
+func TestMigrateWordFetchedAt(t *testing.T) {
+	db := newTestDB(t)
+	defer closeAndAssertError(t, db)
+	migrateTo(t, db, 9)
+
+	_, err := db.ExecContext(t.Context(), `INSERT INTO words (word) VALUES ('snow');
+INSERT INTO definitions (word_id, definition, part_of_speech) VALUES (last_insert_rowid(), 'def 1', 'noun');`)
+	require.NoError(t, err)
+
+	store, err := NewStore(t.Context(), db)
+	require.NoError(t, err)
+
+	got, err := store.FetchedAt(t.Context(), "snow", "en")
+	require.NoError(t, err)
+	assert.True(t, got.IsZero(), "words cached before the migration have an unknown fetch time")
+}
+
+func TestMigrateDefinitionsFTS(t *testing.T) {
+	db := newTestDB(t)
+	defer closeAndAssertError(t, db)
+	migrateTo(t, db, 11)
+
+	_, err := db.ExecContext(t.Context(), `INSERT INTO words (word) VALUES ('snow');
+INSERT INTO definitions (word_id, definition, part_of_speech) VALUES (last_insert_rowid(), 'Frozen rain.', 'noun');`)
+	require.NoError(t, err)
+
+	store, err := NewStore(t.Context(), db)
+	require.NoError(t, err)
+
+	got, err := store.Search(t.Context(), dictionary.SearchQuery{Phrase: "frozen"})
+	require.NoError(t, err)
+	require.Len(t, got, 1, "definitions cached before the migration are indexed")
+	assert.Equal(t, "snow", got[0].Word)
+}
+
+func TestMigrateVocabEventClock(t *testing.T) {
+	db := newTestDB(t)
+	defer closeAndAssertError(t, db)
+	migrateTo(t, db, 13)
+
+	// Both events were written in the same second, but the remove's ULID is a millisecond later
+	_, err := db.ExecContext(t.Context(), `INSERT INTO vocab (word, language) VALUES ('snow', 'en');
+INSERT INTO vocab_events (id, type, word, language, timestamp) VALUES ('01HQWY5CGQ0000000000000000', 'remove', 'snow', 'en', 1709294400);
+INSERT INTO vocab_events (id, type, word, language, timestamp) VALUES ('01HQWY5CGP0000000000000000', 'add', 'snow', 'en', 1709294400);
+INSERT INTO vocab_events (id, type, word, language, timestamp) VALUES ('01J3XYZ1', 'add', 'rain', 'en', 100);`)
+	require.NoError(t, err)
+
+	store, err := NewStore(t.Context(), db)
+	require.NoError(t, err)
+
+	events, err := store.GetEvents(t.Context())
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, "01J3XYZ1", events[0].ID)
+	assert.Equal(t, vocab.Clock{Wall: 100_000}, events[0].Clock, "events without a ULID are clocked by their timestamp")
+	assert.Equal(t, vocab.EventTypeAdd, events[1].Type)
+	assert.Equal(t, vocab.EventTypeRemove, events[2].Type)
+	assert.Equal(t, int64(1709294400023), events[2].Clock.Wall, "events are clocked by the time in their ULID")
+
+	// The list is replayed with the add before the remove
+	list, err := store.GetWordsInList(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []vocab.Entry{{Word: "rain", Language: "en"}}, list)
+}
+
+func TestMigrateVocabLanguageKey(t *testing.T) {
+	db := newTestDB(t)
+	defer closeAndAssertError(t, db)
+	migrateTo(t, db, 17)
+
+	// The list could only hold one language of a word, so the German snow was lost when the English one was added
+	_, err := db.ExecContext(t.Context(), `INSERT INTO vocab (word, language) VALUES ('snow', 'en');
+INSERT INTO vocab_events (id, type, word, language, timestamp, clock_wall, clock_logical, seq) VALUES
+	('01HQWY5CGP0000000000000000', 'add', 'Snow', 'de', 1709294400, 1709294400022, 0, 1),
+	('01HQWY5CGQ0000000000000000', 'add', 'snow', 'en', 1709294400, 1709294400023, 0, 2),
+	('01HQWY5CGQ0000000000000001', 'add', 'rain', 'en', 1709294400, 1709294400023, 1, 3),
+	('01HQWY5CGQ0000000000000002', 'remove', 'rain', 'en', 1709294400, 1709294400023, 2, 4);`)
+	require.NoError(t, err)
+
+	store, err := NewStore(t.Context(), db)
+	require.NoError(t, err)
+
+	list, err := store.GetWordsInList(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []vocab.Entry{{Word: "snow", Language: "de"}, {Word: "snow", Language: "en"}}, list)
+}
+
+func TestMigrateWordLanguage(t *testing.T) {
+	db := newTestDB(t)
+	defer closeAndAssertError(t, db)
+	migrateTo(t, db, 7)
+
+	_, err := db.ExecContext(t.Context(), `INSERT INTO words (word) VALUES ('snow');
+INSERT INTO entries (word_id, position, phonetic) VALUES (last_insert_rowid(), 0, '/snəʊ/');
+INSERT INTO definitions (word_id, definition, part_of_speech) VALUES ((SELECT id FROM words WHERE word = 'snow'), 'def 1', 'noun');
+INSERT INTO vocab (word) VALUES ('snow');
+INSERT INTO vocab_events (id, type, word, timestamp) VALUES ('01J3XYZ1', 'add', 'snow', 100);`)
+	require.NoError(t, err)
+
+	store, err := NewStore(t.Context(), db)
+	require.NoError(t, err)
+
+	entries, err := store.LookupWord(t.Context(), "snow", "en")
+	require.NoError(t, err)
+	assert.Equal(t, []dictionary.Entry{{
+		Phonetic:    "/snəʊ/",
+		Definitions: []dictionary.Definition{{PartOfSpeech: "noun", Meaning: "def 1"}},
+	}}, entries)
+
+	list, err := store.GetWordsInList(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []vocab.Entry{{Word: "snow", Language: "en"}}, list)
+
+	events, err := store.GetEvents(t.Context())
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "en", events[0].Language)
+
+	// The rebuilt words table still cascades deletes
+	_, err = db.ExecContext(t.Context(), `DELETE FROM words`)
+	require.NoError(t, err)
+	var defCount int
+	require.NoError(t, db.QueryRowContext(t.Context(), `SELECT count() FROM definitions`).Scan(&defCount))
+	assert.Zero(t, defCount)
 }
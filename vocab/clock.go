@@ -0,0 +1,55 @@
+package vocab
+
+import (
+	"cmp"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// Clock is a hybrid logical clock reading. It follows the wall clock, but never goes backwards from the latest event a
+// device has seen, so an event is always ordered after every event known to the device which wrote it, even when the
+// clocks of devices disagree.
+type Clock struct {
+	// Wall is a unix time in milliseconds
+	Wall int64 `json:"wall"`
+	// Logical orders events with the same Wall
+	Logical int64 `json:"logical"`
+}
+
+// IsZero reports whether the clock is unset, as it is for events written before clocks were recorded
+func (c Clock) IsZero() bool {
+	return c == Clock{}
+}
+
+// Tick returns the clock of an event happening at now, after the event with clock c
+func (c Clock) Tick(now time.Time) Clock {
+	wall := now.UnixMilli()
+	if wall > c.Wall {
+		return Clock{Wall: wall}
+	}
+	return Clock{Wall: c.Wall, Logical: c.Logical + 1}
+}
+
+// Compare returns -1 if c is before o, 1 if it's after, and 0 if they're the same
+func (c Clock) Compare(o Clock) int {
+	return cmp.Or(cmp.Compare(c.Wall, o.Wall), cmp.Compare(c.Logical, o.Logical))
+}
+
+// OrderClock returns the clock the event is ordered by. Events written before clocks were recorded are given one from
+// the time in their ULID, or their timestamp if their ID isn't a ULID, so every device orders them the same way.
+func (e Event) OrderClock() Clock {
+	if !e.Clock.IsZero() {
+		return e.Clock
+	}
+	if id, err := ulid.ParseStrict(e.ID); err == nil {
+		return Clock{Wall: int64(id.Time())}
+	}
+	return Clock{Wall: e.Timestamp * 1000}
+}
+
+// Compare orders events by their clocks, breaking ties by ID. The order is the same on every device, and causal: an
+// event comes after every event its device had seen when writing it.
+func Compare(a, b Event) int {
+	return cmp.Or(a.OrderClock().Compare(b.OrderClock()), cmp.Compare(a.ID, b.ID))
+}
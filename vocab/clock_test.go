@@ -0,0 +1,33 @@
+package vocab
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClock_Tick(t *testing.T) {
+	now := time.UnixMilli(1000)
+
+	assert.Equal(t, Clock{Wall: 1000}, Clock{}.Tick(now), "follows the wall clock")
+	assert.Equal(t, Clock{Wall: 1000}, Clock{Wall: 900, Logical: 4}.Tick(now), "resets the counter when the wall clock moves on")
+	assert.Equal(t, Clock{Wall: 1000, Logical: 1}, Clock{Wall: 1000}.Tick(now), "counts events in the same millisecond")
+	assert.Equal(t, Clock{Wall: 5000, Logical: 3}, Clock{Wall: 5000, Logical: 2}.Tick(now), "never goes backwards")
+}
+
+func TestEvent_OrderClock(t *testing.T) {
+	assert.Equal(t, Clock{Wall: 7, Logical: 1}, Event{ID: "01HQWY5CGP0000000000000000", Clock: Clock{Wall: 7, Logical: 1}}.OrderClock())
+	assert.Equal(t, Clock{Wall: 1709294400022}, Event{ID: "01HQWY5CGP0000000000000000", Timestamp: 1709294400}.OrderClock())
+	assert.Equal(t, Clock{Wall: 100_000}, Event{ID: "01J3XYZ1", Timestamp: 100}.OrderClock())
+}
+
+func TestCompare(t *testing.T) {
+	a := Event{ID: "a", Clock: Clock{Wall: 1000}}
+	b := Event{ID: "b", Clock: Clock{Wall: 1000}}
+	c := Event{ID: "0", Clock: Clock{Wall: 1000, Logical: 1}}
+
+	assert.Negative(t, Compare(a, b), "ties are broken by ID")
+	assert.Negative(t, Compare(b, c), "clocks come before IDs")
+	assert.Zero(t, Compare(a, a))
+}
@@ -11,5 +11,14 @@ type Event struct {
 	ID        string    `json:"id"`
 	Type      EventType `json:"type"`
 	Word      string    `json:"word"`
+	Language  string    `json:"language,omitempty"`
 	Timestamp int64     `json:"timestamp"`
+	// Clock orders the event causally. It's zero for events written before clocks were recorded.
+	Clock Clock `json:"clock,omitzero"`
+}
+
+// Entry is a word in the vocab list along with the language it was added in.
+type Entry struct {
+	Word     string
+	Language string
 }
@@ -0,0 +1,27 @@
+package vocab
+
+import "time"
+
+// Import is a batch of events added from elsewhere, such as a file of events exported on another machine
+type Import struct {
+	// Source names where the events came from
+	Source string    `json:"source"`
+	At     time.Time `json:"at"`
+}
+
+// HistoryEntry is an event in the history of the vocab list, along with the import it came from. Import is nil for
+// events written on this machine.
+type HistoryEntry struct {
+	Event
+	Import *Import `json:"import,omitempty"`
+}
+
+// HistoryFilter chooses the events of the vocab list's history. Zero fields match every event.
+type HistoryFilter struct {
+	// Word matches events of a word, regardless of case
+	Word string
+	// Since and Until match events which happened in [Since, Until)
+	Since time.Time
+	Until time.Time
+	Type  EventType
+}